@@ -0,0 +1,24 @@
+// Package timpani executes Timpani activities which aren't wrapped (yet)
+// by the Timpani API package, with the same preconfigured Temporal options.
+package timpani
+
+import (
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/timpani-api/pkg/temporal"
+)
+
+// ExecuteActivity requests the Timpani worker to execute one of its activities which
+// isn't wrapped (yet) by the Timpani API package, with the same preconfigured Temporal options.
+func ExecuteActivity[T any](ctx workflow.Context, name string, req any) (*T, error) {
+	opts := temporal.ActivityOptions
+	if opts == nil {
+		opts = temporal.DefaultActivityOptions("timpani")
+	}
+
+	resp := new(T)
+	if err := workflow.ExecuteActivity(workflow.WithActivityOptions(ctx, *opts), name, req).Get(ctx, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+package activities
+
+import (
+	"errors"
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// commitsCommentRequest is based on:
+//   - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-comments-post
+//   - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-comments-comment-id-put
+//   - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-comments-comment-id-delete
+type commitsCommentRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Workspace string `json:"workspace"`
+	RepoSlug  string `json:"repo_slug"`
+	Commit    string `json:"commit"`
+
+	CommentID string `json:"comment_id,omitempty"`
+	Markdown  string `json:"text,omitempty"`
+	ParentID  string `json:"parent_id,omitempty"`
+}
+
+func CreateCommitComment(ctx workflow.Context, thrippyID, workspace, repo, commit, parentID, msg string) (string, error) {
+	if thrippyID == "" {
+		return "", errors.New("missing user authentication credentials")
+	}
+
+	req := commitsCommentRequest{
+		ThrippyLinkID: thrippyID, Workspace: workspace, RepoSlug: repo, Commit: commit,
+		Markdown: msg, ParentID: parentID, // Optional.
+	}
+	resp, err := timpani.ExecuteActivity[bitbucket.Comment](ctx, "bitbucket.commits.createComment", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to create Bitbucket commit comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("workspace", workspace), slog.String("repo", repo),
+			slog.String("commit", commit), slog.String("parent_id", parentID))
+		return "", err
+	}
+
+	return resp.Links["html"].HRef, nil
+}
+
+func UpdateCommitComment(ctx workflow.Context, thrippyID, workspace, repo, commit, commentID, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req := commitsCommentRequest{
+		ThrippyLinkID: thrippyID, Workspace: workspace, RepoSlug: repo, Commit: commit,
+		CommentID: commentID, Markdown: msg,
+	}
+	if _, err := timpani.ExecuteActivity[bitbucket.Comment](ctx, "bitbucket.commits.updateComment", req); err != nil {
+		logger.From(ctx).Error("failed to update Bitbucket commit comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("workspace", workspace), slog.String("repo", repo),
+			slog.String("commit", commit), slog.String("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
+
+func DeleteCommitComment(ctx workflow.Context, thrippyID, workspace, repo, commit, commentID string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req := commitsCommentRequest{ThrippyLinkID: thrippyID, Workspace: workspace, RepoSlug: repo, Commit: commit, CommentID: commentID}
+	if _, err := timpani.ExecuteActivity[any](ctx, "bitbucket.commits.deleteComment", req); err != nil {
+		logger.From(ctx).Error("failed to delete Bitbucket commit comment", slog.Any("error", err),
+			slog.String("workspace", workspace), slog.String("repo", repo),
+			slog.String("commit", commit), slog.String("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
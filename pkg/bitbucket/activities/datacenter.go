@@ -0,0 +1,487 @@
+package activities
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// dataCenterRequest is the common input of Timpani's Bitbucket Data Center activities, based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-pull-requests/
+type dataCenterRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+	BaseURL       string `json:"base_url"`
+	ProjectKey    string `json:"project_key"`
+	RepoSlug      string `json:"repo_slug"`
+	PullRequestID int    `json:"pull_request_id"`
+
+	CommentID  int    `json:"comment_id,omitempty"`
+	ParentID   int    `json:"parent_id,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	StrategyID string `json:"strategy_id,omitempty"`
+}
+
+// pagedResponse is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/intro/#pagination
+type pagedResponse[T any] struct {
+	Values []T `json:"values"`
+}
+
+func newDataCenterRequest(ctx workflow.Context, thrippyID, url string) (*dataCenterRequest, error) {
+	u := datacenter.ParseURL(url)
+	if u == nil {
+		logger.From(ctx).Error("failed to parse Bitbucket Data Center PR's URL", slog.String("url", url))
+		return nil, errors.New("invalid Bitbucket Data Center PR URL: " + url)
+	}
+
+	return &dataCenterRequest{
+		ThrippyLinkID: thrippyID,
+		BaseURL:       u.BaseURL,
+		ProjectKey:    u.ProjectKey,
+		RepoSlug:      u.RepoSlug,
+		PullRequestID: u.PRID,
+		CommentID:     u.CommentID,
+	}, nil
+}
+
+// GetDataCenterPullRequest allows the Thrippy link ID to be empty, even though it is encouraged to specify it.
+func GetDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL string) (*datacenter.PullRequest, error) {
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := timpani.ExecuteActivity[datacenter.PullRequest](ctx, "bitbucket.datacenter.pullrequests.get", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to get Bitbucket Data Center PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// CanAccessDataCenterPullRequest checks whether the given user has access to a PR. Unlike
+// [GetDataCenterPullRequest], API call failures here are not treated (i.e. logged) as server errors.
+func CanAccessDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL string) bool {
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return false
+	}
+
+	pr, err := timpani.ExecuteActivity[datacenter.PullRequest](ctx, "bitbucket.datacenter.pullrequests.get", req)
+	return err == nil && pr.ID > 0
+}
+
+// ListDataCenterCommits returns the commits of a PR, in reverse chronological order (like Bitbucket Cloud).
+func ListDataCenterCommits(ctx workflow.Context, thrippyID, prURL string) ([]datacenter.Commit, error) {
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := timpani.ExecuteActivity[pagedResponse[datacenter.Commit]](ctx, "bitbucket.datacenter.pullrequests.listCommits", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Bitbucket Data Center PR's commits", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return nil, err
+	}
+
+	return resp.Values, nil
+}
+
+// ListDataCenterChanges returns the files that were changed in a PR.
+func ListDataCenterChanges(ctx workflow.Context, thrippyID, prURL string) ([]datacenter.Change, error) {
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := timpani.ExecuteActivity[pagedResponse[datacenter.Change]](ctx, "bitbucket.datacenter.pullrequests.listChanges", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Bitbucket Data Center PR's changes", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return nil, err
+	}
+
+	return resp.Values, nil
+}
+
+// CreateDataCenterComment creates a new PR comment. If the given URL is a comment URL
+// (rather than a PR URL), the new comment is a reply to it. It returns the new comment's URL.
+func CreateDataCenterComment(ctx workflow.Context, thrippyID, url, msg string) (string, error) {
+	if thrippyID == "" {
+		return "", errors.New("missing user authentication credentials")
+	}
+
+	req, err := newDataCenterRequest(ctx, thrippyID, url)
+	if err != nil {
+		return "", err
+	}
+	req.ParentID, req.CommentID = req.CommentID, 0 // Optional.
+	req.Text = msg
+
+	resp, err := timpani.ExecuteActivity[datacenter.Comment](ctx, "bitbucket.datacenter.pullrequests.createComment", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to create Bitbucket Data Center PR comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("url", url))
+		return "", err
+	}
+
+	return datacenter.CommentURL(url, resp.ID), nil
+}
+
+// GetDataCenterComment allows the Thrippy link ID to be empty, even though it is encouraged to specify it.
+func GetDataCenterComment(ctx workflow.Context, thrippyID, commentURL string) (*datacenter.Comment, error) {
+	req, err := newDataCenterRequest(ctx, thrippyID, commentURL)
+	if err != nil {
+		return nil, err
+	}
+	if req.CommentID == 0 {
+		logger.From(ctx).Error("failed to parse Bitbucket Data Center PR comment's URL", slog.String("url", commentURL))
+		return nil, errors.New("invalid Bitbucket Data Center PR comment URL: " + commentURL)
+	}
+
+	resp, err := timpani.ExecuteActivity[datacenter.Comment](ctx, "bitbucket.datacenter.pullrequests.getComment", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to get Bitbucket Data Center PR comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("comment_url", commentURL))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// UpdateDataCenterComment replaces the text of an existing PR comment. Unlike Bitbucket Cloud, this requires
+// the comment's current version (for optimistic locking), so this function retrieves the comment first.
+func UpdateDataCenterComment(ctx workflow.Context, thrippyID, commentURL, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	comment, err := GetDataCenterComment(ctx, thrippyID, commentURL)
+	if err != nil {
+		return err
+	}
+
+	req, _ := newDataCenterRequest(ctx, thrippyID, commentURL) // Already validated above.
+	req.Version = comment.Version
+	req.Text = msg
+
+	if _, err := timpani.ExecuteActivity[datacenter.Comment](ctx, "bitbucket.datacenter.pullrequests.updateComment", req); err != nil {
+		logger.From(ctx).Error("failed to update Bitbucket Data Center PR comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("comment_url", commentURL))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDataCenterComment deletes an existing PR comment. Like [UpdateDataCenterComment],
+// this requires the comment's current version, so this function retrieves the comment first.
+func DeleteDataCenterComment(ctx workflow.Context, thrippyID, commentURL string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	comment, err := GetDataCenterComment(ctx, thrippyID, commentURL)
+	if err != nil {
+		return err
+	}
+
+	req, _ := newDataCenterRequest(ctx, thrippyID, commentURL) // Already validated above.
+	req.Version = comment.Version
+
+	if _, err := timpani.ExecuteActivity[any](ctx, "bitbucket.datacenter.pullrequests.deleteComment", req); err != nil {
+		logger.From(ctx).Error("failed to delete Bitbucket Data Center PR comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("comment_url", commentURL))
+		return err
+	}
+
+	return nil
+}
+
+// ListDataCenterTasks returns the tasks of a PR, converted into Bitbucket Cloud tasks.
+// In Bitbucket Data Center, tasks are comments with the "BLOCKER" severity.
+func ListDataCenterTasks(ctx workflow.Context, thrippyID, prURL string) ([]bitbucket.Task, error) {
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := timpani.ExecuteActivity[pagedResponse[datacenter.Comment]](ctx, "bitbucket.datacenter.pullrequests.listBlockerComments", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Bitbucket Data Center PR's tasks", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return nil, err
+	}
+
+	tasks := make([]bitbucket.Task, 0, len(resp.Values))
+	for _, c := range resp.Values {
+		tasks = append(tasks, c.CloudTask())
+	}
+	return tasks, nil
+}
+
+// CreateDataCenterTask creates a new task in a PR, i.e. a comment with the "BLOCKER" severity.
+func CreateDataCenterTask(ctx workflow.Context, thrippyID, prURL, text string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+	req.Text = text
+	req.Severity = "BLOCKER"
+
+	if _, err := timpani.ExecuteActivity[datacenter.Comment](ctx, "bitbucket.datacenter.pullrequests.createComment", req); err != nil {
+		logger.From(ctx).Error("failed to create Bitbucket Data Center PR task", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+// MergeDataCenterPullRequest merges a PR, with the given merge strategy ID (e.g. "no-ff",
+// "squash", "ff-only"), or the repository's default strategy if it's empty. Unlike Bitbucket
+// Cloud, this requires the PR's current version, so this function retrieves the PR first.
+func MergeDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL, strategyID string) error {
+	req, err := dataCenterPullRequestVersion(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+	req.StrategyID = strategyID
+
+	if _, err := timpani.ExecuteActivity[datacenter.PullRequest](ctx, "bitbucket.datacenter.pullrequests.merge", req); err != nil {
+		logger.From(ctx).Error("failed to merge Bitbucket Data Center PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+// DeclineDataCenterPullRequest declines a PR. Like [MergeDataCenterPullRequest],
+// this requires the PR's current version, so this function retrieves the PR first.
+func DeclineDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL string) error {
+	req, err := dataCenterPullRequestVersion(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	if _, err := timpani.ExecuteActivity[datacenter.PullRequest](ctx, "bitbucket.datacenter.pullrequests.decline", req); err != nil {
+		logger.From(ctx).Error("failed to decline Bitbucket Data Center PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+// dataCenterPullRequestVersion returns a request to modify an
+// existing PR, which includes the PR's current version.
+func dataCenterPullRequestVersion(ctx workflow.Context, thrippyID, prURL string) (*dataCenterRequest, error) {
+	if thrippyID == "" {
+		return nil, errors.New("missing user authentication credentials")
+	}
+
+	pr, err := GetDataCenterPullRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := newDataCenterRequest(ctx, thrippyID, prURL) // Already validated above.
+	req.Version = pr.Version
+	return req, nil
+}
+
+// dataCenterUpdateRequest is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-put
+type dataCenterUpdateRequest struct {
+	dataCenterRequest
+
+	Title     string               `json:"title"`
+	Reviewers []dataCenterReviewer `json:"reviewers"`
+}
+
+type dataCenterReviewer struct {
+	User dataCenterUserRef `json:"user"`
+}
+
+type dataCenterUserRef struct {
+	Name string `json:"name"`
+}
+
+// dataCenterUsersRequest is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-system-maintenance/#api-api-latest-users-get
+type dataCenterUsersRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+	BaseURL       string `json:"base_url"`
+	Filter        string `json:"filter"`
+}
+
+// AddDataCenterReviewers adds reviewers (identified by their pseudo account IDs, see [datacenter.AccountID])
+// to a PR. Bitbucket Data Center identifies users by their usernames, so this function looks them up by their
+// email addresses. It also retrieves the current state of the PR, because updating it requires its version.
+func AddDataCenterReviewers(ctx workflow.Context, thrippyID, prURL string, accountIDs []string) error {
+	req, pr, err := newDataCenterUpdateRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range accountIDs {
+		if slices.ContainsFunc(pr.Reviewers, func(p datacenter.Participant) bool { return datacenter.AccountID(p.User.EmailAddress) == id }) {
+			continue
+		}
+
+		name, err := dataCenterUsername(ctx, thrippyID, req.BaseURL, strings.TrimPrefix(id, datacenter.AccountIDPrefix))
+		if err != nil {
+			return err
+		}
+		req.Reviewers = append(req.Reviewers, dataCenterReviewer{User: dataCenterUserRef{Name: name}})
+	}
+
+	return updateDataCenterPullRequest(ctx, req, prURL)
+}
+
+// RemoveDataCenterReviewers removes reviewers (identified by their pseudo account IDs, see [datacenter.AccountID])
+// from a PR. Just like [AddDataCenterReviewers], it retrieves the current state of the PR and updates it.
+func RemoveDataCenterReviewers(ctx workflow.Context, thrippyID, prURL string, accountIDs []string) error {
+	req, pr, err := newDataCenterUpdateRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	req.Reviewers = make([]dataCenterReviewer, 0, len(pr.Reviewers))
+	for _, p := range pr.Reviewers {
+		if !slices.Contains(accountIDs, datacenter.AccountID(p.User.EmailAddress)) {
+			req.Reviewers = append(req.Reviewers, dataCenterReviewer{User: dataCenterUserRef{Name: p.User.Name}})
+		}
+	}
+
+	return updateDataCenterPullRequest(ctx, req, prURL)
+}
+
+func newDataCenterUpdateRequest(ctx workflow.Context, thrippyID, prURL string) (*dataCenterUpdateRequest, *datacenter.PullRequest, error) {
+	if thrippyID == "" {
+		return nil, nil, errors.New("missing user authentication credentials")
+	}
+
+	pr, err := GetDataCenterPullRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, _ := newDataCenterRequest(ctx, thrippyID, prURL) // Already validated above.
+	req.Version = pr.Version
+
+	reviewers := make([]dataCenterReviewer, 0, len(pr.Reviewers))
+	for _, p := range pr.Reviewers {
+		reviewers = append(reviewers, dataCenterReviewer{User: dataCenterUserRef{Name: p.User.Name}})
+	}
+
+	return &dataCenterUpdateRequest{dataCenterRequest: *req, Title: pr.Title, Reviewers: reviewers}, pr, nil
+}
+
+func updateDataCenterPullRequest(ctx workflow.Context, req *dataCenterUpdateRequest, prURL string) error {
+	if _, err := timpani.ExecuteActivity[datacenter.PullRequest](ctx, "bitbucket.datacenter.pullrequests.update", req); err != nil {
+		logger.From(ctx).Error("failed to update Bitbucket Data Center PR reviewers", slog.Any("error", err),
+			slog.String("thrippy_id", req.ThrippyLinkID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+// dataCenterUsername looks up the username of a Bitbucket Data Center user by their email address.
+func dataCenterUsername(ctx workflow.Context, thrippyID, baseURL, email string) (string, error) {
+	req := dataCenterUsersRequest{ThrippyLinkID: thrippyID, BaseURL: baseURL, Filter: email}
+	resp, err := timpani.ExecuteActivity[pagedResponse[datacenter.User]](ctx, "bitbucket.datacenter.users.list", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Bitbucket Data Center users", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("base_url", baseURL), slog.String("email", email))
+		return "", err
+	}
+
+	for _, u := range resp.Values {
+		if strings.EqualFold(u.EmailAddress, email) {
+			return u.Name, nil
+		}
+	}
+
+	return "", errors.New("user not found in Bitbucket Data Center: " + email)
+}
+
+// ApproveDataCenterPullRequest sets the review status of the authenticated user to "APPROVED".
+func ApproveDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL string) error {
+	return setDataCenterReviewStatus(ctx, thrippyID, prURL, "approve")
+}
+
+// UnapproveDataCenterPullRequest sets the review status of the authenticated user to "UNAPPROVED".
+func UnapproveDataCenterPullRequest(ctx workflow.Context, thrippyID, prURL string) error {
+	return setDataCenterReviewStatus(ctx, thrippyID, prURL, "unapprove")
+}
+
+func setDataCenterReviewStatus(ctx workflow.Context, thrippyID, prURL, action string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req, err := newDataCenterRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	if _, err := timpani.ExecuteActivity[datacenter.Participant](ctx, "bitbucket.datacenter.pullrequests."+action, req); err != nil {
+		logger.From(ctx).Error("failed to "+action+" Bitbucket Data Center PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+// dataCenterFileRequest is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-raw-path-get
+type dataCenterFileRequest struct {
+	BaseURL    string `json:"base_url"`
+	ProjectKey string `json:"project_key"`
+	RepoSlug   string `json:"repo_slug"`
+	At         string `json:"at,omitempty"` // Commit hash or branch name.
+	Path       string `json:"path"`
+}
+
+// GetDataCenterSourceFile returns the raw content of a file in a repository, at the given commit (or branch, if the commit is empty).
+// If the file doesn't exist, this function returns an empty string without an error.
+func GetDataCenterSourceFile(ctx workflow.Context, baseURL, projectKey, repo, branch, commit, path string) (string, error) {
+	req := dataCenterFileRequest{BaseURL: baseURL, ProjectKey: projectKey, RepoSlug: repo, At: commit, Path: path}
+	if commit == "" {
+		req.At = branch
+	}
+
+	file, err := timpani.ExecuteActivity[string](ctx, "bitbucket.datacenter.repos.getRawFile", req)
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return "", nil
+	}
+	if err != nil {
+		logger.From(ctx).Warn("failed to read Bitbucket Data Center source file",
+			slog.Any("error", err), slog.String("base_url", baseURL), slog.String("project_key", projectKey),
+			slog.String("repo", repo), slog.String("branch", branch), slog.String("commit", commit), slog.String("path", path))
+		return "", err
+	}
+
+	return *file, nil
+}
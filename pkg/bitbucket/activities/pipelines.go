@@ -0,0 +1,84 @@
+package activities
+
+import (
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+)
+
+// pipelinesRequest is based on:
+//   - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pipelines/#api-repositories-workspace-repo-slug-pipelines-pipeline-uuid-steps-get
+//   - https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pipelines/#api-repositories-workspace-repo-slug-pipelines-pipeline-uuid-steps-step-uuid-log-get
+type pipelinesRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Workspace    string `json:"workspace"`
+	RepoSlug     string `json:"repo_slug"`
+	PipelineUUID string `json:"pipeline_uuid"` // Or build number.
+	StepUUID     string `json:"step_uuid,omitempty"`
+
+	Next string `json:"next,omitempty"` // Populated and used only in Timpani, for pagination.
+}
+
+// PipelineStep is based on:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pipelines/#api-repositories-workspace-repo-slug-pipelines-pipeline-uuid-steps-step-uuid-get
+type PipelineStep struct {
+	UUID  string            `json:"uuid"`
+	Name  string            `json:"name"`
+	State PipelineStepState `json:"state"`
+}
+
+type PipelineStepState struct {
+	Name   string                `json:"name"` // "PENDING", "IN_PROGRESS", "COMPLETED".
+	Result *PipelineResultStatus `json:"result,omitempty"`
+}
+
+type PipelineResultStatus struct {
+	Name string `json:"name"` // "SUCCESSFUL", "FAILED", "ERROR", "STOPPED", etc.
+}
+
+type pipelineStepsResponse struct {
+	Values []PipelineStep `json:"values"`
+	Next   string         `json:"next,omitempty"`
+}
+
+// ListPipelineSteps returns all the steps of a Bitbucket Pipelines run, which is identified by its UUID or build number.
+func ListPipelineSteps(ctx workflow.Context, workspace, repo, pipeline string) ([]PipelineStep, error) {
+	req := pipelinesRequest{Workspace: workspace, RepoSlug: repo, PipelineUUID: pipeline, Next: "start"}
+
+	var steps []PipelineStep
+	for req.Next != "" {
+		if req.Next == "start" {
+			req.Next = ""
+		}
+
+		resp, err := timpani.ExecuteActivity[pipelineStepsResponse](ctx, "bitbucket.pipelines.listSteps", req)
+		if err != nil {
+			logger.From(ctx).Error("failed to list Bitbucket pipeline steps", slog.Any("error", err),
+				slog.String("workspace", workspace), slog.String("repo", repo), slog.String("pipeline", pipeline))
+			return nil, err
+		}
+
+		steps = append(steps, resp.Values...)
+		req.Next = resp.Next
+	}
+
+	return steps, nil
+}
+
+// GetPipelineStepLog returns the raw log of a specific step in a Bitbucket Pipelines run.
+func GetPipelineStepLog(ctx workflow.Context, workspace, repo, pipeline, stepUUID string) (string, error) {
+	req := pipelinesRequest{Workspace: workspace, RepoSlug: repo, PipelineUUID: pipeline, StepUUID: stepUUID}
+	log, err := timpani.ExecuteActivity[string](ctx, "bitbucket.pipelines.getStepLog", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to get Bitbucket pipeline step log", slog.Any("error", err),
+			slog.String("workspace", workspace), slog.String("repo", repo),
+			slog.String("pipeline", pipeline), slog.String("step_uuid", stepUUID))
+		return "", err
+	}
+
+	return *log, nil
+}
@@ -2,12 +2,17 @@ package activities
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
+	"time"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
@@ -92,6 +97,10 @@ func GetPullRequestComment(ctx workflow.Context, thrippyID, commentURL string) (
 
 // ListPullRequestTasks allows the Thrippy link ID to be empty, even though it is encouraged to specify it.
 func ListPullRequestTasks(ctx workflow.Context, thrippyID, prURL string) ([]bitbucket.Task, error) {
+	if datacenter.IsURL(prURL) {
+		return ListDataCenterTasks(ctx, thrippyID, prURL)
+	}
+
 	url := commentURLPattern.FindStringSubmatch(prURL)
 	if len(url) < 4 {
 		logger.From(ctx).Error("failed to parse Bitbucket PR's URL", slog.String("url", prURL))
@@ -124,3 +133,170 @@ func UpdatePullRequestComment(ctx workflow.Context, thrippyID, workspace, repo,
 
 	return nil
 }
+
+// pullRequestsListCommentsRequest is based on:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-get
+type pullRequestsListCommentsRequest struct {
+	bitbucket.PullRequestsRequest
+
+	// https://developer.atlassian.com/cloud/bitbucket/rest/intro/#filtering
+	Query string `json:"q,omitempty"`
+	Sort  string `json:"sort,omitempty"`
+
+	Next string `json:"next,omitempty"` // Populated and used only in Timpani, for pagination.
+}
+
+// pullRequestsListCommentsResponse is based on:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-get
+type pullRequestsListCommentsResponse struct {
+	Values []bitbucket.Comment `json:"values"`
+	Next   string              `json:"next,omitempty"`
+}
+
+// ListPullRequestComments returns all the comments in a PR (including deleted ones)
+// which were created or updated since the given time, in a single batch. It allows
+// the Thrippy link ID to be empty, even though it is encouraged to specify it.
+func ListPullRequestComments(ctx workflow.Context, thrippyID, prURL string, since time.Time) ([]bitbucket.Comment, error) {
+	url := commentURLPattern.FindStringSubmatch(prURL)
+	if len(url) < 4 {
+		logger.From(ctx).Error("failed to parse Bitbucket PR's URL", slog.String("url", prURL))
+		return nil, errors.New("invalid Bitbucket PR URL: " + prURL)
+	}
+
+	req := pullRequestsListCommentsRequest{
+		PullRequestsRequest: bitbucket.PullRequestsRequest{
+			ThrippyLinkID: thrippyID,
+			Workspace:     url[1],
+			RepoSlug:      url[2],
+			PullRequestID: url[3],
+		},
+		Query: fmt.Sprintf(`updated_on >= %s`, since.UTC().Format(time.RFC3339)),
+		Sort:  "updated_on",
+		Next:  "start",
+	}
+
+	var comments []bitbucket.Comment
+	for req.Next != "" {
+		if req.Next == "start" {
+			req.Next = ""
+		}
+
+		resp, err := timpani.ExecuteActivity[pullRequestsListCommentsResponse](ctx, "bitbucket.pullrequests.listComments", req)
+		if err != nil {
+			logger.From(ctx).Error("failed to list Bitbucket PR comments", slog.Any("error", err),
+				slog.String("thrippy_id", thrippyID), slog.String("workspace", url[1]),
+				slog.String("repo", url[2]), slog.String("pr_id", url[3]))
+			return nil, err
+		}
+
+		comments = append(comments, resp.Values...)
+		req.Next = resp.Next
+	}
+
+	return comments, nil
+}
+
+// pullRequestsCreateTaskRequest is based on:
+// https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-tasks-post
+type pullRequestsCreateTaskRequest struct {
+	bitbucket.PullRequestsRequest
+
+	Markdown string `json:"text"`
+}
+
+// CreatePullRequestTask creates a new task in a PR, on behalf of the given user.
+func CreatePullRequestTask(ctx workflow.Context, thrippyID, workspace, repo, prID, msg string) (*bitbucket.Task, error) {
+	if thrippyID == "" {
+		return nil, errors.New("missing user authentication credentials")
+	}
+
+	req := pullRequestsCreateTaskRequest{
+		PullRequestsRequest: bitbucket.PullRequestsRequest{
+			ThrippyLinkID: thrippyID,
+			Workspace:     workspace,
+			RepoSlug:      repo,
+			PullRequestID: prID,
+		},
+		Markdown: msg,
+	}
+
+	task, err := timpani.ExecuteActivity[bitbucket.Task](ctx, "bitbucket.pullrequests.createTask", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to create Bitbucket PR task", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("workspace", workspace),
+			slog.String("repo", repo), slog.String("pr_id", prID))
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// AddPullRequestReviewers adds reviewers (identified by their Bitbucket account IDs) to a PR. Bitbucket doesn't
+// have a dedicated API for this, so this function retrieves the current state of the PR and updates it.
+func AddPullRequestReviewers(ctx workflow.Context, thrippyID, prURL string, accountIDs []string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr, err := GetPullRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	var reviewers []any
+	if list, ok := pr["reviewers"].([]any); ok {
+		reviewers = list
+	}
+	for _, id := range accountIDs {
+		if !slices.ContainsFunc(reviewers, func(r any) bool { return accountID(r) == id }) {
+			reviewers = append(reviewers, map[string]any{"account_id": id})
+		}
+	}
+
+	return updatePullRequestReviewers(ctx, thrippyID, prURL, pr, reviewers)
+}
+
+// RemovePullRequestReviewers removes reviewers (identified by their Bitbucket account IDs) from a PR.
+// Just like [AddPullRequestReviewers], it retrieves the current state of the PR and updates it.
+func RemovePullRequestReviewers(ctx workflow.Context, thrippyID, prURL string, accountIDs []string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr, err := GetPullRequest(ctx, thrippyID, prURL)
+	if err != nil {
+		return err
+	}
+
+	reviewers := []any{}
+	if list, ok := pr["reviewers"].([]any); ok {
+		reviewers = slices.DeleteFunc(list, func(r any) bool { return slices.Contains(accountIDs, accountID(r)) })
+	}
+
+	return updatePullRequestReviewers(ctx, thrippyID, prURL, pr, reviewers)
+}
+
+func updatePullRequestReviewers(ctx workflow.Context, thrippyID, prURL string, pr map[string]any, reviewers []any) error {
+	url := commentURLPattern.FindStringSubmatch(prURL)
+
+	// Bitbucket API quirk: it rejects updates with the "summary.html" field.
+	delete(pr, "summary")
+	pr["reviewers"] = reviewers
+
+	if _, err := bitbucket.PullRequestsUpdate(ctx, thrippyID, url[1], url[2], url[3], pr); err != nil {
+		logger.From(ctx).Error("failed to update Bitbucket PR reviewers", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+		return err
+	}
+
+	return nil
+}
+
+func accountID(account any) string {
+	m, ok := account.(map[string]any)
+	if !ok {
+		return ""
+	}
+	id, _ := m["account_id"].(string)
+	return id
+}
@@ -2,6 +2,7 @@ package activities
 
 import (
 	"log/slog"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
@@ -9,8 +10,13 @@ import (
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
+// GetSourceFile returns the content of a file in the given commit, using the default Thrippy link of the
+// Bitbucket workspace. If the file doesn't exist, this function returns an empty string without an error.
 func GetSourceFile(ctx workflow.Context, workspace, repo, branch, commit, path string) (string, error) {
 	file, err := bitbucket.SourceGetFile(ctx, "", workspace, repo, commit, path)
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return "", nil
+	}
 	if err != nil {
 		logger.From(ctx).Warn("failed to read Bitbucket source file",
 			slog.Any("error", err), slog.String("workspace", workspace), slog.String("repo", repo),
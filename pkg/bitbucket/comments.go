@@ -46,7 +46,62 @@ func BeautifyInlineComment(ctx workflow.Context, comment *Comment, msg string) (
 // inlineCommentPrefix constructs a prefix to a PR comment,
 // indicating its type (file/inline) and location (path and line/s).
 func inlineCommentPrefix(commentURL string, in *Inline) string {
-	var line1 int
+	line1, line2 := inlineLines(in)
+
+	subject := "Inline"
+	location := "in"
+	switch line1 {
+	case 0: // No line info.
+		subject = "File"
+	case line2: // Single line.
+		location = fmt.Sprintf("in line %d in", line1)
+	default: // Multiple lines.
+		location = fmt.Sprintf("in lines %d-%d in", line1, line2)
+	}
+
+	return fmt.Sprintf("<%s|%s comment> %s `%s`:\n", commentURL, subject, location, in.Path)
+}
+
+// CommitCommentPrefix constructs a prefix to a commit comment, indicating the commit and (if the
+// comment is inline) its location, with a deep link to the commented line in Bitbucket's commit page.
+func CommitCommentPrefix(comment *Comment, commit *Commit) string {
+	commitURL := HTMLURL(commit.Links)
+	hash := commit.Hash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+
+	prefix := fmt.Sprintf("<%s|Commit comment> on <%s|`%s`>", HTMLURL(comment.Links), commitURL, hash)
+	if comment.Inline == nil {
+		return prefix + ":\n"
+	}
+
+	in := comment.Inline
+	line1, line2 := inlineLines(in)
+	switch line1 {
+	case 0: // No line info.
+		return fmt.Sprintf("%s in <%s|`%s`>:\n", prefix, commitLineURL(commitURL, in), in.Path)
+	case line2: // Single line.
+		return fmt.Sprintf("%s in <%s|line %d> in `%s`:\n", prefix, commitLineURL(commitURL, in), line1, in.Path)
+	default: // Multiple lines.
+		return fmt.Sprintf("%s in <%s|lines %d-%d> in `%s`:\n", prefix, commitLineURL(commitURL, in), line1, line2, in.Path)
+	}
+}
+
+// commitLineURL returns a deep link to the (last) line of an inline comment in Bitbucket's commit page,
+// based on the file path and the line number in the new ("T") or old ("F") version of the file.
+func commitLineURL(commitURL string, in *Inline) string {
+	if in.To != nil {
+		return fmt.Sprintf("%s#L%sT%d", commitURL, in.Path, *in.To)
+	}
+	if in.From != nil {
+		return fmt.Sprintf("%s#L%sF%d", commitURL, in.Path, *in.From)
+	}
+	return fmt.Sprintf("%s#chg-%s", commitURL, in.Path)
+}
+
+// inlineLines returns the first and last lines of an inline comment, or zeros if it's a file comment.
+func inlineLines(in *Inline) (line1, line2 int) {
 	if in.StartFrom != nil {
 		line1 = *in.StartFrom
 		if in.StartTo != nil && *in.StartTo < line1 {
@@ -56,7 +111,6 @@ func inlineCommentPrefix(commentURL string, in *Inline) string {
 		line1 = *in.StartTo
 	}
 
-	var line2 int
 	if in.From != nil {
 		line2 = *in.From
 		if in.To != nil && *in.To > line2 {
@@ -73,18 +127,7 @@ func inlineCommentPrefix(commentURL string, in *Inline) string {
 		line2 = line1
 	}
 
-	subject := "Inline"
-	location := "in"
-	switch line1 {
-	case 0: // No line info.
-		subject = "File"
-	case line2: // Single line.
-		location = fmt.Sprintf("in line %d in", line1)
-	default: // Multiple lines.
-		location = fmt.Sprintf("in lines %d-%d in", line1, line2)
-	}
-
-	return fmt.Sprintf("<%s|%s comment> %s `%s`:\n", commentURL, subject, location, in.Path)
+	return line1, line2
 }
 
 // extractSuggestionBlock extracts the suggestion code block from a PR inline comment.
@@ -52,6 +52,49 @@ func TestInlineCommentPrefix(t *testing.T) {
 	}
 }
 
+func TestCommitCommentPrefix(t *testing.T) {
+	commit := &Commit{Hash: "0123456789abcdef", Links: map[string]Link{"html": {HRef: "https://c"}}}
+	tests := []struct {
+		name string
+		i    *Inline
+		want string
+	}{
+		{
+			name: "commit_comment",
+			want: "<https://c#comment-1|Commit comment> on <https://c|`0123456`>:\n",
+		},
+		{
+			name: "file_comment",
+			i:    &Inline{Path: "a/b.go"},
+			want: "<https://c#comment-1|Commit comment> on <https://c|`0123456`> in <https://c#chg-a/b.go|`a/b.go`>:\n",
+		},
+		{
+			name: "single_to_line",
+			i:    &Inline{To: new(7), Path: "a/b.go"},
+			want: "<https://c#comment-1|Commit comment> on <https://c|`0123456`> in <https://c#La/b.goT7|line 7> in `a/b.go`:\n",
+		},
+		{
+			name: "single_from_line",
+			i:    &Inline{From: new(7), Path: "a/b.go"},
+			want: "<https://c#comment-1|Commit comment> on <https://c|`0123456`> in <https://c#La/b.goF7|line 7> in `a/b.go`:\n",
+		},
+		{
+			name: "multiple_lines",
+			i:    &Inline{StartTo: new(2), To: new(3), Path: "a/b.go"},
+			want: "<https://c#comment-1|Commit comment> on <https://c|`0123456`> in <https://c#La/b.goT3|lines 2-3> in `a/b.go`:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := &Comment{Inline: tt.i, Links: map[string]Link{"html": {HRef: "https://c#comment-1"}}}
+			if got := CommitCommentPrefix(comment, commit); got != tt.want {
+				t.Errorf("CommitCommentPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSpliceSuggestion(t *testing.T) {
 	tests := []struct {
 		name       string
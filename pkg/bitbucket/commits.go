@@ -1,18 +1,26 @@
 package bitbucket
 
 import (
+	"fmt"
 	"log/slog"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
 func Commits(ctx workflow.Context, event PullRequestEvent) []Commit {
+	if prURL := HTMLURL(event.PullRequest.Links); datacenter.IsURL(prURL) {
+		return dataCenterCommits(ctx, event, prURL)
+	}
+
 	workspace, repo, found := strings.Cut(event.Repository.FullName, "/")
 	if !found {
 		logger.From(ctx).Error("failed to parse Bitbucket workspace and repository name",
@@ -31,3 +39,36 @@ func Commits(ctx workflow.Context, event PullRequestEvent) []Commit {
 
 	return cs
 }
+
+// FindPRsWithCommit returns the URLs of all (0 or more) the tracked PRs in the given Bitbucket Cloud repository
+// whose commits include the given commit hash. Unlike [FindPRsByCommit], this isn't limited to the latest commit
+// of each PR, so it lists the commits of every tracked PR in the repository.
+func FindPRsWithCommit(ctx workflow.Context, thrippyID, repoFullName, hash string) ([]string, error) {
+	workspace, repo, found := strings.Cut(repoFullName, "/")
+	if !found {
+		logger.From(ctx).Error("failed to parse Bitbucket workspace and repository name", slog.String("full_name", repoFullName))
+		return nil, fmt.Errorf("invalid Bitbucket repository name: %q", repoFullName)
+	}
+
+	prURLs, err := data.ListPRsInRepo(ctx, "https://bitbucket.org/"+repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []string
+	for _, prURL := range prURLs {
+		cs, err := bitbucket.PullRequestsListCommits(ctx, thrippyID, workspace, repo, path.Base(prURL))
+		if err != nil {
+			logger.From(ctx).Error("failed to list Bitbucket PR's commits", slog.Any("error", err),
+				slog.String("thrippy_id", thrippyID), slog.String("pr_url", prURL))
+			continue
+		}
+
+		// The hash from the event is always the full commit hash, but the ones in the list may be truncated.
+		if slices.ContainsFunc(cs, func(c Commit) bool { return c.Hash != "" && strings.HasPrefix(hash, c.Hash) }) {
+			prs = append(prs, prURL)
+		}
+	}
+
+	return prs, nil
+}
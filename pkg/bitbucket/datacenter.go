@@ -0,0 +1,203 @@
+package bitbucket
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// dataCenterEventTypes maps Bitbucket Data Center event keys to equivalent Bitbucket Cloud event types:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Pullrequest
+var dataCenterEventTypes = map[string]string{
+	"pr:opened":              "created",
+	"pr:modified":            "updated", // Title, description, or target branch.
+	"pr:from_ref_updated":    "updated", // Commit(s) pushed to the source branch.
+	"pr:reviewer:updated":    "updated", // Reviewers added/removed.
+	"pr:reviewer:approved":   "approved",
+	"pr:reviewer:unapproved": "unapproved",
+	"pr:reviewer:needs_work": "changes_request_created",
+	"pr:merged":              "fulfilled",
+	"pr:declined":            "rejected",
+	"pr:deleted":             "rejected",
+
+	"pr:comment:added":   "comment_created",
+	"pr:comment:edited":  "comment_updated",
+	"pr:comment:deleted": "comment_deleted",
+}
+
+// ConvertDataCenterEvent converts a Bitbucket Data Center PR event into a Bitbucket Cloud PR event,
+// so it can be handled by the same workflows as Bitbucket Cloud events. The event's type is set
+// to the equivalent Bitbucket Cloud event type, e.g. "pr:opened" is converted to "created".
+func ConvertDataCenterEvent(e datacenter.Event) (*PullRequestEvent, error) {
+	eventType, ok := dataCenterEventTypes[e.EventKey]
+	if !ok {
+		return nil, errors.New("unsupported Bitbucket Data Center event key: " + e.EventKey)
+	}
+
+	prURL := datacenter.HTMLURL(e.PullRequest.Links)
+	if !datacenter.IsURL(prURL) {
+		return nil, errors.New("invalid Bitbucket Data Center PR URL: " + prURL)
+	}
+
+	pr := dataCenterPullRequest(e.PullRequest, prURL)
+	event := &PullRequestEvent{
+		Type:        eventType,
+		PullRequest: pr,
+		Repository:  pr.Destination.Repository,
+		Actor:       e.Actor.Account(),
+	}
+
+	switch eventType {
+	case "approved":
+		event.Approval = &Review{Date: e.Date, User: event.Actor}
+		// Unlike Bitbucket Cloud, reviewers may switch directly from "needs work" to "approved",
+		// without a separate event for removing the change request, so we report both in one event.
+		if e.PreviousStatus == "NEEDS_WORK" {
+			event.ChangesRequest = &Review{Date: e.Date, User: event.Actor}
+		}
+
+	case "unapproved":
+		// Bitbucket Cloud has separate event types for removing approvals and removing change requests.
+		if e.PreviousStatus == "NEEDS_WORK" {
+			event.Type = "changes_request_removed"
+			event.ChangesRequest = &Review{Date: e.Date, User: event.Actor}
+		}
+
+	case "changes_request_created":
+		event.ChangesRequest = &Review{Date: e.Date, User: event.Actor}
+	}
+
+	// [Config.PullRequestReviewedWorkflow] increments or decrements the count,
+	// so we compensate for that here, based on the PR's current review states.
+	switch {
+	case event.Type == "changes_request_created":
+		event.PullRequest.ChangeRequestCount--
+	case event.Type == "changes_request_removed", event.Type == "approved" && event.ChangesRequest != nil:
+		event.PullRequest.ChangeRequestCount++
+	}
+
+	if e.Comment != nil {
+		comment := e.Comment.CloudComment(prURL, e.CommentParentID)
+		event.Comment = &comment
+	}
+
+	return event, nil
+}
+
+func dataCenterPullRequest(pr datacenter.PullRequest, prURL string) PullRequest {
+	cloudPR := PullRequest{
+		ID:          pr.ID,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+		Draft:       pr.Draft,
+
+		Source:      dataCenterReference(pr.FromRef, prURL),
+		Destination: dataCenterReference(pr.ToRef, prURL),
+
+		CreatedOn: millisToRFC3339(pr.CreatedDate),
+		UpdatedOn: millisToRFC3339(pr.UpdatedDate),
+
+		Author: pr.Author.User.Account(),
+		Links:  map[string]Link{"html": {HRef: prURL}},
+	}
+
+	if pr.Properties != nil {
+		cloudPR.TaskCount = pr.Properties.OpenTaskCount
+	}
+
+	for _, p := range pr.Reviewers {
+		cloudPR.Reviewers = append(cloudPR.Reviewers, p.User.Account())
+		if p.Status == "NEEDS_WORK" {
+			cloudPR.ChangeRequestCount++
+		}
+	}
+
+	for _, p := range slices.Concat(pr.Reviewers, pr.Participants) {
+		cloudPR.Participants = append(cloudPR.Participants, dataCenterParticipant(p))
+	}
+
+	return cloudPR
+}
+
+func dataCenterParticipant(p datacenter.Participant) Participant {
+	var state *string
+	switch p.Status {
+	case "APPROVED":
+		s := "approved"
+		state = &s
+	case "NEEDS_WORK":
+		s := "changes_requested"
+		state = &s
+	}
+
+	return Participant{User: p.User.Account(), Role: p.Role, Approved: p.Approved, State: state}
+}
+
+// dataCenterRepoURLPattern extracts the web URL of a repository from the URL of one of its PRs.
+var dataCenterRepoURLPattern = regexp.MustCompile(`^https://[^/]+/projects/[^/]+/repos/[^/]+`)
+
+func dataCenterReference(ref datacenter.Ref, prURL string) Reference {
+	repo := ref.Repository
+	repoURL := dataCenterRepoURLPattern.FindString(prURL)
+	if u := datacenter.ParseURL(prURL); u != nil && (u.ProjectKey != repo.Project.Key || u.RepoSlug != repo.Slug) {
+		repoURL = u.BaseURL + "/projects/" + repo.Project.Key + "/repos/" + repo.Slug // Source branch in a fork.
+	}
+
+	return Reference{
+		Branch: Branch{Name: ref.DisplayID},
+		Commit: Commit{Hash: ref.LatestCommit, Links: map[string]Link{"html": {HRef: repoURL + "/commits/" + ref.LatestCommit}}},
+		Repository: Repository{
+			FullName: repo.Project.Key + "/" + repo.Slug,
+			Name:     repo.Name,
+			Project:  &Project{Key: repo.Project.Key, Name: repo.Project.Name},
+			Links:    map[string]Link{"html": {HRef: repoURL}},
+		},
+	}
+}
+
+func millisToRFC3339(ms int64) string {
+	if ms == 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+// dataCenterCommits is the Bitbucket Data Center implementation of [Commits].
+func dataCenterCommits(ctx workflow.Context, event PullRequestEvent, prURL string) []Commit {
+	user := data.SelectUserByBitbucketID(ctx, event.Actor.AccountID)
+	cs, err := activities.ListDataCenterCommits(ctx, user.ThrippyLink, prURL)
+	if err != nil {
+		return nil
+	}
+
+	repoURL := HTMLURL(event.Repository.Links)
+	commits := make([]Commit, 0, len(cs))
+	for _, c := range cs {
+		commits = append(commits, c.CloudCommit(repoURL))
+	}
+	return commits
+}
+
+// dataCenterDiffstat is the Bitbucket Data Center implementation of [Diffstat].
+func dataCenterDiffstat(ctx workflow.Context, event PullRequestEvent, prURL string) []bitbucket.Diffstat {
+	user := data.SelectUserByBitbucketID(ctx, event.Actor.AccountID)
+	cs, err := activities.ListDataCenterChanges(ctx, user.ThrippyLink, prURL)
+	if err != nil {
+		return nil
+	}
+
+	ds := make([]bitbucket.Diffstat, 0, len(cs))
+	for _, c := range cs {
+		ds = append(ds, c.CloudDiffstat())
+	}
+	return ds
+}
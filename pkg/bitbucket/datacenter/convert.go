@@ -0,0 +1,132 @@
+package datacenter
+
+import (
+	"time"
+
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// HTMLURL returns the web URL of a Bitbucket Data Center entity, based on its "self" link.
+func HTMLURL(links map[string][]Link) string {
+	if len(links["self"]) == 0 {
+		return ""
+	}
+	return links["self"][0].HRef
+}
+
+// Account converts a Bitbucket Data Center user into a Bitbucket Cloud account.
+func (u User) Account() bitbucket.User {
+	t := "user"
+	if u.Type == "SERVICE" {
+		t = "app_user"
+	}
+
+	return bitbucket.User{
+		Type:        t,
+		DisplayName: u.DisplayName,
+		Nickname:    u.Slug,
+		AccountID:   AccountID(u.EmailAddress),
+	}
+}
+
+// CloudComment converts a Bitbucket Data Center comment into a Bitbucket Cloud comment,
+// in the context of the PR with the given URL, and an optional parent comment ID.
+func (c Comment) CloudComment(prURL string, parentID *int) bitbucket.Comment {
+	comment := bitbucket.Comment{
+		ID:        c.ID,
+		Content:   bitbucket.Rendered{Raw: c.Text, Markup: "markdown"},
+		User:      c.Author.Account(),
+		CreatedOn: millisToTime(c.CreatedDate),
+		UpdatedOn: millisToTime(c.UpdatedDate),
+		Links:     map[string]bitbucket.Link{"html": {HRef: CommentURL(prURL, c.ID)}},
+	}
+
+	if parentID != nil {
+		comment.Parent = &bitbucket.Parent{
+			ID:    *parentID,
+			Links: map[string]bitbucket.Link{"html": {HRef: CommentURL(prURL, *parentID)}},
+		}
+	}
+
+	if a := c.Anchor; a != nil && a.Path != "" {
+		comment.Inline = &bitbucket.Inline{Path: a.Path, SrcRev: a.FromHash, DestRev: a.ToHash}
+		if a.Line > 0 {
+			line := a.Line
+			if a.FileType == "FROM" {
+				comment.Inline.From = &line
+			} else {
+				comment.Inline.To = &line
+			}
+		}
+	}
+
+	return comment
+}
+
+// CloudTask converts a Bitbucket Data Center blocker comment into a Bitbucket Cloud task.
+func (c Comment) CloudTask() bitbucket.Task {
+	task := bitbucket.Task{
+		ID:        c.ID,
+		State:     "UNRESOLVED",
+		Content:   bitbucket.Rendered{Raw: c.Text, Markup: "markdown"},
+		Creator:   c.Author.Account(),
+		CreatedOn: millisToTime(c.CreatedDate),
+		UpdatedOn: millisToTime(c.UpdatedDate),
+	}
+
+	if c.State == "RESOLVED" {
+		task.State = "RESOLVED"
+		task.ResolvedOn = millisToTime(c.ResolvedDate)
+		if c.Resolver != nil {
+			u := c.Resolver.Account()
+			task.ResolvedBy = &u
+		}
+	}
+
+	return task
+}
+
+// CloudCommit converts a Bitbucket Data Center commit into a Bitbucket Cloud
+// commit, in the context of the repository with the given web URL.
+func (c Commit) CloudCommit(repoURL string) bitbucket.Commit {
+	author := c.Author.Account()
+	return bitbucket.Commit{
+		Hash:    c.ID,
+		Date:    millisToTime(c.AuthorTimestamp).Format(time.RFC3339),
+		Author:  &author,
+		Message: c.Message,
+		Links:   map[string]bitbucket.Link{"html": {HRef: repoURL + "/commits/" + c.ID}},
+	}
+}
+
+// CloudDiffstat converts a Bitbucket Data Center change into a Bitbucket Cloud diffstat entry.
+// Data Center doesn't report the number of added and removed lines per file in the same API call.
+func (c Change) CloudDiffstat() bitbucket.Diffstat {
+	d := bitbucket.Diffstat{}
+	switch c.Type {
+	case "ADD", "COPY":
+		d.Status = "added"
+		d.New = &bitbucket.CommitFile{Path: c.Path.ToString}
+	case "DELETE":
+		d.Status = "removed"
+		d.Old = &bitbucket.CommitFile{Path: c.Path.ToString}
+	case "MOVE":
+		d.Status = "renamed"
+		d.New = &bitbucket.CommitFile{Path: c.Path.ToString}
+		if c.SrcPath != nil {
+			d.Old = &bitbucket.CommitFile{Path: c.SrcPath.ToString}
+		}
+	default: // "MODIFY".
+		d.Status = "modified"
+		d.Old = &bitbucket.CommitFile{Path: c.Path.ToString}
+		d.New = &bitbucket.CommitFile{Path: c.Path.ToString}
+	}
+	return d
+}
+
+func millisToTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,179 @@
+package datacenter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readEvent(t *testing.T, name string) Event {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", name+".json")) //gosec:disable G304 // Unit test.
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := Event{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestUserAccount(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want string // Account type.
+		id   string
+	}{
+		{
+			name: "normal_user",
+			user: User{Slug: "alice", DisplayName: "Alice", EmailAddress: "Alice@Example.com", Type: "NORMAL"},
+			want: "user",
+			id:   "dc:alice@example.com",
+		},
+		{
+			name: "service_user",
+			user: User{Slug: "ci", DisplayName: "CI", Type: "SERVICE"},
+			want: "app_user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.user.Account()
+			if got.Type != tt.want {
+				t.Errorf("Account().Type = %q, want %q", got.Type, tt.want)
+			}
+			if got.AccountID != tt.id {
+				t.Errorf("Account().AccountID = %q, want %q", got.AccountID, tt.id)
+			}
+			if got.Nickname != tt.user.Slug || got.DisplayName != tt.user.DisplayName {
+				t.Errorf("Account() = %v, want nickname %q and display name %q", got, tt.user.Slug, tt.user.DisplayName)
+			}
+		})
+	}
+}
+
+func TestCloudComment(t *testing.T) {
+	e := readEvent(t, "pr_comment_added")
+	prURL := HTMLURL(e.PullRequest.Links)
+	got := e.Comment.CloudComment(prURL, e.CommentParentID)
+
+	if want := prURL + "/overview?commentId=17"; got.Links["html"].HRef != want {
+		t.Errorf("CloudComment() URL = %q, want %q", got.Links["html"].HRef, want)
+	}
+	if got.Parent == nil || got.Parent.ID != 16 {
+		t.Fatalf("CloudComment() parent = %v, want ID 16", got.Parent)
+	}
+	if want := prURL + "/overview?commentId=16"; got.Parent.Links["html"].HRef != want {
+		t.Errorf("CloudComment() parent URL = %q, want %q", got.Parent.Links["html"].HRef, want)
+	}
+	if got.Content.Raw != "Should this be configurable?" {
+		t.Errorf("CloudComment() text = %q", got.Content.Raw)
+	}
+	if got.User.AccountID != "dc:bob@example.com" {
+		t.Errorf("CloudComment() user = %q, want %q", got.User.AccountID, "dc:bob@example.com")
+	}
+
+	in := got.Inline
+	if in == nil {
+		t.Fatal("CloudComment() inline = nil")
+	}
+	if in.Path != "pkg/webhook/client.go" || in.To == nil || *in.To != 27 || in.From != nil {
+		t.Errorf("CloudComment() inline = %+v, want path and \"to\" line 27", in)
+	}
+}
+
+func TestCloudTask(t *testing.T) {
+	bob := User{Slug: "bob", DisplayName: "Bob", EmailAddress: "bob@example.com", Type: "NORMAL"}
+	tests := []struct {
+		name         string
+		comment      Comment
+		wantState    string
+		wantResolver string
+	}{
+		{
+			name:      "open",
+			comment:   Comment{ID: 1, Text: "Add tests", Severity: "BLOCKER", State: "OPEN"},
+			wantState: "UNRESOLVED",
+		},
+		{
+			name:         "resolved",
+			comment:      Comment{ID: 2, Text: "Fix typo", Severity: "BLOCKER", State: "RESOLVED", Resolver: &bob},
+			wantState:    "RESOLVED",
+			wantResolver: "dc:bob@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.comment.CloudTask()
+			if got.ID != tt.comment.ID || got.Content.Raw != tt.comment.Text {
+				t.Errorf("CloudTask() = %d %q, want %d %q", got.ID, got.Content.Raw, tt.comment.ID, tt.comment.Text)
+			}
+			if got.State != tt.wantState {
+				t.Errorf("CloudTask().State = %q, want %q", got.State, tt.wantState)
+			}
+			resolver := ""
+			if got.ResolvedBy != nil {
+				resolver = got.ResolvedBy.AccountID
+			}
+			if resolver != tt.wantResolver {
+				t.Errorf("CloudTask().ResolvedBy = %q, want %q", resolver, tt.wantResolver)
+			}
+		})
+	}
+}
+
+func TestCloudDiffstat(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   Change
+		wantStat string
+		wantOld  string
+		wantNew  string
+	}{
+		{
+			name:     "add",
+			change:   Change{Type: "ADD", Path: Path{ToString: "a.go"}},
+			wantStat: "added",
+			wantNew:  "a.go",
+		},
+		{
+			name:     "modify",
+			change:   Change{Type: "MODIFY", Path: Path{ToString: "b.go"}},
+			wantStat: "modified",
+			wantOld:  "b.go",
+			wantNew:  "b.go",
+		},
+		{
+			name:     "delete",
+			change:   Change{Type: "DELETE", Path: Path{ToString: "c.go"}},
+			wantStat: "removed",
+			wantOld:  "c.go",
+		},
+		{
+			name:     "move",
+			change:   Change{Type: "MOVE", Path: Path{ToString: "e.go"}, SrcPath: &Path{ToString: "d.go"}},
+			wantStat: "renamed",
+			wantOld:  "d.go",
+			wantNew:  "e.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.change.CloudDiffstat()
+			if got.Status != tt.wantStat {
+				t.Errorf("CloudDiffstat().Status = %q, want %q", got.Status, tt.wantStat)
+			}
+			if (got.Old == nil && tt.wantOld != "") || (got.Old != nil && got.Old.Path != tt.wantOld) {
+				t.Errorf("CloudDiffstat().Old = %v, want %q", got.Old, tt.wantOld)
+			}
+			if (got.New == nil && tt.wantNew != "") || (got.New != nil && got.New.Path != tt.wantNew) {
+				t.Errorf("CloudDiffstat().New = %v, want %q", got.New, tt.wantNew)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+// Package datacenter supports self-hosted Bitbucket Data Center (formerly
+// Bitbucket Server) instances, alongside Bitbucket Cloud: webhook payloads,
+// URL patterns, and conversions of Data Center entities into their Bitbucket
+// Cloud counterparts, so they can be handled by the same RevChat workflows.
+//
+// Data Center PR URLs look like this (instances with a context path are not
+// supported): https://bitbucket.example.com/projects/KEY/repos/slug/pull-requests/123
+package datacenter
@@ -0,0 +1,171 @@
+package datacenter
+
+// Event is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Pullrequest
+type Event struct {
+	EventKey string `json:"eventKey"` // E.g. "pr:opened", "pr:comment:added".
+	Date     string `json:"date"`
+	Actor    User   `json:"actor"`
+
+	PullRequest PullRequest `json:"pullRequest"`
+
+	// "pr:reviewer:updated".
+	AddedReviewers   []User `json:"addedReviewers,omitempty"`
+	RemovedReviewers []User `json:"removedReviewers,omitempty"`
+
+	// "pr:reviewer:approved", "pr:reviewer:unapproved", "pr:reviewer:needs_work".
+	Participant    *Participant `json:"participant,omitempty"`
+	PreviousStatus string       `json:"previousStatus,omitempty"`
+
+	// "pr:comment:added", "pr:comment:edited", "pr:comment:deleted".
+	Comment         *Comment `json:"comment,omitempty"`
+	CommentParentID *int     `json:"commentParentId,omitempty"`
+	PreviousComment string   `json:"previousComment,omitempty"`
+
+	// "pr:from_ref_updated".
+	PreviousFromHash string `json:"previousFromHash,omitempty"`
+
+	// PreviousTitle       string `json:"previousTitle"`       // Unnecessary, RevChat compares PR snapshots.
+	// PreviousDescription string `json:"previousDescription"` // Unnecessary, RevChat compares PR snapshots.
+	// PreviousTarget      Ref    `json:"previousTarget"`      // Unnecessary, RevChat compares PR snapshots.
+}
+
+// Anchor is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-comments-post
+type Anchor struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	LineType string `json:"lineType,omitempty"` // "ADDED", "REMOVED", "CONTEXT".
+	FileType string `json:"fileType,omitempty"` // "FROM", "TO".
+	FromHash string `json:"fromHash,omitempty"`
+	ToHash   string `json:"toHash,omitempty"`
+}
+
+// Change is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-changes-get
+type Change struct {
+	Type    string `json:"type"` // "ADD", "MODIFY", "DELETE", "MOVE", "COPY".
+	Path    Path   `json:"path"`
+	SrcPath *Path  `json:"srcPath,omitempty"`
+}
+
+// Comment is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Commentadded
+type Comment struct {
+	ID      int     `json:"id"`
+	Version int     `json:"version"`
+	Text    string  `json:"text"`
+	Author  User    `json:"author"`
+	Anchor  *Anchor `json:"anchor,omitempty"`
+
+	// Tasks are comments with the "BLOCKER" severity.
+	Severity string `json:"severity,omitempty"` // "NORMAL", "BLOCKER".
+	State    string `json:"state,omitempty"`    // "OPEN", "RESOLVED".
+	Resolver *User  `json:"resolver,omitempty"`
+
+	CreatedDate  int64 `json:"createdDate"`
+	UpdatedDate  int64 `json:"updatedDate"`
+	ResolvedDate int64 `json:"resolvedDate,omitempty"`
+}
+
+// Commit is based on:
+// https://developer.atlassian.com/server/bitbucket/rest/v906/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-commits-get
+type Commit struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	Message   string `json:"message"`
+	Author    User   `json:"author"`
+
+	AuthorTimestamp int64 `json:"authorTimestamp"`
+}
+
+//revive:disable:exported
+type Link struct {
+	HRef string `json:"href"`
+} //revive:enable:exported
+
+// Participant is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Participant
+type Participant struct {
+	User     User   `json:"user"`
+	Role     string `json:"role"` // "AUTHOR", "REVIEWER", "PARTICIPANT".
+	Approved bool   `json:"approved"`
+	Status   string `json:"status"` // "UNAPPROVED", "NEEDS_WORK", "APPROVED".
+
+	LastReviewedCommit string `json:"lastReviewedCommit,omitempty"`
+}
+
+//revive:disable:exported
+type Path struct {
+	ToString string `json:"toString"`
+} //revive:enable:exported
+
+//revive:disable:exported
+type Project struct {
+	ID   int    `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+} //revive:enable:exported
+
+// PullRequest is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-PullRequest
+type PullRequest struct {
+	ID          int    `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state"` // "OPEN", "MERGED", "DECLINED".
+	Draft       bool   `json:"draft,omitempty"`
+
+	CreatedDate int64 `json:"createdDate"`
+	UpdatedDate int64 `json:"updatedDate"`
+
+	FromRef Ref `json:"fromRef"`
+	ToRef   Ref `json:"toRef"`
+
+	Author       Participant   `json:"author"`
+	Reviewers    []Participant `json:"reviewers"`
+	Participants []Participant `json:"participants"`
+
+	Properties *Properties `json:"properties,omitempty"`
+
+	Links map[string][]Link `json:"links"`
+}
+
+//revive:disable:exported
+type Properties struct {
+	OpenTaskCount     int `json:"openTaskCount"`
+	ResolvedTaskCount int `json:"resolvedTaskCount"`
+} //revive:enable:exported
+
+// Ref is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Reference
+type Ref struct {
+	ID           string     `json:"id"` // E.g. "refs/heads/main".
+	DisplayID    string     `json:"displayId"`
+	LatestCommit string     `json:"latestCommit"`
+	Repository   Repository `json:"repository"`
+}
+
+// Repository is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Repository
+type Repository struct {
+	ID      int     `json:"id"`
+	Slug    string  `json:"slug"`
+	Name    string  `json:"name"`
+	Project Project `json:"project"`
+
+	Links map[string][]Link `json:"links,omitempty"`
+}
+
+// User is based on:
+// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-User
+type User struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Type         string `json:"type"` // "NORMAL" or "SERVICE".
+	Active       bool   `json:"active"`
+}
@@ -0,0 +1,63 @@
+package datacenter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AccountIDPrefix distinguishes the pseudo account IDs of Bitbucket Data Center users from Bitbucket
+// Cloud account IDs. Data Center users don't have a global account ID, but unlike Bitbucket Cloud,
+// their payloads do expose email addresses, so RevChat uses them to identify users instead.
+const AccountIDPrefix = "dc:"
+
+// prURLPattern matches Data Center PR URLs, with an optional comment ID. Unlike Bitbucket
+// Cloud, self-hosted instances may be served over plain HTTP.
+var prURLPattern = regexp.MustCompile(`^(https?://[^/]+)/projects/([^/]+)/repos/([^/]+)/pull-requests/(\d+)(/overview\?commentId=(\d+))?`)
+
+// PRURL identifies a Bitbucket Data Center PR, or a PR comment.
+type PRURL struct {
+	BaseURL    string // E.g. "https://bitbucket.example.com".
+	ProjectKey string
+	RepoSlug   string
+	PRID       int
+	CommentID  int // Optional.
+}
+
+// IsURL checks whether the given URL belongs to a Bitbucket Data
+// Center PR, i.e. not Bitbucket Cloud, and not any other platform.
+func IsURL(url string) bool {
+	return prURLPattern.MatchString(url)
+}
+
+// ParseURL parses a Bitbucket Data Center PR or PR comment URL.
+// It returns nil if the URL doesn't match the expected pattern.
+func ParseURL(url string) *PRURL {
+	m := prURLPattern.FindStringSubmatch(url)
+	if m == nil {
+		return nil
+	}
+
+	prID, err := strconv.Atoi(m[4])
+	if err != nil {
+		return nil
+	}
+	commentID, _ := strconv.Atoi(m[6]) // Optional, defaults to 0.
+
+	return &PRURL{BaseURL: m[1], ProjectKey: m[2], RepoSlug: m[3], PRID: prID, CommentID: commentID}
+}
+
+// CommentURL returns the URL of a comment in a Bitbucket Data Center PR.
+func CommentURL(prURL string, commentID int) string {
+	prURL, _, _ = strings.Cut(prURL, "/overview")
+	return prURL + "/overview?commentId=" + strconv.Itoa(commentID)
+}
+
+// AccountID returns the pseudo account ID of a Bitbucket Data Center user, based on their email
+// address, or an empty string if the user doesn't have one (e.g. service accounts). See [AccountIDPrefix].
+func AccountID(email string) string {
+	if email == "" {
+		return ""
+	}
+	return AccountIDPrefix + strings.ToLower(email)
+}
@@ -0,0 +1,64 @@
+package datacenter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want *PRURL
+	}{
+		{
+			name: "pr",
+			url:  "https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42",
+			want: &PRURL{BaseURL: "https://bitbucket.example.com", ProjectKey: "PRJ", RepoSlug: "backend", PRID: 42},
+		},
+		{
+			name: "pr_subpage",
+			url:  "https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42/diff",
+			want: &PRURL{BaseURL: "https://bitbucket.example.com", ProjectKey: "PRJ", RepoSlug: "backend", PRID: 42},
+		},
+		{
+			name: "comment",
+			url:  "https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42/overview?commentId=17",
+			want: &PRURL{BaseURL: "https://bitbucket.example.com", ProjectKey: "PRJ", RepoSlug: "backend", PRID: 42, CommentID: 17},
+		},
+		{
+			name: "http",
+			url:  "http://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42",
+			want: &PRURL{BaseURL: "http://bitbucket.example.com", ProjectKey: "PRJ", RepoSlug: "backend", PRID: 42},
+		},
+		{
+			name: "bitbucket_cloud",
+			url:  "https://bitbucket.org/workspace/repo/pull-requests/42",
+		},
+		{
+			name: "github",
+			url:  "https://github.com/owner/repo/pull/42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseURL(tt.url); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseURL() = %v, want %v", got, tt.want)
+			}
+			if got := IsURL(tt.url); got != (tt.want != nil) {
+				t.Errorf("IsURL() = %v, want %v", got, tt.want != nil)
+			}
+		})
+	}
+}
+
+func TestCommentURL(t *testing.T) {
+	want := "https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42/overview?commentId=17"
+
+	if got := CommentURL("https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42", 17); got != want {
+		t.Errorf("CommentURL() = %q, want %q", got, want)
+	}
+	if got := CommentURL("https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42/overview?commentId=16", 17); got != want {
+		t.Errorf("CommentURL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+)
+
+func readDataCenterEvent(t *testing.T, name string) datacenter.Event {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("datacenter", "testdata", name+".json")) //gosec:disable G304 // Unit test.
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := datacenter.Event{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestConvertDataCenterEvent(t *testing.T) {
+	const prURL = "https://bitbucket.example.com/projects/PRJ/repos/backend/pull-requests/42"
+
+	tests := []struct {
+		fixture            string
+		wantType           string
+		wantActor          string
+		wantChangeRequests int
+		wantComment        bool
+	}{
+		{
+			fixture:   "pr_opened",
+			wantType:  "created",
+			wantActor: "dc:alice@example.com",
+		},
+		{
+			fixture:            "pr_reviewer_needs_work",
+			wantType:           "changes_request_created",
+			wantActor:          "dc:bob@example.com",
+			wantChangeRequests: 0, // 1 in the payload, minus the workflow's increment.
+		},
+		{
+			fixture:            "pr_reviewer_unapproved",
+			wantType:           "changes_request_removed",
+			wantActor:          "dc:bob@example.com",
+			wantChangeRequests: 1, // 0 in the payload, plus the workflow's decrement.
+		},
+		{
+			fixture:   "pr_reviewer_approved",
+			wantType:  "approved",
+			wantActor: "dc:carol@example.com",
+		},
+		{
+			fixture:     "pr_comment_added",
+			wantType:    "comment_created",
+			wantActor:   "dc:bob@example.com",
+			wantComment: true,
+		},
+		{
+			fixture:   "pr_merged",
+			wantType:  "fulfilled",
+			wantActor: "dc:alice@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := ConvertDataCenterEvent(readDataCenterEvent(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("ConvertDataCenterEvent() error = %v", err)
+			}
+
+			if got.Type != tt.wantType {
+				t.Errorf("ConvertDataCenterEvent() type = %q, want %q", got.Type, tt.wantType)
+			}
+			if got.Actor.AccountID != tt.wantActor {
+				t.Errorf("ConvertDataCenterEvent() actor = %q, want %q", got.Actor.AccountID, tt.wantActor)
+			}
+			if got.PullRequest.ChangeRequestCount != tt.wantChangeRequests {
+				t.Errorf("ConvertDataCenterEvent() change requests = %d, want %d", got.PullRequest.ChangeRequestCount, tt.wantChangeRequests)
+			}
+			if (got.Comment != nil) != tt.wantComment {
+				t.Errorf("ConvertDataCenterEvent() comment = %v, want %v", got.Comment, tt.wantComment)
+			}
+
+			pr := got.PullRequest
+			if url := HTMLURL(pr.Links); url != prURL {
+				t.Errorf("ConvertDataCenterEvent() PR URL = %q, want %q", url, prURL)
+			}
+			if pr.ID != 42 || pr.Title != "Add retries to the webhook client" {
+				t.Errorf("ConvertDataCenterEvent() PR = %d %q", pr.ID, pr.Title)
+			}
+			if got.Repository.FullName != "PRJ/backend" {
+				t.Errorf("ConvertDataCenterEvent() repo = %q, want %q", got.Repository.FullName, "PRJ/backend")
+			}
+			if pr.Destination.Branch.Name != "main" || pr.Source.Branch.Name != "feature/retries" {
+				t.Errorf("ConvertDataCenterEvent() branches = %q <- %q", pr.Destination.Branch.Name, pr.Source.Branch.Name)
+			}
+			if want := "8d51122def5632836d1cb1026e879069e10a1e13"; pr.Source.Commit.Hash != want {
+				t.Errorf("ConvertDataCenterEvent() source commit = %q, want %q", pr.Source.Commit.Hash, want)
+			}
+			if ids := accountIDs(pr.Reviewers); len(ids) != 2 || ids[0] != "dc:bob@example.com" || ids[1] != "dc:carol@example.com" {
+				t.Errorf("ConvertDataCenterEvent() reviewers = %v", ids)
+			}
+		})
+	}
+}
+
+func TestConvertDataCenterEventApprovedAfterNeedsWork(t *testing.T) {
+	e := readDataCenterEvent(t, "pr_reviewer_approved")
+	e.PreviousStatus = "NEEDS_WORK"
+
+	got, err := ConvertDataCenterEvent(e)
+	if err != nil {
+		t.Fatalf("ConvertDataCenterEvent() error = %v", err)
+	}
+
+	if got.Type != "approved" {
+		t.Errorf("ConvertDataCenterEvent() type = %q, want %q", got.Type, "approved")
+	}
+	if got.Approval == nil || got.ChangesRequest == nil {
+		t.Errorf("ConvertDataCenterEvent() approval = %v, changes request = %v, want both", got.Approval, got.ChangesRequest)
+	}
+	// 0 in the payload, plus the workflow's decrement.
+	if got.PullRequest.ChangeRequestCount != 1 {
+		t.Errorf("ConvertDataCenterEvent() change requests = %d, want %d", got.PullRequest.ChangeRequestCount, 1)
+	}
+}
+
+func TestConvertDataCenterEventUnsupported(t *testing.T) {
+	e := readDataCenterEvent(t, "pr_opened")
+	e.EventKey = "repo:refs_changed"
+
+	if _, err := ConvertDataCenterEvent(e); err == nil {
+		t.Error("ConvertDataCenterEvent() error = nil, want unsupported event key")
+	}
+}
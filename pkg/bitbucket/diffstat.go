@@ -8,11 +8,16 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
 func Diffstat(ctx workflow.Context, event PullRequestEvent) []bitbucket.Diffstat {
+	if prURL := HTMLURL(event.PullRequest.Links); datacenter.IsURL(prURL) {
+		return dataCenterDiffstat(ctx, event, prURL)
+	}
+
 	workspace, repo, found := strings.Cut(event.Repository.FullName, "/")
 	if !found {
 		logger.From(ctx).Error("failed to parse Bitbucket workspace and repository name",
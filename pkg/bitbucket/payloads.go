@@ -29,7 +29,7 @@ type RepositoryEvent struct {
 	Comment      *Comment      `json:"comment,omitempty"`
 	CommitStatus *CommitStatus `json:"commit_status,omitempty"`
 
-	Issue map[string]any `json:"issue,omitempty"`
+	Issue *Issue `json:"issue,omitempty"`
 }
 
 type Account = bitbucket.User
@@ -68,6 +68,26 @@ type CommitStatus struct {
 
 type Inline = bitbucket.Inline
 
+// https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Issue
+type Issue struct {
+	// Type string `json:"type"` // Always "issue".
+
+	ID       int      `json:"id"`
+	Title    string   `json:"title"`
+	Content  Rendered `json:"content"`
+	Kind     string   `json:"kind"`     // "bug", "enhancement", "proposal", "task".
+	Priority string   `json:"priority"` // "trivial", "minor", "major", "critical", "blocker".
+	State    string   `json:"state"`    // "new", "open", "resolved", "on hold", "invalid", "duplicate", "wontfix", "closed".
+
+	Reporter Account  `json:"reporter"`
+	Assignee *Account `json:"assignee,omitempty"`
+
+	CreatedOn string `json:"created_on"`
+	UpdatedOn string `json:"updated_on"`
+
+	Links map[string]Link `json:"links"`
+}
+
 type Link = bitbucket.Link
 
 type Participant struct {
@@ -131,6 +151,8 @@ type Reference struct {
 	Repository Repository `json:"repository"`
 }
 
+type Rendered = bitbucket.Rendered
+
 type Repository struct {
 	// Type string `json:"type"` // Always "repository".
 
@@ -0,0 +1,85 @@
+package bitbucket
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	slack "github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// LogExcerptLines is the maximum number of lines in a failed step's log excerpt.
+const LogExcerptLines = 40
+
+var (
+	// pipelineURLPattern matches both the current and the legacy URL formats of Bitbucket Pipelines results.
+	pipelineURLPattern = regexp.MustCompile(`^https://bitbucket\.org/([^/]+)/([^/]+)/(?:addon/pipelines/home#!/|pipelines/)results/(\d+)`)
+
+	ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	logErrorPattern   = regexp.MustCompile(`(?i)(^|[^a-z])(error|fail(ed|ure)?|exception|panic|fatal)([^a-z]|$)`)
+)
+
+// PostFailedStepLog posts an excerpt of the log of the first failed step in a Bitbucket Pipelines
+// run, as a text file in the thread of the given Slack message (which announced the failed build).
+// This is a best-effort convenience for reviewers, so errors are logged but not returned.
+func PostFailedStepLog(ctx workflow.Context, channelID, threadTS, buildURL string) {
+	url := pipelineURLPattern.FindStringSubmatch(buildURL)
+	if url == nil {
+		return // Not a Bitbucket Pipelines build.
+	}
+
+	steps, err := activities.ListPipelineSteps(ctx, url[1], url[2], url[3])
+	if err != nil {
+		return
+	}
+
+	for _, step := range steps {
+		if r := step.State.Result; r == nil || (r.Name != "FAILED" && r.Name != "ERROR") {
+			continue
+		}
+
+		log, err := activities.GetPipelineStepLog(ctx, url[1], url[2], url[3], step.UUID)
+		if err != nil || strings.TrimSpace(log) == "" {
+			return
+		}
+
+		filename := fmt.Sprintf("pipeline-%s-step.log", url[3])
+		title := fmt.Sprintf("Log excerpt of failed step: %s", step.Name)
+		if _, err := slack.Upload(ctx, []byte(logExcerpt(log, LogExcerptLines)), filename, title, "text", "text/plain", channelID, threadTS); err != nil {
+			logger.From(ctx).Warn("failed to post Bitbucket pipeline step log excerpt",
+				slog.Any("error", err), slog.String("build_url", buildURL))
+		}
+		return // Only the first failed step is relevant.
+	}
+}
+
+// logExcerpt extracts the most relevant part of a build log: the first block of lines (i.e. between
+// empty lines) which contains errors, starting a few lines of context before its first error, within
+// the same block. Later errors are often just consequences of the first one. If the block is too long,
+// the excerpt is truncated. If there are no error lines at all, it returns the last lines of the log.
+func logExcerpt(log string, maxLines int) string {
+	log = ansiEscapePattern.ReplaceAllString(log, "")
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(log, "\r\n", "\n"), "\n"), "\n")
+
+	first := slices.IndexFunc(lines, logErrorPattern.MatchString)
+	if first < 0 {
+		return strings.Join(lines[max(0, len(lines)-maxLines):], "\n") + "\n"
+	}
+
+	start := first
+	for start > max(0, first-3) && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	end := first + 1
+	for end < len(lines) && end < start+maxLines && strings.TrimSpace(lines[end]) != "" {
+		end++
+	}
+
+	return strings.Join(lines[start:end], "\n") + "\n"
+}
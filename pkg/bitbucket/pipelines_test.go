@@ -0,0 +1,125 @@
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLogExcerpt(t *testing.T) {
+	var long strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+
+	tests := []struct {
+		name     string
+		log      string
+		maxLines int
+		want     string
+	}{
+		{
+			name:     "no_errors_short",
+			log:      "a\nb\nc\n",
+			maxLines: 5,
+			want:     "a\nb\nc\n",
+		},
+		{
+			name:     "no_errors_long",
+			log:      long.String(),
+			maxLines: 3,
+			want:     "line 97\nline 98\nline 99\n",
+		},
+		{
+			name:     "error_block",
+			log:      "+ go test ./...\nok  pkg/a\nok  pkg/b\nok  pkg/c\n--- FAIL: TestFoo\n    foo_test.go:12: got 1, want 2\nFAILED\n\nmore output\n",
+			maxLines: 10,
+			want:     "ok  pkg/a\nok  pkg/b\nok  pkg/c\n--- FAIL: TestFoo\n    foo_test.go:12: got 1, want 2\nFAILED\n",
+		},
+		{
+			name:     "error_block_truncated",
+			log:      "Error: something\n1\n2\n3\n4\n5\n",
+			maxLines: 3,
+			want:     "Error: something\n1\n2\n",
+		},
+		{
+			name:     "first_error_block",
+			log:      "fetch\nError: not found\nexit 1\n\nbuild\nlink\nerror: undefined symbol\nexit 1\n",
+			maxLines: 10,
+			want:     "fetch\nError: not found\nexit 1\n",
+		},
+		{
+			name:     "context_within_block",
+			log:      "setup\n\nbuild\nerror: bad\n",
+			maxLines: 10,
+			want:     "build\nerror: bad\n",
+		},
+		{
+			name:     "long_block_truncated",
+			log:      "error: 1\n2\n3\n4\n5\nerror: 6\n7\n8\n",
+			maxLines: 3,
+			want:     "error: 1\n2\n3\n",
+		},
+		{
+			name:     "ansi_escapes_and_crlf",
+			log:      "build\r\n\x1b[31merror\x1b[0m: bad\r\n",
+			maxLines: 10,
+			want:     "build\nerror: bad\n",
+		},
+		{
+			name:     "not_an_error_word",
+			log:      "terrorist\nmirrored\n",
+			maxLines: 10,
+			want:     "terrorist\nmirrored\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := logExcerpt(tt.log, tt.maxLines); got != tt.want {
+				t.Errorf("logExcerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineURLPattern(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{
+			name: "current",
+			url:  "https://bitbucket.org/workspace/repo/pipelines/results/123",
+			want: []string{"workspace", "repo", "123"},
+		},
+		{
+			name: "with_step",
+			url:  "https://bitbucket.org/workspace/repo/pipelines/results/123/steps/{abc}",
+			want: []string{"workspace", "repo", "123"},
+		},
+		{
+			name: "legacy",
+			url:  "https://bitbucket.org/workspace/repo/addon/pipelines/home#!/results/45",
+			want: []string{"workspace", "repo", "45"},
+		},
+		{
+			name: "external_ci",
+			url:  "https://ci.example.com/job/123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pipelineURLPattern.FindStringSubmatch(tt.url)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("FindStringSubmatch() = %q, want nil", got)
+				}
+				return
+			}
+			if len(got) != 4 || got[1] != tt.want[0] || got[2] != tt.want[1] || got[3] != tt.want[2] {
+				t.Errorf("FindStringSubmatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,15 +3,15 @@ package bitbucket
 import (
 	"fmt"
 	"log/slog"
-	"maps"
 	"regexp"
-	"slices"
 	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/markdown"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
 	"github.com/tzrikka/timpani-api/pkg/slack"
 )
 
@@ -19,11 +19,15 @@ const (
 	maxBookmarkTitleLen = 200
 )
 
-func newBookmarkTitles(pr PullRequest, files int) []string {
+var trailingDots = regexp.MustCompile(`\.+$`)
+
+const tasksBookmarkIndex = 2
+
+func newBookmarkTitles(pr PullRequest, files, taskCount int, openTasks []string) []string {
 	return []string{
 		fmt.Sprintf("Reviewers (%d)", len(accountIDs(pr.Reviewers))),
 		fmt.Sprintf("Comments (%d)", pr.CommentCount),
-		fmt.Sprintf("Tasks (%d)", pr.TaskCount),
+		tasksBookmarkTitle(taskCount, openTasks),
 		fmt.Sprintf("Approvals (%d)", countApprovals(pr)),
 		fmt.Sprintf("Commits (%d)", pr.CommitCount),
 		fmt.Sprintf("Files changed (%d)", files),
@@ -32,7 +36,8 @@ func newBookmarkTitles(pr PullRequest, files int) []string {
 }
 
 func SetChannelBookmarks(ctx workflow.Context, channelID, prURL string, pr PullRequest) {
-	titles := newBookmarkTitles(pr, len(data.LoadDiffstatPaths(ctx, prURL)))
+	taskCount, open := openTasks(ctx, prURL, pr.TaskCount)
+	titles := newBookmarkTitles(pr, len(data.LoadDiffstatPaths(ctx, prURL)), taskCount, open)
 	_ = slack.BookmarksAdd(ctx, channelID, titles[0], prURL+"/overview", ":eyes:")
 	_ = slack.BookmarksAdd(ctx, channelID, titles[1], prURL+"/overview", ":speech_balloon:")
 	_ = slack.BookmarksAdd(ctx, channelID, titles[2], prURL+"/overview", ":white_check_mark:")
@@ -51,7 +56,8 @@ func UpdateChannelBookmarks(ctx workflow.Context, pr PullRequest, prURL, channel
 		return
 	}
 
-	newTitles := newBookmarkTitles(pr, len(data.LoadDiffstatPaths(ctx, prURL)))
+	taskCount, open := openTasks(ctx, prURL, pr.TaskCount)
+	newTitles := newBookmarkTitles(pr, len(data.LoadDiffstatPaths(ctx, prURL)), taskCount, open)
 	for i, b := range bookmarks {
 		if i >= len(newTitles) {
 			break
@@ -67,57 +73,75 @@ func UpdateChannelBookmarks(ctx workflow.Context, pr PullRequest, prURL, channel
 // UpdateChannelBuildsBookmark updates the "Builds" bookmark in the PR's Slack channel, based on the latest repository
 // event. This is a deferred call that doesn't return an error, because handling the event itself is more important.
 func UpdateChannelBuildsBookmark(ctx workflow.Context, channelID, prURL string) {
+	activities.UpdateBuildsBookmark(ctx, channelID, prURL, "Builds", func(b data.CommitStatus) string {
+		return trailingDots.ReplaceAllString(strings.TrimSpace(b.Desc), "")
+	})
+}
+
+// UpdateChannelTasksBookmark updates the "Tasks" bookmark in the PR's Slack channel, based on the last-known
+// state of the PR's tasks (see [SyncTasks]), for task changes which aren't accompanied by any other PR event.
+// This is a deferred call that doesn't return an error, because handling the event itself is more important.
+func UpdateChannelTasksBookmark(ctx workflow.Context, channelID, prURL string) {
 	bookmarks, err := slack.BookmarksList(ctx, channelID)
 	if err != nil {
 		logger.From(ctx).Error("failed to list Slack channel bookmarks", slog.Any("error", err))
 		return
 	}
-	if len(bookmarks) < 7 {
+	if len(bookmarks) <= tasksBookmarkIndex {
 		return
 	}
 
-	prStatus := data.ReadBitbucketBuilds(ctx, prURL)
-
-	sb := new(strings.Builder)
-	sb.WriteString("Builds: ")
-	if len(prStatus.Builds) == 0 {
-		sb.WriteString("no results")
-	}
-
-	keys := slices.Sorted(maps.Keys(prStatus.Builds)) //workflowcheck:ignore // Sorted for deterministic order.
-	for i, k := range keys {
-		if i > 0 {
-			sb.WriteString(" ")
+	title := tasksBookmarkTitle(openTasks(ctx, prURL, 0))
+	if b := bookmarks[tasksBookmarkIndex]; title != b.Title {
+		if err := slack.BookmarksEditTitle(ctx, channelID, b.ID, title); err != nil {
+			logger.From(ctx).Error("failed to update Slack channel bookmark", slog.Any("error", err), slog.String("title", title))
 		}
-
-		b := prStatus.Builds[k]
-		desc := regexp.MustCompile(`\.+$`).ReplaceAllString(strings.TrimSpace(b.Desc), "")
-		fmt.Fprintf(sb, "%s %s", buildState(b.State), desc) //workflowcheck:ignore // Deterministic output, not a file.
 	}
+}
 
-	title := sb.String()
-	if len(title) > maxBookmarkTitleLen {
-		title = title[:maxBookmarkTitleLen]
+// AddIssueBookmarks adds bookmarks to the PR's Slack channel for issues which are referenced in
+// the PR's title or description, unless they are already bookmarked. Stale issue bookmarks are not
+// removed, because they may still be relevant. This is a deferred call that doesn't return an error.
+func AddIssueBookmarks(ctx workflow.Context, channelID string, issues []markdown.IssueLink) {
+	if len(issues) == 0 {
+		return
 	}
 
-	if title == bookmarks[6].Title {
+	bookmarks, err := slack.BookmarksList(ctx, channelID)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Slack channel bookmarks", slog.Any("error", err))
 		return
 	}
 
-	if err := slack.BookmarksEditTitle(ctx, channelID, bookmarks[6].ID, title); err != nil {
-		logger.From(ctx).Error("failed to update Slack channel's builds bookmark", slog.Any("error", err))
+	links := map[string]bool{}
+	for _, b := range bookmarks {
+		if b.Link != nil {
+			links[*b.Link] = true
+		}
+	}
+
+	for _, issue := range issues {
+		if links[issue.URL] {
+			continue
+		}
+		if err := slack.BookmarksAdd(ctx, channelID, "Issue: "+issue.ID, issue.URL, ":ticket:"); err != nil {
+			logger.From(ctx).Error("failed to add Slack channel bookmark", slog.Any("error", err), slog.String("issue_url", issue.URL))
+		}
 	}
 }
 
-func buildState(state string) string {
-	switch state {
-	case "INPROGRESS":
-		return "[?]"
-	case "SUCCESSFUL":
-		return "[V]"
-	default: // "FAILED", "STOPPED".
-		return "[X]"
+// tasksBookmarkTitle lists the texts of a PR's open tasks (if they are known) after their count.
+func tasksBookmarkTitle(count int, openTasks []string) string {
+	title := fmt.Sprintf("Tasks (%d)", count)
+	if len(openTasks) > 0 {
+		title += ": " + strings.Join(openTasks, " | ")
+	}
+
+	// Truncate by runes, not bytes, to avoid splitting multi-byte characters.
+	if r := []rune(title); len(r) > maxBookmarkTitleLen {
+		title = string(r[:maxBookmarkTitleLen-3]) + "..."
 	}
+	return title
 }
 
 func countApprovals(pr PullRequest) int {
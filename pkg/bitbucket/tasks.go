@@ -0,0 +1,166 @@
+package bitbucket
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/markdown"
+	slack "github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// SyncTasks mirrors changes in a Bitbucket PR's tasks in its Slack channel: it announces created,
+// resolved and reopened tasks, and maintains a checklist message, which is pinned if pinChecklist is true.
+//
+// Bitbucket doesn't send webhook events for tasks, so this function is called whenever they're likely
+// to change: after PR comment events, in every round of the PR's comment reconciliation workflow, and
+// after creating a task in Slack. It returns true if the PR's tasks (including their texts) changed.
+func SyncTasks(ctx workflow.Context, channelID, prURL string, pinChecklist bool) bool {
+	tasks, err := activities.ListPullRequestTasks(ctx, "", prURL)
+	if err != nil {
+		return false // The error is already logged.
+	}
+
+	state := data.ReadTasks(ctx, prURL)
+	curr := convertTasks(tasks)
+	if state.Tasks != nil && slices.Equal(state.Tasks, curr) {
+		return false
+	}
+
+	// The first sync of a PR's tasks (e.g. in a PR which predates this feature)
+	// only records them, instead of announcing all of them as newly-created.
+	if state.Tasks != nil {
+		announceTasks(ctx, channelID, state.Tasks, curr, tasks)
+	}
+
+	state.Tasks = curr
+	state.ChecklistTS = updateTasksChecklist(ctx, channelID, state.ChecklistTS, curr, pinChecklist)
+	data.WriteTasks(ctx, prURL, state)
+
+	return true
+}
+
+// announceTasks posts a Slack message for each task which was created, resolved, or reopened since the last sync.
+func announceTasks(ctx workflow.Context, channelID string, prev, curr []data.Task, tasks []bitbucket.Task) {
+	created, resolved, reopened := diffTasks(prev, curr)
+	for _, i := range created {
+		MentionUserInMsg(ctx, channelID, tasks[i].Creator, "%s created a task: "+markdown.EscapeSlack(curr[i].Text))
+	}
+	for _, i := range resolved {
+		if u := tasks[i].ResolvedBy; u != nil {
+			MentionUserInMsg(ctx, channelID, *u, "%s resolved a task: "+markdown.EscapeSlack(curr[i].Text))
+		} else {
+			_ = slack.PostMessage(ctx, channelID, "A task was resolved: "+markdown.EscapeSlack(curr[i].Text))
+		}
+	}
+	for _, i := range reopened {
+		_ = slack.PostMessage(ctx, channelID, "A task was reopened: "+markdown.EscapeSlack(curr[i].Text))
+	}
+}
+
+// openTasks returns the number and texts of a PR's open tasks, based on their last-known state (see
+// [SyncTasks]), without calling the Bitbucket API. If they were never synced, it returns the given count.
+func openTasks(ctx workflow.Context, prURL string, count int) (int, []string) {
+	state := data.ReadTasks(ctx, prURL)
+	if state.Tasks == nil {
+		return count, nil
+	}
+
+	texts := openTaskTexts(state.Tasks)
+	return len(texts), texts
+}
+
+// updateTasksChecklist posts (and optionally pins) or updates the checklist message of a PR's
+// tasks in its Slack channel. It returns the message's timestamp, which may be empty
+// if the PR doesn't have any tasks yet, or if posting the message failed.
+func updateTasksChecklist(ctx workflow.Context, channelID, ts string, tasks []data.Task, pin bool) string {
+	if ts != "" {
+		_ = slack.UpdateMessage(ctx, channelID, ts, tasksChecklist(tasks))
+		return ts
+	}
+
+	if len(tasks) == 0 {
+		return ""
+	}
+
+	resp, err := slack.PostReply(ctx, channelID, "", tasksChecklist(tasks))
+	if err != nil {
+		return ""
+	}
+
+	if pin {
+		_ = slack.PinMessage(ctx, channelID, resp.TS) // Nice to have, but not critical.
+	}
+	return resp.TS
+}
+
+// convertTasks always returns a non-nil slice, to distinguish between a
+// PR without tasks and a PR whose tasks were never synced (see [openTasks]).
+func convertTasks(tasks []bitbucket.Task) []data.Task {
+	converted := make([]data.Task, 0, len(tasks))
+	for _, t := range tasks {
+		text, _, _ := strings.Cut(strings.TrimSpace(t.Content.Raw), "\n")
+		converted = append(converted, data.Task{ID: t.ID, Text: text, Resolved: t.State == "RESOLVED"})
+	}
+	return converted
+}
+
+// diffTasks compares the last-known state of a PR's tasks with their current state, and returns
+// the indices of current tasks which were created, resolved, or reopened since the last check.
+func diffTasks(prev, curr []data.Task) (created, resolved, reopened []int) {
+	known := make(map[int]data.Task, len(prev))
+	for _, t := range prev {
+		known[t.ID] = t
+	}
+
+	for i, t := range curr {
+		old, found := known[t.ID]
+		switch {
+		case !found:
+			created = append(created, i)
+		case t.Resolved && !old.Resolved:
+			resolved = append(resolved, i)
+		case !t.Resolved && old.Resolved:
+			reopened = append(reopened, i)
+		}
+	}
+
+	return created, resolved, reopened
+}
+
+// tasksChecklist formats a PR's tasks as a Slack message.
+func tasksChecklist(tasks []data.Task) string {
+	done := 0
+	for _, t := range tasks {
+		if t.Resolved {
+			done++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":clipboard: *Tasks:* %d of %d resolved", done, len(tasks)) //workflowcheck:ignore // Deterministic output, not a file.
+	for _, t := range tasks {
+		if t.Resolved {
+			fmt.Fprintf(&sb, "\n:ballot_box_with_check: ~%s~", markdown.EscapeSlack(t.Text)) //workflowcheck:ignore // Deterministic output, not a file.
+		} else {
+			fmt.Fprintf(&sb, "\n:white_large_square: %s", markdown.EscapeSlack(t.Text)) //workflowcheck:ignore // Deterministic output, not a file.
+		}
+	}
+
+	return sb.String()
+}
+
+func openTaskTexts(tasks []data.Task) []string {
+	texts := []string{}
+	for _, t := range tasks {
+		if !t.Resolved {
+			texts = append(texts, t.Text)
+		}
+	}
+	return texts
+}
@@ -0,0 +1,172 @@
+package bitbucket
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+func TestDiffTasks(t *testing.T) {
+	prev := []data.Task{
+		{ID: 1, Text: "unchanged"},
+		{ID: 2, Text: "to be resolved"},
+		{ID: 3, Text: "to be reopened", Resolved: true},
+		{ID: 4, Text: "to be deleted"},
+	}
+	curr := []data.Task{
+		{ID: 1, Text: "unchanged"},
+		{ID: 2, Text: "to be resolved", Resolved: true},
+		{ID: 3, Text: "to be reopened"},
+		{ID: 5, Text: "new"},
+	}
+
+	created, resolved, reopened := diffTasks(prev, curr)
+	if want := []int{3}; !reflect.DeepEqual(created, want) {
+		t.Errorf("diffTasks() created = %v, want %v", created, want)
+	}
+	if want := []int{1}; !reflect.DeepEqual(resolved, want) {
+		t.Errorf("diffTasks() resolved = %v, want %v", resolved, want)
+	}
+	if want := []int{2}; !reflect.DeepEqual(reopened, want) {
+		t.Errorf("diffTasks() reopened = %v, want %v", reopened, want)
+	}
+}
+
+func TestSyncTasks(t *testing.T) {
+	const prURL = "https://bitbucket.org/workspace/repo/pull-requests/1"
+
+	tests := []struct {
+		name         string
+		state        data.PRTasks
+		pinChecklist bool
+		want         []string
+	}{
+		{
+			name:         "first_sync",
+			pinChecklist: true,
+			want:         []string{"slack.chat.postMessage", "slack.pins.add"},
+		},
+		{
+			name: "first_sync_without_pin",
+			want: []string{"slack.chat.postMessage"},
+		},
+		{
+			name:  "reopened_task",
+			state: data.PRTasks{Tasks: []data.Task{{ID: 1, Text: "foo", Resolved: true}}, ChecklistTS: "T1"},
+			want:  []string{"slack.chat.postMessage", "slack.chat.update"},
+		},
+		{
+			name:  "no_changes",
+			state: data.PRTasks{Tasks: []data.Task{{ID: 1, Text: "foo"}}, ChecklistTS: "T1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			if tt.state.Tasks != nil {
+				data.WriteTasks(nil, prURL, tt.state)
+			}
+
+			s := testsuite.WorkflowTestSuite{}
+			env := s.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(func(ctx workflow.Context) (bool, error) {
+				return SyncTasks(ctx, "C1", prURL, tt.pinChecklist), nil
+			}, workflow.RegisterOptions{Name: "sync_tasks"})
+
+			listTasks := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+				return map[string]any{"values": []bitbucket.Task{{ID: 1, Content: bitbucket.Rendered{Raw: "foo"}, State: "UNRESOLVED"}}}, nil
+			}
+			env.RegisterActivityWithOptions(listTasks, activity.RegisterOptions{Name: "bitbucket.pullrequests.listTasks"})
+
+			calls := []string{}
+			for _, name := range []string{"slack.chat.postMessage", "slack.chat.update", "slack.pins.add"} {
+				env.RegisterActivityWithOptions(func(_ context.Context, _ map[string]any) (map[string]any, error) {
+					calls = append(calls, name)
+					return map[string]any{"ok": true, "channel": "C1", "ts": "T2"}, nil
+				}, activity.RegisterOptions{Name: name})
+			}
+
+			env.ExecuteWorkflow("sync_tasks")
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("SyncTasks() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("SyncTasks() error = %v", err)
+			}
+
+			if !slices.Equal(calls, tt.want) {
+				t.Errorf("SyncTasks() calls = %q, want %q", calls, tt.want)
+			}
+			if got := data.ReadTasks(nil, prURL).Tasks; !reflect.DeepEqual(got, []data.Task{{ID: 1, Text: "foo"}}) {
+				t.Errorf("ReadTasks() = %v, want the current task", got)
+			}
+		})
+	}
+}
+
+func TestTasksChecklist(t *testing.T) {
+	tasks := []data.Task{
+		{ID: 1, Text: "foo", Resolved: true},
+		{ID: 2, Text: "bar <baz>"},
+	}
+
+	got := tasksChecklist(tasks)
+	want := ":clipboard: *Tasks:* 1 of 2 resolved\n:ballot_box_with_check: ~foo~\n:white_large_square: bar &lt;baz&gt;"
+	if got != want {
+		t.Errorf("tasksChecklist() = %q, want %q", got, want)
+	}
+}
+
+func TestTasksBookmarkTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		openTasks []string
+		want      string
+	}{
+		{
+			name: "no_tasks",
+			want: "Tasks (0)",
+		},
+		{
+			name:  "unknown_tasks",
+			count: 2,
+			want:  "Tasks (2)",
+		},
+		{
+			name:      "known_tasks",
+			count:     2,
+			openTasks: []string{"foo", "bar"},
+			want:      "Tasks (2): foo | bar",
+		},
+		{
+			name:      "truncated",
+			count:     1,
+			openTasks: []string{strings.Repeat("a", 300)},
+			want:      "Tasks (1): " + strings.Repeat("a", maxBookmarkTitleLen-14) + "...",
+		},
+		{
+			name:      "truncated_multibyte",
+			count:     1,
+			openTasks: []string{strings.Repeat("ש", 300)},
+			want:      "Tasks (1): " + strings.Repeat("ש", maxBookmarkTitleLen-14) + "...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tasksBookmarkTitle(tt.count, tt.openTasks); got != tt.want {
+				t.Errorf("tasksBookmarkTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package workflows
+
+import (
+	"log/slog"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/otel"
+	"github.com/tzrikka/revchat/pkg/bitbucket"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+)
+
+// DataCenterSignals is a list of signal names that RevChat receives from Timpani, to
+// trigger event handling workflows for self-hosted Bitbucket Data Center instances.
+// These events are converted into their Bitbucket Cloud equivalents, and handled by the
+// same workflows as [PullRequestSignals], so they don't need to be registered separately.
+// RevChat listens to them only if the "bitbucket-datacenter" flag is set.
+//
+// This is based on:
+//   - https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Pullrequest
+//   - https://github.com/tzrikka/revchat/blob/main/docs/setup/bitbucket.md#bitbucket-data-center
+var DataCenterSignals = []string{
+	"bitbucket.datacenter.events.pr.opened",
+	"bitbucket.datacenter.events.pr.modified",
+	"bitbucket.datacenter.events.pr.from_ref_updated",
+	"bitbucket.datacenter.events.pr.reviewer.updated",
+	"bitbucket.datacenter.events.pr.reviewer.approved",
+	"bitbucket.datacenter.events.pr.reviewer.unapproved",
+	"bitbucket.datacenter.events.pr.reviewer.needs_work",
+	"bitbucket.datacenter.events.pr.merged",
+	"bitbucket.datacenter.events.pr.declined",
+	"bitbucket.datacenter.events.pr.deleted",
+
+	"bitbucket.datacenter.events.pr.comment.added",
+	"bitbucket.datacenter.events.pr.comment.edited",
+	"bitbucket.datacenter.events.pr.comment.deleted",
+}
+
+// RegisterDataCenterSignals routes [DataCenterSignals] to the registered workflows of [PullRequestSignals].
+func RegisterDataCenterSignals(ctx workflow.Context, sel workflow.Selector) {
+	for _, signalName := range DataCenterSignals {
+		sel.AddReceive(workflow.GetSignalChannel(ctx, signalName), func(ch workflow.ReceiveChannel, _ bool) {
+			payload := new(datacenter.Event)
+			ch.Receive(ctx, payload)
+
+			signal := ch.Name()
+			otel.SignalReceived(ctx, signal, false)
+
+			if event := convertDataCenterEvent(ctx, signal, payload); event != nil {
+				executePRWorkflow(ctx, event)
+			}
+		})
+	}
+}
+
+// DrainDataCenterSignals drains all pending [DataCenterSignals] channels,
+// and waits for their corresponding workflow executions to complete in order.
+// This is called in preparation for resetting the dispatcher workflow's history.
+func DrainDataCenterSignals(ctx workflow.Context) bool {
+	totalEvents := 0
+	for _, signal := range DataCenterSignals {
+		ch := workflow.GetSignalChannel(ctx, signal)
+		signalEvents := 0
+		for {
+			payload := new(datacenter.Event)
+			if !ch.ReceiveAsync(payload) {
+				break
+			}
+
+			otel.SignalReceived(ctx, signal, true)
+			signalEvents++
+
+			event := convertDataCenterEvent(ctx, signal, payload)
+			if event == nil {
+				continue
+			}
+
+			ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+				WorkflowID: prChildWorkflowID(ctx, event),
+			})
+			_ = workflow.ExecuteChildWorkflow(ctx, "bitbucket.events.pullrequest."+event.Type, event).Get(ctx, nil)
+		}
+
+		if signalEvents > 0 {
+			logger.From(ctx).Info("drained signal channel",
+				slog.String("signal", signal), slog.Int("event_count", signalEvents))
+		}
+		totalEvents += signalEvents
+	}
+	return totalEvents > 0
+}
+
+// convertDataCenterEvent is a wrapper around [bitbucket.ConvertDataCenterEvent], which
+// also falls back to the signal name if the event key is missing, and logs errors.
+func convertDataCenterEvent(ctx workflow.Context, signal string, payload *datacenter.Event) *bitbucket.PullRequestEvent {
+	if payload.EventKey == "" {
+		payload.EventKey = strings.ReplaceAll(strings.TrimPrefix(signal, "bitbucket.datacenter.events."), ".", ":")
+	}
+
+	event, err := bitbucket.ConvertDataCenterEvent(*payload)
+	if err != nil {
+		logger.From(ctx).Error("failed to convert Bitbucket Data Center event", slog.Any("error", err),
+			slog.String("signal", signal), slog.String("event_key", payload.EventKey))
+		return nil
+	}
+
+	return event
+}
@@ -13,6 +13,8 @@ import (
 
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/pkg/bitbucket"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/markdown"
 	"github.com/tzrikka/revchat/pkg/slack"
@@ -44,6 +46,7 @@ func (c Config) PullRequestCreatedWorkflow(ctx workflow.Context, event bitbucket
 	activities.SetChannelTopic(ctx, channelID, prURL)
 	activities.SetChannelDescription(ctx, c.TemporalOpts, channelID, pr.Title, prURL, "")
 	bitbucket.SetChannelBookmarks(ctx, channelID, prURL, pr)
+	bitbucket.AddIssueBookmarks(ctx, channelID, markdown.IssueLinks(ctx, c.LinkifyMap, pr.Title+"\n"+pr.Description))
 
 	msg := "%s created this PR: " + markdown.LinkifyTitle(ctx, c.LinkifyMap, prURL, pr.Title)
 	if desc := strings.TrimSpace(pr.Description); desc != "" && desc != pr.Title {
@@ -52,7 +55,7 @@ func (c Config) PullRequestCreatedWorkflow(ctx workflow.Context, event bitbucket
 	bitbucket.MentionUserInMsg(ctx, channelID, event.Actor, msg)
 
 	followerIDs := data.SelectUserByBitbucketID(ctx, pr.Author.AccountID).Followers
-	followerIDs = discardUsersWithoutAccess(ctx, followerIDs, pr.Destination.Repository.FullName, pr.ID)
+	followerIDs = discardUsersWithoutAccess(ctx, followerIDs, prURL, pr.Destination.Repository.FullName, pr.ID)
 
 	err = activities.InviteUsersToChannel(ctx, c.TemporalOpts, channelID, prURL, bitbucket.ChannelMembers(ctx, pr), followerIDs)
 	if err != nil {
@@ -66,12 +69,13 @@ func (c Config) PullRequestCreatedWorkflow(ctx workflow.Context, event bitbucket
 		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to invite users to Slack channel for "+prURL, err)
 	}
 
+	c.autoAddReviewers(ctx, channelID, prURL, pr)
 	return nil
 }
 
 // discardUsersWithoutAccess ensures that each Slack user (a follower of the
 // PR author) has access to the PR before adding them to the PR's Slack channel.
-func discardUsersWithoutAccess(ctx workflow.Context, slackUserIDs []string, repoFullName string, prID int) []string {
+func discardUsersWithoutAccess(ctx workflow.Context, slackUserIDs []string, prURL, repoFullName string, prID int) []string {
 	workspace, repo, found := strings.Cut(repoFullName, "/")
 	if !found {
 		return nil // "Fail closed".
@@ -80,20 +84,24 @@ func discardUsersWithoutAccess(ctx workflow.Context, slackUserIDs []string, repo
 
 	filtered := make([]string, 0, len(slackUserIDs))
 	for _, slackUserID := range slackUserIDs {
-		if userHasAccess(ctx, slackUserID, workspace, repo, id) {
+		if userHasAccess(ctx, slackUserID, prURL, workspace, repo, id) {
 			filtered = append(filtered, slackUserID)
 		}
 	}
 	return filtered
 }
 
-func userHasAccess(ctx workflow.Context, slackUserID, workspace, repo, prID string) bool {
+func userHasAccess(ctx workflow.Context, slackUserID, prURL, workspace, repo, prID string) bool {
 	// No need error checking here: if there's an error here, optedIn will be false anyway.
 	user, optedIn, _ := data.SelectUserBySlackID(ctx, slackUserID)
 	if !optedIn {
 		return false
 	}
 
+	if datacenter.IsURL(prURL) {
+		return bbactivities.CanAccessDataCenterPullRequest(ctx, user.ThrippyLink, prURL)
+	}
+
 	// We use the [timpani] repo, instead of the [activities] package, because
 	// we don't want to treat (i.e. log) API call failures here as server errors.
 	resp, _ := timpani.PullRequestsGet(ctx, user.ThrippyLink, workspace, repo, prID)
@@ -203,6 +211,10 @@ func (c Config) PullRequestUpdatedWorkflow(ctx workflow.Context, event bitbucket
 		data.UpdateActivityTime(ctx, c.TemporalOpts, prURL, email)
 	}
 
+	if snapshot.Title != pr.Title || snapshot.Description != pr.Description {
+		bitbucket.AddIssueBookmarks(ctx, channelID, markdown.IssueLinks(ctx, c.LinkifyMap, pr.Title+"\n"+pr.Description))
+	}
+
 	// Reviewers added/removed.
 	added, removed := bitbucket.ReviewersDiff(*snapshot, pr)
 	if len(added)+len(removed) > 0 {
@@ -285,6 +297,12 @@ func (c Config) PullRequestReviewedWorkflow(ctx workflow.Context, event bitbucke
 	case "approved":
 		msg += "approved this PR. :+1:"
 
+		// Bitbucket Data Center reports approvals of PRs which the reviewer
+		// previously marked as "needs work" without a separate removal event.
+		if event.ChangesRequest != nil {
+			pr.ChangeRequestCount = max(pr.ChangeRequestCount-1, 0)
+		}
+
 		err = data.RemoveReviewerFromTurns(ctx, c.TemporalOpts, prURL, email, true)
 		if err != nil {
 			_ = activities.AlertError(ctx, c.SlackAlertsChannel, "failed to remove approver from PR turns", err, "Email", email)
@@ -324,7 +342,7 @@ func (c Config) PullRequestReviewedWorkflow(ctx workflow.Context, event bitbucke
 	}
 
 	_, err2 := bitbucket.SwitchPRSnapshot(ctx, prURL, pr)
-	if strings.HasPrefix(event.Type, "changes_request") && err2 != nil {
+	if (strings.HasPrefix(event.Type, "changes_request") || event.ChangesRequest != nil) && err2 != nil {
 		_ = activities.AlertError(ctx, c.SlackAlertsChannel, "failed to update change-request count in PR snapshot",
 			err2, "PR", prURL, "New count", pr.ChangeRequestCount)
 		err = errors.Join(err, err2)
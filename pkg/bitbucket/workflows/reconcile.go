@@ -0,0 +1,380 @@
+package workflows
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket"
+	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	slack "github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+const (
+	// ReconcileCommentsWorkflowName is the registered name of [Config.ReconcileCommentsWorkflow].
+	ReconcileCommentsWorkflowName = "bitbucket.workflows.reconcile_comments"
+	// ReconcileCommentSignal adds, updates, or removes a [TrackedComment] in a running [Config.ReconcileCommentsWorkflow].
+	ReconcileCommentSignal = "bitbucket.reconcile.comment"
+
+	// LegacyPollCommentWorkflowName is the registered name of [Config.LegacyPollCommentWorkflow].
+	LegacyPollCommentWorkflowName = "bitbucket.schedules.poll_comment"
+	// legacyCleanupSchedule is the ID of the Temporal schedule which was used to delete obsolete
+	// per-comment polling schedules, before they were replaced by [Config.ReconcileCommentsWorkflow].
+	legacyCleanupSchedule = "bitbucket.schedules.polling_cleanup"
+)
+
+const (
+	// CommentPollingWindow is how long a PR comment is tracked after its creation or last update,
+	// to match the duration of Bitbucket's [10-minute silent window].
+	//
+	// [10-minute silent window]: https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Comment-updated
+	CommentPollingWindow = 10 * time.Minute
+	// CommentPollingInterval is the initial (and shortest) time between reconciliation rounds. It is doubled after
+	// every quiet round (i.e. without any detected changes), up to [CommentPollingMaxInterval], and reset whenever
+	// a comment is created or updated.
+	CommentPollingInterval    = 15 * time.Second
+	CommentPollingMaxInterval = 2 * time.Minute
+
+	// commentListMargin compensates for clock skew and indexing delays when listing
+	// recently-updated PR comments in Bitbucket, to avoid missing any of them.
+	commentListMargin = time.Minute
+)
+
+// TrackedComment is a PR comment which [Config.ReconcileCommentsWorkflow]
+// checks for edits and deletions, and also the payload of [ReconcileCommentSignal].
+type TrackedComment struct {
+	URL       string    `json:"url"`
+	ThrippyID string    `json:"thrippy_id,omitempty"`
+	Checksum  string    `json:"checksum,omitempty"` // An empty checksum in a signal means "stop tracking this comment".
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReconcileCommentsRequest is the input to [Config.ReconcileCommentsWorkflow]. It is
+// also used to carry over the workflow's state when it continues as a new run.
+type ReconcileCommentsRequest struct {
+	PRURL     string                    `json:"pr_url"`
+	Comments  map[string]TrackedComment `json:"comments,omitempty"`
+	Interval  time.Duration             `json:"interval,omitempty"`
+	NextRound time.Time                 `json:"next_round,omitzero"`
+}
+
+func checksum(s string) string {
+	h := sha256.New()
+	h.Write([]byte(s))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// prURLPattern extracts a Bitbucket Cloud PR's URL from the URL of one of its comments.
+var prURLPattern = regexp.MustCompile(`^https://[^/]+/[^/]+/[^/]+/pull-requests/\d+`)
+
+func reconcileWorkflowID(prURL string) string {
+	return trimURLPrefix(prURL) + "__reconcile_comments"
+}
+
+// pollCommentForUpdates starts tracking a PR comment (or restarts its tracking window) in the PR's
+// [Config.ReconcileCommentsWorkflow], which is started if it isn't already running. This is a
+// convenience wrapper for [Config.trackCommentActivity].
+func (c Config) pollCommentForUpdates(ctx workflow.Context, accountID, commentURL, rawText string) error {
+	if datacenter.IsURL(commentURL) {
+		return nil // Unlike Bitbucket Cloud, Bitbucket Data Center reports all comment edits.
+	}
+
+	prURL := prURLPattern.FindString(commentURL)
+	if prURL == "" {
+		logger.From(ctx).Error("failed to parse Bitbucket PR comment's URL", slog.String("url", commentURL))
+		return errors.New("invalid Bitbucket PR comment URL: " + commentURL)
+	}
+
+	tc := TrackedComment{
+		URL:       commentURL,
+		ThrippyID: data.SelectUserByBitbucketID(ctx, accountID).ThrippyLink,
+		Checksum:  checksum(rawText),
+		UpdatedAt: workflow.Now(ctx).UTC(),
+	}
+
+	ctx = workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{
+		StartToCloseTimeout: CommentPollingInterval,
+	})
+	return workflow.ExecuteLocalActivity(ctx, c.trackCommentActivity, prURL, tc).Get(ctx, nil)
+}
+
+// trackCommentActivity is a Temporal local activity that signals the PR's [Config.ReconcileCommentsWorkflow]
+// to track a specific comment, and starts that workflow if it isn't already running.
+func (c Config) trackCommentActivity(ctx context.Context, prURL string, tc TrackedComment) error {
+	l := activity.GetLogger(ctx)
+	cli, err := client.Dial(c.TemporalOpts)
+	if err != nil {
+		l.Error("failed to dial Temporal", slog.Any("error", err))
+		return err
+	}
+	defer cli.Close()
+
+	opts := client.StartWorkflowOptions{ID: reconcileWorkflowID(prURL), TaskQueue: c.TaskQueue}
+	req := ReconcileCommentsRequest{PRURL: prURL}
+
+	if _, err := cli.SignalWithStartWorkflow(ctx, opts.ID, ReconcileCommentSignal, tc, opts, ReconcileCommentsWorkflowName, req); err != nil {
+		l.Error("failed to signal Bitbucket PR comments reconciliation workflow",
+			slog.Any("error", err), slog.String("comment_url", tc.URL))
+		return err
+	}
+
+	return nil
+}
+
+// stopPollingComment removes a PR comment from its PR's [Config.ReconcileCommentsWorkflow], if
+// it's running. This is a convenience wrapper for [Config.untrackCommentActivity].
+func (c Config) stopPollingComment(ctx workflow.Context, commentURL string) error {
+	if datacenter.IsURL(commentURL) {
+		return nil // See [Config.pollCommentForUpdates].
+	}
+
+	prURL := prURLPattern.FindString(commentURL)
+	if prURL == "" {
+		return nil // Nothing to untrack.
+	}
+
+	ctx = workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{
+		StartToCloseTimeout: CommentPollingInterval,
+	})
+	return workflow.ExecuteLocalActivity(ctx, c.untrackCommentActivity, prURL, commentURL).Get(ctx, nil)
+}
+
+func (c Config) untrackCommentActivity(ctx context.Context, prURL, commentURL string) error {
+	l := activity.GetLogger(ctx)
+	cli, err := client.Dial(c.TemporalOpts)
+	if err != nil {
+		l.Error("failed to dial Temporal", slog.Any("error", err))
+		return err
+	}
+	defer cli.Close()
+
+	err = cli.SignalWorkflow(ctx, reconcileWorkflowID(prURL), "", ReconcileCommentSignal, TrackedComment{URL: commentURL})
+	if notFound := new(serviceerror.NotFound); errors.As(err, &notFound) {
+		return nil // The workflow isn't running, so there's nothing to untrack.
+	}
+	if err != nil {
+		l.Error("failed to signal Bitbucket PR comments reconciliation workflow",
+			slog.Any("error", err), slog.String("comment_url", commentURL))
+		return err
+	}
+
+	return nil
+}
+
+// ReconcileCommentsWorkflow is a long-running per-PR workflow which detects and mirrors edits and deletions of
+// recently created or updated PR comments, which Bitbucket doesn't report within its [10-minute silent window].
+//
+// Comments are added to this workflow by [Config.pollCommentForUpdates], and tracked for [CommentPollingWindow]
+// since their last change. In each round, this workflow lists all the PR's recently-updated comments in a single
+// API call, and compares their checksums (for privacy and efficiency reasons) to the ones it's tracking. The
+// interval between rounds backs off exponentially as the PR goes quiet, and the workflow ends when there are
+// no more comments to track. Signals don't postpone the next round, they may only advance it.
+//
+// [10-minute silent window]: https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Comment-updated
+func (c Config) ReconcileCommentsWorkflow(ctx workflow.Context, req ReconcileCommentsRequest) error {
+	if req.Comments == nil {
+		req.Comments = map[string]TrackedComment{}
+	}
+	if req.Interval == 0 {
+		req.Interval = CommentPollingInterval
+	}
+
+	ch := workflow.GetSignalChannel(ctx, ReconcileCommentSignal)
+	for {
+		// Process all pending signals before deciding whether there's anything left to do.
+		var tc TrackedComment
+		for ch.ReceiveAsync(&tc) {
+			req.trackComment(tc, workflow.Now(ctx))
+		}
+		if len(req.Comments) == 0 {
+			logger.From(ctx).Debug("no more Bitbucket PR comments to reconcile", slog.String("pr_url", req.PRURL))
+			return nil
+		}
+
+		if workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			return workflow.NewContinueAsNewError(ctx, ReconcileCommentsWorkflowName, req)
+		}
+
+		if req.NextRound.IsZero() {
+			req.NextRound = workflow.Now(ctx).Add(req.Interval)
+		}
+
+		if wait := req.NextRound.Sub(workflow.Now(ctx)); wait > 0 {
+			timerCtx, cancel := workflow.WithCancel(ctx)
+			signaled := false
+
+			sel := workflow.NewSelector(ctx)
+			sel.AddFuture(workflow.NewTimer(timerCtx, wait), func(workflow.Future) {})
+			sel.AddReceive(ch, func(ch workflow.ReceiveChannel, _ bool) {
+				ch.Receive(ctx, &tc)
+				req.trackComment(tc, workflow.Now(ctx))
+				signaled = true
+			})
+			sel.Select(ctx)
+			cancel()
+
+			if signaled {
+				continue // Wait for the same deadline, unless the signal advanced it.
+			}
+		}
+
+		if c.reconcileComments(ctx, &req) {
+			req.Interval = CommentPollingInterval
+		} else {
+			req.Interval = min(req.Interval*2, CommentPollingMaxInterval)
+		}
+		req.NextRound = workflow.Now(ctx).Add(req.Interval)
+	}
+}
+
+// trackComment applies a [ReconcileCommentSignal] to the workflow's state. Tracking a comment
+// resets the backoff, so the next round may come sooner than planned, but never later.
+func (r *ReconcileCommentsRequest) trackComment(tc TrackedComment, now time.Time) {
+	if tc.Checksum == "" {
+		delete(r.Comments, tc.URL)
+		return
+	}
+
+	r.Comments[tc.URL] = tc
+	r.Interval = CommentPollingInterval
+	if next := now.Add(r.Interval); r.NextRound.IsZero() || next.Before(r.NextRound) {
+		r.NextRound = next
+	}
+}
+
+// reconcileComments runs a single round of [Config.ReconcileCommentsWorkflow]. It returns
+// true if it detected any changes in the tracked comments, in order to reset the backoff.
+func (c Config) reconcileComments(ctx workflow.Context, req *ReconcileCommentsRequest) bool {
+	urls := slices.Sorted(maps.Keys(req.Comments)) //workflowcheck:ignore
+
+	thrippyID := ""
+	since := workflow.Now(ctx).UTC()
+	for _, url := range urls {
+		tc := req.Comments[url]
+		if thrippyID == "" {
+			thrippyID = tc.ThrippyID
+		}
+		if tc.UpdatedAt.Before(since) {
+			since = tc.UpdatedAt
+		}
+	}
+
+	// Batch-fetch all the recently-updated comments in the PR. If that fails (the error is already
+	// logged), the loop below falls back to fetching each tracked comment individually.
+	comments, err := activities.ListPullRequestComments(ctx, thrippyID, req.PRURL, since.Add(-commentListMargin))
+	if err != nil {
+		comments = nil
+	}
+
+	recent := make(map[string]*bitbucket.Comment, len(comments))
+	for i := range comments {
+		recent[bitbucket.HTMLURL(comments[i].Links)] = &comments[i]
+	}
+
+	changed := false
+	now := workflow.Now(ctx).UTC()
+	for _, url := range urls {
+		tc := req.Comments[url]
+		comment, found := recent[url]
+		if !found {
+			// The comment may be missing from the list due to pagination or indexing delays,
+			// or because listing failed, so we check it individually before giving up on it.
+			if comment, err = activities.GetPullRequestComment(ctx, tc.ThrippyID, url); err != nil {
+				continue
+			}
+		}
+
+		switch {
+		case comment.Deleted:
+			logger.From(ctx).Info("Bitbucket PR comment deleted, mirroring in Slack", slog.String("comment_url", url))
+			_ = deleteCommentInSlack(ctx, url)
+			delete(req.Comments, url)
+			changed = true
+
+		case checksum(comment.Content.Raw) != tc.Checksum:
+			logger.From(ctx).Info("Bitbucket PR comment text changed, updating Slack message", slog.String("comment_url", url))
+			_ = c.editCommentInSlack(ctx, comment)
+			tc.Checksum = checksum(comment.Content.Raw)
+			tc.UpdatedAt = now
+			req.Comments[url] = tc
+			changed = true
+		}
+	}
+
+	// Bitbucket doesn't send webhook events for tasks either, so we sync them in every round too.
+	if channelID, found := slack.LookupChannel(ctx, req.PRURL); found && bitbucket.SyncTasks(ctx, channelID, req.PRURL, c.SlackPinChecklists) {
+		bitbucket.UpdateChannelTasksBookmark(ctx, channelID, req.PRURL)
+		changed = true
+	}
+
+	req.expireComments(now)
+	return changed
+}
+
+// expireComments stops tracking comments which haven't changed during the last
+// [CommentPollingWindow], because Bitbucket will report their future edits by itself.
+func (r *ReconcileCommentsRequest) expireComments(now time.Time) {
+	maps.DeleteFunc(r.Comments, func(_ string, tc TrackedComment) bool {
+		return now.Sub(tc.UpdatedAt) > CommentPollingWindow
+	})
+}
+
+// LegacyPollCommentWorkflow does nothing. It replaces the per-comment polling workflow which
+// preceded [Config.ReconcileCommentsWorkflow], and remains registered only as long as legacy
+// schedules may still trigger it (see [DeleteLegacySchedules]), to prevent failed executions.
+func (c Config) LegacyPollCommentWorkflow(_ workflow.Context, _ map[string]any) error {
+	return nil
+}
+
+// DeleteLegacySchedules deletes the Temporal schedules which preceded [Config.ReconcileCommentsWorkflow]:
+// all the per-comment polling schedules (which trigger [Config.LegacyPollCommentWorkflow]), and the
+// schedule which was used to clean them up. Failures are only logged, the next startup will retry.
+func DeleteLegacySchedules(ctx context.Context, c client.Client) {
+	l := logger.FromContext(ctx)
+	ids := []string{legacyCleanupSchedule}
+
+	schedules, err := c.ScheduleClient().List(ctx, client.ScheduleListOptions{})
+	if err != nil {
+		l.Warn("failed to list legacy Bitbucket comment polling schedules", slog.Any("error", err))
+	}
+	for err == nil && schedules.HasNext() {
+		var sched *client.ScheduleListEntry
+		if sched, err = schedules.Next(); err != nil {
+			l.Warn("failed to get next legacy Bitbucket comment polling schedule", slog.Any("error", err))
+			break
+		}
+		if sched.WorkflowType.Name == LegacyPollCommentWorkflowName {
+			ids = append(ids, sched.ID)
+		}
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		err := c.ScheduleClient().GetHandle(ctx, id).Delete(ctx)
+		if notFound := new(serviceerror.NotFound); err != nil && !errors.As(err, &notFound) {
+			l.Warn("failed to delete legacy Bitbucket comment polling schedule", slog.Any("error", err), slog.String("schedule_id", id))
+			continue
+		}
+		if err == nil {
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		l.Info("deleted legacy Bitbucket comment polling schedules", slog.Int("count", deleted))
+	}
+}
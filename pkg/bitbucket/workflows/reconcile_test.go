@@ -0,0 +1,150 @@
+package workflows
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+const (
+	testPRURL       = "https://bitbucket.org/workspace/repo/pull-requests/1"
+	testCommentURL1 = testPRURL + "#comment-1"
+	testCommentURL2 = testPRURL + "#comment-2"
+)
+
+var testStartTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newReconcileTestEnv returns a test environment for [Config.ReconcileCommentsWorkflow], in which
+// the tracked comments never change, and a pointer to the elapsed times of all reconciliation rounds.
+func newReconcileTestEnv(t *testing.T) (*testsuite.TestWorkflowEnvironment, *[]time.Duration) {
+	t.Helper()
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.SetStartTime(testStartTime)
+	env.RegisterWorkflowWithOptions(Config{}.ReconcileCommentsWorkflow, workflow.RegisterOptions{Name: ReconcileCommentsWorkflowName})
+
+	rounds := []time.Duration{}
+	listComments := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+		rounds = append(rounds, env.Now().Sub(testStartTime))
+		return map[string]any{"values": []bitbucket.Comment{
+			{Content: bitbucket.Rendered{Raw: "text"}, Links: map[string]bitbucket.Link{"html": {HRef: testCommentURL1}}},
+			{Content: bitbucket.Rendered{Raw: "text"}, Links: map[string]bitbucket.Link{"html": {HRef: testCommentURL2}}},
+		}}, nil
+	}
+	env.RegisterActivityWithOptions(listComments, activity.RegisterOptions{Name: "bitbucket.pullrequests.listComments"})
+
+	return env, &rounds
+}
+
+func newReconcileTestRequest() ReconcileCommentsRequest {
+	return ReconcileCommentsRequest{
+		PRURL: testPRURL,
+		Comments: map[string]TrackedComment{
+			testCommentURL1: {URL: testCommentURL1, Checksum: checksum("text"), UpdatedAt: testStartTime},
+		},
+	}
+}
+
+func TestReconcileCommentsWorkflowTimer(t *testing.T) {
+	env, rounds := newReconcileTestEnv(t)
+	env.ExecuteWorkflow(ReconcileCommentsWorkflowName, newReconcileTestRequest())
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("ReconcileCommentsWorkflow() didn't complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("ReconcileCommentsWorkflow() error = %v", err)
+	}
+
+	// Exponential backoff: 15s, 30s, 1m, and then 2m, until the comment isn't
+	// updated for longer than [CommentPollingWindow] (10m) and stops being tracked.
+	want := []time.Duration{
+		15 * time.Second, 45 * time.Second, 105 * time.Second, 225 * time.Second,
+		345 * time.Second, 465 * time.Second, 585 * time.Second, 705 * time.Second,
+	}
+	if !slices.Equal(*rounds, want) {
+		t.Errorf("ReconcileCommentsWorkflow() rounds = %v, want %v", *rounds, want)
+	}
+}
+
+func TestReconcileCommentsWorkflowSignals(t *testing.T) {
+	env, rounds := newReconcileTestEnv(t)
+
+	track := TrackedComment{URL: testCommentURL2, Checksum: checksum("text")}
+	// A new comment doesn't postpone the first round.
+	env.RegisterDelayedCallback(func() {
+		track.UpdatedAt = env.Now()
+		env.SignalWorkflow(ReconcileCommentSignal, track)
+	}, 10*time.Second)
+	// An updated comment doesn't postpone the second round (45s) either, even
+	// though it resets the backoff (if it did, the round would be at 55s).
+	env.RegisterDelayedCallback(func() {
+		track.UpdatedAt = env.Now()
+		env.SignalWorkflow(ReconcileCommentSignal, track)
+	}, 40*time.Second)
+	// Untracking all the comments ends the workflow before its third round (75s).
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(ReconcileCommentSignal, TrackedComment{URL: testCommentURL1})
+		env.SignalWorkflow(ReconcileCommentSignal, TrackedComment{URL: testCommentURL2})
+	}, 60*time.Second)
+
+	env.ExecuteWorkflow(ReconcileCommentsWorkflowName, newReconcileTestRequest())
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("ReconcileCommentsWorkflow() didn't complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("ReconcileCommentsWorkflow() error = %v", err)
+	}
+
+	want := []time.Duration{15 * time.Second, 45 * time.Second}
+	if !slices.Equal(*rounds, want) {
+		t.Errorf("ReconcileCommentsWorkflow() rounds = %v, want %v", *rounds, want)
+	}
+}
+
+func TestReconcileCommentsWorkflowListFailure(t *testing.T) {
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.SetStartTime(testStartTime)
+	env.RegisterWorkflowWithOptions(Config{}.ReconcileCommentsWorkflow, workflow.RegisterOptions{Name: ReconcileCommentsWorkflowName})
+
+	listComments := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+		return nil, temporal.NewNonRetryableApplicationError("not found", "error", nil)
+	}
+	env.RegisterActivityWithOptions(listComments, activity.RegisterOptions{Name: "bitbucket.pullrequests.listComments"})
+
+	// Without the list, each tracked comment is still checked individually in every round.
+	rounds := []time.Duration{}
+	getComment := func(_ context.Context, _ map[string]any) (*bitbucket.Comment, error) {
+		rounds = append(rounds, env.Now().Sub(testStartTime))
+		return &bitbucket.Comment{Content: bitbucket.Rendered{Raw: "text"}}, nil
+	}
+	env.RegisterActivityWithOptions(getComment, activity.RegisterOptions{Name: "bitbucket.pullrequests.getComment"})
+
+	env.ExecuteWorkflow(ReconcileCommentsWorkflowName, newReconcileTestRequest())
+
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("ReconcileCommentsWorkflow() didn't complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatalf("ReconcileCommentsWorkflow() error = %v", err)
+	}
+
+	want := []time.Duration{
+		15 * time.Second, 45 * time.Second, 105 * time.Second, 225 * time.Second,
+		345 * time.Second, 465 * time.Second, 585 * time.Second, 705 * time.Second,
+	}
+	if !slices.Equal(rounds, want) {
+		t.Errorf("ReconcileCommentsWorkflow() rounds = %v, want %v", rounds, want)
+	}
+}
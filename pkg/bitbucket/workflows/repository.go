@@ -13,6 +13,7 @@ import (
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/pkg/bitbucket"
 	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/markdown"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
 )
 
@@ -21,11 +22,67 @@ import (
 // a critical or common need, so a non-persistent in-memory cache is good enough.
 var mergeReadiness = cache.New[bool](time.Hour, cache.DefaultCleanupInterval)
 
-// CommitCommentCreatedWorkflow (will) handle (in the future) this event:
+// CommitCommentCreatedWorkflow mirrors the creation of a new commit comment in the Slack channels
+// of all the tracked PRs which include the commented commit. Slack replies to the mirrored message
+// are mirrored back as replies to the commit comment (see the Slack message workflows):
 // https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Commit-comment-created
-func CommitCommentCreatedWorkflow(ctx workflow.Context, _ bitbucket.RepositoryEvent) error {
-	logger.From(ctx).Debug("Bitbucket commit comment created event - not implemented yet")
-	return nil
+func (c Config) CommitCommentCreatedWorkflow(ctx workflow.Context, event bitbucket.RepositoryEvent) error {
+	if event.Commit == nil || event.Comment == nil {
+		logger.From(ctx).Error("missing commit or comment in Bitbucket commit comment event")
+		return errors.New("missing commit or comment in Bitbucket commit comment event")
+	}
+
+	// If the comment was created by RevChat, i.e. mirrored from Slack, don't repost it.
+	if strings.HasSuffix(event.Comment.Content.Raw, "\n\n[This comment was created by RevChat]: #") {
+		logger.From(ctx).Debug("ignoring self-triggered Bitbucket event")
+		return nil
+	}
+
+	// Commit comment --> commit hash --> 0 or more tracked PRs which include this commit (not necessarily
+	// as their latest commit, so unlike build statuses we can't rely only on RevChat's PR snapshots).
+	user := data.SelectUserByBitbucketID(ctx, event.Actor.AccountID)
+	prURLs, err := bitbucket.FindPRsWithCommit(ctx, user.ThrippyLink, event.Repository.FullName, event.Commit.Hash)
+	if err != nil {
+		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to associate commit hash with PR", err)
+	}
+
+	if len(prURLs) == 0 {
+		logger.From(ctx).Debug("PR not found for commit comment", slog.String("hash", event.Commit.Hash),
+			slog.String("comment_url", bitbucket.HTMLURL(event.Comment.Links)))
+		// This is not a problem: the commit may not belong to any tracked PR.
+		return nil
+	}
+
+	for _, prURL := range prURLs {
+		err = errors.Join(err, c.mirrorCommitComment(ctx, event, prURL))
+	}
+
+	return err
+}
+
+// mirrorCommitComment posts a commit comment (or a reply to one) in a PR's Slack channel.
+//
+// The mapping between the comment's URL and Slack IDs is 2-way, so if the commit belongs
+// to multiple PRs, Slack replies in all of their channels are mirrored back to Bitbucket,
+// but Bitbucket replies are mirrored only in the thread of the last channel to be mapped.
+func (c Config) mirrorCommitComment(ctx workflow.Context, event bitbucket.RepositoryEvent, prURL string) error {
+	// If we're not tracking this PR, there's no need/way to mirror this event.
+	channelID, found := activities.LookupChannel(ctx, prURL)
+	if !found {
+		return nil
+	}
+
+	comment := event.Comment
+	commentURL := bitbucket.HTMLURL(comment.Links)
+	msg := markdown.BitbucketToSlack(ctx, comment.Content.Raw, prURL)
+
+	if comment.Parent != nil {
+		parentURL := bitbucket.HTMLURL(comment.Parent.Links)
+		return bitbucket.ImpersonateUserInReply(ctx, commentURL, parentURL, c.SlackAlertsChannel, comment.User, msg, nil)
+	}
+
+	msg = bitbucket.CommitCommentPrefix(comment, event.Commit) + msg
+	return bitbucket.ImpersonateUserInMsg(ctx, commentURL, channelID, c.SlackAlertsChannel, comment.User, msg, nil)
 }
 
 // CommitStatusWorkflow mirrors build/commit status updates in the corresponding PR's Slack channel:
@@ -47,8 +104,16 @@ func (c Config) CommitStatusWorkflow(ctx workflow.Context, event bitbucket.Repos
 		return nil
 	}
 
+	// Build history is recorded per repository, even if multiple PRs share the same commit.
+	status := data.CommitStatus{Name: cs.Name, State: cs.State, Desc: cs.Description, URL: cs.URL}
+	flaky := map[string]bool{}
 	for _, pr := range prs {
-		err = errors.Join(err, updateCommitStatus(ctx, cs, pr))
+		prURL := bitbucket.HTMLURL(pr.Links)
+		repoURL := data.RepoURL(prURL)
+		if _, found := flaky[repoURL]; !found {
+			flaky[repoURL] = data.RecordBuildTransition(ctx, prURL, cs.Commit.Hash, cs.Key, status)
+		}
+		err = errors.Join(err, c.updateCommitStatus(ctx, cs, pr, flaky[repoURL]))
 	}
 
 	return err
@@ -61,7 +126,7 @@ func (c Config) CommitStatusWorkflow(ctx workflow.Context, event bitbucket.Repos
 // This function uses the following [bitbucket.PullRequest] details:
 // Links (map), draft flag (bool), ChangeRequestCount and TaskCount (int), and Participants (slice).
 // This is relevant for PR detail pruning in [data.FindPRsByCommit].
-func updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bitbucket.PullRequest) error {
+func (c Config) updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bitbucket.PullRequest, flaky bool) error {
 	// If we're not tracking this PR, there's no need/way to announce this event.
 	prURL := bitbucket.HTMLURL(pr.Links)
 	channelID, found := activities.LookupChannel(ctx, prURL)
@@ -72,11 +137,14 @@ func updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bi
 	defer bitbucket.UpdateChannelBuildsBookmark(ctx, channelID, prURL)
 
 	status := data.CommitStatus{Name: cs.Name, State: cs.State, Desc: cs.Description, URL: cs.URL}
-	data.UpdateBitbucketBuilds(ctx, prURL, cs.Commit.Hash, cs.Key, status)
+	data.UpdateBuilds(ctx, prURL, cs.Commit.Hash, cs.Key, status)
 
 	desc, _, _ := strings.Cut(cs.Description, "\n")
-	msg := fmt.Sprintf(`%s "%s" build status: <%s|%s>`, buildStateEmoji(cs.State), cs.Name, cs.URL, desc)
-	err := activities.PostMessage(ctx, channelID, msg)
+	msg := fmt.Sprintf(`%s "%s" build status: <%s|%s>`, activities.BuildStateEmoji(cs.State), cs.Name, cs.URL, desc)
+	if flaky {
+		msg += activities.FlakyBuildNote
+	}
+	resp, err := activities.PostReply(ctx, channelID, "", msg)
 
 	// If the channel is archived but we still store data for it, clean it up. We don't consider this a server error.
 	if err != nil && strings.Contains(err.Error(), "is_archived") {
@@ -84,9 +152,14 @@ func updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bi
 		return nil
 	}
 
+	// Save reviewers a click into Bitbucket Pipelines, by showing them why the build failed.
+	if err == nil && cs.State == "FAILED" && c.PipelineLogs {
+		bitbucket.PostFailedStepLog(ctx, channelID, resp.TS, cs.URL)
+	}
+
 	// Other than announcing this specific event, also announce if the PR is ready to be merged
-	// (not a draft, all builds are successful, the PR has at least 2 approvals, and no pending action items).
-	if pr.Draft || cs.State != "SUCCESSFUL" || !allBuildsSuccessful(ctx, prURL) || pr.ChangeRequestCount > 0 || pr.TaskCount > 0 {
+	// (not a draft, all builds are successful, the PR has enough approvals, and no pending action items).
+	if pr.Draft || cs.State != "SUCCESSFUL" || !data.AllBuildsSuccessful(ctx, prURL) || pr.ChangeRequestCount > 0 || pr.TaskCount > 0 {
 		return err
 	}
 
@@ -96,7 +169,7 @@ func updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bi
 			approvers++
 		}
 	}
-	if approvers < 2 {
+	if approvers < c.MinApprovals {
 		return err
 	}
 
@@ -113,35 +186,31 @@ func updateCommitStatus(ctx workflow.Context, cs *bitbucket.CommitStatus, pr *bi
 	return nil
 }
 
-// IssueCreatedWorkflow (will) handle (in the future) this event:
+// IssueCreatedWorkflow announces a new issue in the Slack channels of all
+// the tracked PRs that it references (by "#123" or by PR URL), if there are any:
 // https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Created
-func IssueCreatedWorkflow(ctx workflow.Context, _ bitbucket.RepositoryEvent) error {
-	logger.From(ctx).Debug("Bitbucket issue created event - not implemented yet")
-	return nil
-}
-
-func buildStateEmoji(state string) string {
-	switch state {
-	case "INPROGRESS":
-		return ":hourglass_flowing_sand:"
-	case "SUCCESSFUL":
-		return ":large_green_circle:"
-	default: // "FAILED", "STOPPED".
-		return ":red_circle:"
+func (c Config) IssueCreatedWorkflow(ctx workflow.Context, event bitbucket.RepositoryEvent) error {
+	issue := event.Issue
+	if issue == nil {
+		logger.From(ctx).Error("missing issue in Bitbucket issue created event")
+		return errors.New("missing issue in Bitbucket issue created event")
 	}
-}
 
-func allBuildsSuccessful(ctx workflow.Context, url string) bool {
-	prStatus := data.ReadBitbucketBuilds(ctx, url)
-	if len(prStatus.Builds) < 2 {
-		return false
+	// Relative references ("#123") are resolved according to the repository of the issue.
+	baseURL := bitbucket.HTMLURL(event.Repository.Links) + "/pull-requests/"
+	prURLs := markdown.PullRequestURLs(baseURL, issue.Title+"\n"+issue.Content.Raw)
+	if len(prURLs) == 0 {
+		return nil
 	}
 
-	for _, build := range prStatus.Builds {
-		if build.State != "SUCCESSFUL" {
-			return false
+	msg := fmt.Sprintf(":ticket: %%s created an issue which references this PR: <%s|#%d: %s>",
+		bitbucket.HTMLURL(issue.Links), issue.ID, issue.Title)
+	for _, prURL := range prURLs {
+		// If we're not tracking this PR, there's no need/way to announce this event.
+		if channelID, found := activities.LookupChannel(ctx, prURL); found {
+			bitbucket.MentionUserInMsg(ctx, channelID, event.Actor, msg)
 		}
-	} //workflowcheck:ignore // Iteration order doesn't matter here.
+	}
 
-	return true
+	return nil
 }
@@ -0,0 +1,67 @@
+package workflows
+
+import (
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// autoAddReviewers adds a minimal set of available code owners as reviewers to a new PR, on behalf of its
+// author, if this is enabled for the PR's repository (see the "reviewers-auto-add-repos" flag). The resulting
+// PR update event announces the new reviewers and adds them to the PR's Slack channel, as usual.
+func (c Config) autoAddReviewers(ctx workflow.Context, channelID, prURL string, pr bitbucket.PullRequest) {
+	if !c.AutoAddReviewersRepos[strings.ToLower(pr.Destination.Repository.FullName)] {
+		return
+	}
+
+	suggestions, err := slack.SuggestReviewers(ctx, c.TemporalOpts, prURL, c.ReviewersMaxLoad)
+	if err != nil {
+		return
+	}
+
+	var accountIDs []string
+	for _, name := range suggestions.Owners {
+		if slices.Contains(suggestions.Reviewers, name) {
+			continue
+		}
+		if id := accountID(ctx, prURL, name); id != "" {
+			accountIDs = append(accountIDs, id)
+		}
+	}
+	if len(accountIDs) == 0 {
+		return
+	}
+
+	thrippyID := data.SelectUserByBitbucketID(ctx, pr.Author.AccountID).ThrippyLink
+	if thrippyID == "" {
+		_ = activities.PostMessage(ctx, channelID, ":information_source: Code owners were not added automatically "+
+			"as reviewers, because the PR author is not opted-in. Use the `suggest` slash command to list them.")
+		return
+	}
+
+	addReviewers := bbactivities.AddPullRequestReviewers
+	if datacenter.IsURL(prURL) {
+		addReviewers = bbactivities.AddDataCenterReviewers
+	}
+	if err := addReviewers(ctx, thrippyID, prURL, accountIDs); err != nil {
+		_ = activities.PostMessage(ctx, channelID, ":warning: Failed to add code owners automatically as reviewers.")
+	}
+}
+
+// accountID returns the Bitbucket account ID of a code owner, identified by their real name, or an empty string if they're
+// unknown. Bitbucket Data Center users are identified by pseudo account IDs, based on their email addresses.
+func accountID(ctx workflow.Context, prURL, realName string) string {
+	user := data.SelectUserByRealName(ctx, realName)
+	if datacenter.IsURL(prURL) {
+		return datacenter.AccountID(user.Email)
+	}
+	return user.BitbucketID
+}
@@ -1,7 +1,6 @@
 package workflows
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -25,8 +24,14 @@ type Config struct {
 	SlackChannelNamePrefix    string
 	SlackChannelNameMaxLength int
 	SlackChannelsArePrivate   bool
+	SlackPinChecklists        bool
 
-	LinkifyMap map[string]string
+	AutoAddReviewersRepos map[string]bool
+	ReviewersMaxLoad      int
+	MinApprovals          int
+
+	LinkifyMap   map[string]string
+	PipelineLogs bool
 
 	TemporalOpts client.Options
 	TaskQueue    string
@@ -38,8 +43,14 @@ func newConfig(cmd *cli.Command, temporalOpts client.Options, taskQueue string)
 		SlackChannelNamePrefix:    cmd.String("slack-channel-name-prefix"),
 		SlackChannelNameMaxLength: cmd.Int("slack-channel-name-max-length"),
 		SlackChannelsArePrivate:   cmd.Bool("slack-private-channels"),
+		SlackPinChecklists:        cmd.Bool("slack-pin-checklists"),
+
+		AutoAddReviewersRepos: config.RepoSet(cmd.StringSlice("reviewers-auto-add-repos")),
+		ReviewersMaxLoad:      cmd.Int("reviewers-max-load"),
+		MinApprovals:          cmd.Int("reviewers-min-approvals"),
 
-		LinkifyMap: config.KVSliceToMap(cmd.StringSlice("linkification-map")),
+		LinkifyMap:   config.KVSliceToMap(cmd.StringSlice("linkification-map")),
+		PipelineLogs: cmd.Bool("bitbucket-pipeline-logs"),
 
 		TemporalOpts: temporalOpts,
 		TaskQueue:    taskQueue,
@@ -91,13 +102,6 @@ var RepositorySignals = []string{
 	"bitbucket.events.repo.issue_created",
 }
 
-// Schedules is a list of workflow names that RevChat runs periodically via
-// Temporal schedules (https://docs.temporal.io/develop/go/schedules).
-var Schedules = []string{
-	"bitbucket.schedules.poll_comment",
-	"bitbucket.schedules.polling_cleanup",
-}
-
 // RegisterPullRequestWorkflows maps event-handling workflow functions to [PullRequestSignals].
 func RegisterPullRequestWorkflows(cmd *cli.Command, temporalOpts client.Options, taskQueue string, w worker.Worker) {
 	c := newConfig(cmd, temporalOpts, taskQueue)
@@ -121,19 +125,20 @@ func RegisterPullRequestWorkflows(cmd *cli.Command, temporalOpts client.Options,
 		w.RegisterWorkflowWithOptions(f, workflow.RegisterOptions{Name: PullRequestSignals[i]})
 	}
 
-	// Special case: scheduled workflows.
-	w.RegisterWorkflowWithOptions(c.PollCommentWorkflow, workflow.RegisterOptions{Name: Schedules[0]})
-	w.RegisterWorkflowWithOptions(c.PollingCleanupWorkflow, workflow.RegisterOptions{Name: Schedules[1]})
+	// Special case: a long-running per-PR workflow, which is not triggered directly by an event.
+	w.RegisterWorkflowWithOptions(c.ReconcileCommentsWorkflow, workflow.RegisterOptions{Name: ReconcileCommentsWorkflowName})
+	// Legacy no-op, until all the schedules which trigger it are deleted (see [DeleteLegacySchedules]).
+	w.RegisterWorkflowWithOptions(c.LegacyPollCommentWorkflow, workflow.RegisterOptions{Name: LegacyPollCommentWorkflowName})
 }
 
 // RegisterRepositoryWorkflows maps event-handling workflow functions to [RepositorySignals].
 func RegisterRepositoryWorkflows(cmd *cli.Command, temporalOpts client.Options, taskQueue string, w worker.Worker) {
 	c := newConfig(cmd, temporalOpts, taskQueue)
 	funcs := []repoWorkflowFunc{
-		CommitCommentCreatedWorkflow,
+		c.CommitCommentCreatedWorkflow,
 		c.CommitStatusWorkflow,
 		c.CommitStatusWorkflow,
-		IssueCreatedWorkflow,
+		c.IssueCreatedWorkflow,
 	}
 	for i, f := range funcs {
 		w.RegisterWorkflowWithOptions(f, workflow.RegisterOptions{Name: RepositorySignals[i]})
@@ -151,25 +156,30 @@ func RegisterPullRequestSignals(ctx workflow.Context, sel workflow.Selector) {
 			payload.Type = strings.TrimPrefix(signal, "bitbucket.events.pullrequest.")
 			otel.SignalReceived(ctx, signal, false)
 
-			// https://docs.temporal.io/develop/go/child-workflows#parent-close-policy
-			opts := workflow.ChildWorkflowOptions{WorkflowID: prChildWorkflowID(ctx, payload)}
-			if payload.Type != "created" {
-				opts.ParentClosePolicy = enums.PARENT_CLOSE_POLICY_ABANDON
-			}
-			ctx = workflow.WithChildOptions(ctx, opts)
-			wf := workflow.ExecuteChildWorkflow(ctx, signal, payload)
-
-			// Wait for [Config.prCreated] completion before returning, to ensure we handle
-			// subsequent PR initialization events appropriately (e.g. check states).
-			if payload.Type == "created" {
-				_ = wf.Get(ctx, nil) // Blocks until child workflow completes.
-			} else {
-				_ = wf.GetChildWorkflowExecution().Get(ctx, nil) // Blocks until child workflow starts.
-			}
+			executePRWorkflow(ctx, payload)
 		})
 	}
 }
 
+// executePRWorkflow starts a child workflow to handle a PR event, based on the event's type.
+func executePRWorkflow(ctx workflow.Context, payload *bitbucket.PullRequestEvent) {
+	// https://docs.temporal.io/develop/go/child-workflows#parent-close-policy
+	opts := workflow.ChildWorkflowOptions{WorkflowID: prChildWorkflowID(ctx, payload)}
+	if payload.Type != "created" {
+		opts.ParentClosePolicy = enums.PARENT_CLOSE_POLICY_ABANDON
+	}
+	ctx = workflow.WithChildOptions(ctx, opts)
+	wf := workflow.ExecuteChildWorkflow(ctx, "bitbucket.events.pullrequest."+payload.Type, payload)
+
+	// Wait for [Config.prCreated] completion before returning, to ensure we handle
+	// subsequent PR initialization events appropriately (e.g. check states).
+	if payload.Type == "created" {
+		_ = wf.Get(ctx, nil) // Blocks until child workflow completes.
+	} else {
+		_ = wf.GetChildWorkflowExecution().Get(ctx, nil) // Blocks until child workflow starts.
+	}
+}
+
 // RegisterRepositorySignals routes [RepositorySignals] to their registered workflows.
 func RegisterRepositorySignals(ctx workflow.Context, sel workflow.Selector) {
 	for _, signalName := range RepositorySignals {
@@ -262,7 +272,7 @@ func prChildWorkflowID(ctx workflow.Context, event *bitbucket.PullRequestEvent)
 	if event.Comment != nil {
 		id = event.Comment.Links["html"].HRef
 	}
-	id = strings.TrimPrefix(id, "https://bitbucket.org/")
+	id = trimURLPrefix(id)
 
 	var ts int64
 	encoded := workflow.SideEffect(ctx, func(_ workflow.Context) any {
@@ -277,7 +287,7 @@ func prChildWorkflowID(ctx workflow.Context, event *bitbucket.PullRequestEvent)
 func repoChildWorkflowID(ctx workflow.Context, event *bitbucket.RepositoryEvent) string {
 	id := fmt.Sprintf("%s_%s", event.Repository.FullName, event.Actor.AccountID)
 	if event.CommitStatus != nil {
-		id = strings.TrimPrefix(event.CommitStatus.Commit.Links["html"].HRef, "https://bitbucket.org/")
+		id = trimURLPrefix(event.CommitStatus.Commit.Links["html"].HRef)
 	}
 
 	var ts int64
@@ -289,29 +299,3 @@ func repoChildWorkflowID(ctx workflow.Context, event *bitbucket.RepositoryEvent)
 	}
 	return fmt.Sprintf("%s__%s", id, strconv.FormatInt(ts, 36))
 }
-
-// CreateSchedule starts a scheduled workflow that runs once an hour,
-// to delete obsolete (i.e. completed) PR comment polling schedules (which were started by
-// [Config.pollCommentForUpdates]) instead of waiting a week for the Temporal server to do it.
-func CreateSchedule(ctx context.Context, c client.Client, taskQueue string) {
-	_, err := c.ScheduleClient().Create(ctx, client.ScheduleOptions{
-		ID: Schedules[1],
-		Spec: client.ScheduleSpec{
-			Calendars: []client.ScheduleCalendarSpec{
-				{
-					Minute: []client.ScheduleRange{{Start: 15, End: 45, Step: 30}}, // Every 30 minutes.
-					Hour:   []client.ScheduleRange{{Start: 0, End: 23}},
-				},
-			},
-			Jitter: 10 * time.Second,
-		},
-		Action: &client.ScheduleWorkflowAction{
-			Workflow:  Schedules[1],
-			TaskQueue: taskQueue,
-		},
-	})
-	if err != nil {
-		logger.FromContext(ctx).Warn("failed to initialize Bitbucket comment polling cleanup schedule",
-			slog.Any("error", err), slog.String("schedule_id", Schedules[1]))
-	}
-}
@@ -28,10 +28,27 @@ const (
 	StartToCloseTimeout    = 10 * time.Second
 	MaxRetryAttempts       = 5
 
+	LabelRuleNoMerge = "no-merge" // Don't announce that the PR is ready to be merged.
+	LabelRuleUrgent  = "urgent"   // List the PR before others in daily reminders.
+	LabelRuleSkip    = "skip"     // Don't track the PR with a Slack channel.
+
 	DefaultChannelNamePrefix    = "_pr"
 	DefaultChannelNameMaxLength = 50 // Slack's hard limit = 80, but that's still too long.
+
+	DefaultReviewersMaxLoad      = 10
+	DefaultReviewersMinApprovals = 2
+
+	ReviewerLeftNote     = "note"     // Only post a note in the PR channel when a reviewer leaves it.
+	ReviewerLeftUntrack  = "untrack"  // Also stop tracking the reviewer's turn in the PR.
+	ReviewerLeftUnassign = "unassign" // Also remove the reviewer from the PR, on their behalf.
+
+	BitbucketReactionsIgnore = "ignore" // Don't mirror Slack reactions in Bitbucket.
+	BitbucketReactionsReply  = "reply"  // Mirror Slack reactions as comment replies.
 )
 
+// DefaultLabelRules is the default value of the "github-label-rules" flag.
+var DefaultLabelRules = []string{"do-not-merge=" + LabelRuleNoMerge, "urgent=" + LabelRuleUrgent, "skip-revchat=" + LabelRuleSkip}
+
 // configFile returns the path to the app's configuration file.
 // It also creates an empty file if it doesn't already exist.
 func configFile() altsrc.StringSourcer {
@@ -149,6 +166,90 @@ func Flags() []cli.Flag {
 				toml.TOML("bitbucket.workspace", path),
 			),
 		},
+		&cli.StringFlag{
+			Name:  "bitbucket-reactions",
+			Usage: `How to mirror Slack reactions in Bitbucket PR comments, which don't support reactions ("ignore" or "reply")`,
+			Value: BitbucketReactionsIgnore,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("BITBUCKET_REACTIONS"),
+				toml.TOML("bitbucket.reactions", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "bitbucket-commit-comments",
+			Usage: `Mirror Slack replies to commit comments in Bitbucket (requires Timpani to support the "bitbucket.commits.*Comment" activities)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("BITBUCKET_COMMIT_COMMENTS"),
+				toml.TOML("bitbucket.commit_comments", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "bitbucket-create-tasks",
+			Usage: `Create Bitbucket PR tasks with a Slack command (requires Timpani to support the "bitbucket.pullrequests.createTask" activity)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("BITBUCKET_CREATE_TASKS"),
+				toml.TOML("bitbucket.create_tasks", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "bitbucket-pipeline-logs",
+			Usage: `Post log excerpts of failed Bitbucket Pipelines builds (requires Timpani to support the "bitbucket.pipelines.listSteps" and "bitbucket.pipelines.getStepLog" activities)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("BITBUCKET_PIPELINE_LOGS"),
+				toml.TOML("bitbucket.pipeline_logs", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "bitbucket-datacenter",
+			Usage: `Receive events from Bitbucket Data Center instances (requires Timpani to support the "bitbucket.datacenter.*" signals and activities)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("BITBUCKET_DATACENTER"),
+				toml.TOML("bitbucket.datacenter", path),
+			),
+		},
+
+		// GitHub.
+		&cli.StringSliceFlag{
+			Name:  "github-label-rules",
+			Usage: `Map of case-insensitive GitHub PR labels to RevChat behaviors ("no-merge", "urgent", "skip")`,
+			Value: DefaultLabelRules,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("GITHUB_LABEL_RULES"),
+				toml.TOML("github.label_rules", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "github-list-reviews",
+			Usage: `Look up GitHub PR reviews to unapprove PRs and check their approvals (requires Timpani to support the "github.pulls.reviews.list" activity)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("GITHUB_LIST_REVIEWS"),
+				toml.TOML("github.list_reviews", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "github-delete-branches",
+			Usage: `Delete the source branches of GitHub PRs merged with "close-branch" (requires Timpani to support the "github.git.deleteRef" activity)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("GITHUB_DELETE_BRANCHES"),
+				toml.TOML("github.delete_branches", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "github-reviewer-requests",
+			Usage: `Request and remove GitHub PR reviewers on behalf of users (requires Timpani to support the "github.pulls.requestReviewers" and "github.pulls.removeRequestedReviewers" activities)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("GITHUB_REVIEWER_REQUESTS"),
+				toml.TOML("github.reviewer_requests", path),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "github-reactions",
+			Usage: `Mirror Slack reactions as GitHub PR comment reactions (requires Timpani to support the "github.reactions.*" activities)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("GITHUB_REACTIONS"),
+				toml.TOML("github.reactions", path),
+			),
+		},
 
 		// Slack (general).
 		&cli.StringFlag{
@@ -175,6 +276,14 @@ func Flags() []cli.Flag {
 				toml.TOML("slack.nudge_groups", path),
 			),
 		},
+		&cli.BoolFlag{
+			Name:  "slack-reminder-buttons",
+			Usage: `Add action buttons to Slack reminders (requires Timpani to dispatch "block_actions" interaction events)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("SLACK_REMINDER_BUTTONS"),
+				toml.TOML("slack.reminder_buttons", path),
+			),
+		},
 		&cli.BoolFlag{
 			Name:  "slack-report-drafts",
 			Usage: "Show drafts in Slack reminders and status reports",
@@ -183,6 +292,14 @@ func Flags() []cli.Flag {
 				toml.TOML("slack.report_drafts", path),
 			),
 		},
+		&cli.BoolFlag{
+			Name:  "slack-pin-checklists",
+			Usage: `Pin the checklist messages of Bitbucket PR tasks in Slack channels (requires Timpani to support the "slack.pins.add" activity)`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("SLACK_PIN_CHECKLISTS"),
+				toml.TOML("slack.pin_checklists", path),
+			),
+		},
 
 		// Slack (for Bitbucket or GitHub).
 		&cli.IntFlag{
@@ -212,6 +329,51 @@ func Flags() []cli.Flag {
 			),
 		},
 
+		// Code reviewers (for Bitbucket or GitHub).
+		&cli.StringSliceFlag{
+			Name:  "reviewers-auto-add-repos",
+			Usage: `Case-insensitive repositories ("owner/repo") where code owners are added as reviewers to new PRs`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("REVIEWERS_AUTO_ADD_REPOS"),
+				toml.TOML("reviewers.auto_add_repos", path),
+			),
+		},
+		&cli.IntFlag{
+			Name:  "reviewers-max-load",
+			Usage: "Don't suggest reviewers who already have this many PRs waiting for them (0 = unlimited)",
+			Value: DefaultReviewersMaxLoad,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("REVIEWERS_MAX_LOAD"),
+				toml.TOML("reviewers.max_load", path),
+			),
+		},
+		&cli.IntFlag{
+			Name:  "reviewers-min-approvals",
+			Usage: "Announce that a PR is ready to be merged only if it has at least this many approvals",
+			Value: DefaultReviewersMinApprovals,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("REVIEWERS_MIN_APPROVALS"),
+				toml.TOML("reviewers.min_approvals", path),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "reviewers-left-channel",
+			Usage: `What to do when a reviewer leaves a PR channel ("note", "untrack", or "unassign")`,
+			Value: ReviewerLeftNote,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("REVIEWERS_LEFT_CHANNEL"),
+				toml.TOML("reviewers.left_channel", path),
+			),
+		},
+		&cli.StringSliceFlag{
+			Name:  "reviewers-add-on-join-repos",
+			Usage: `Case-insensitive repositories ("owner/repo") where opted-in users who join PR channels are added as reviewers`,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("REVIEWERS_ADD_ON_JOIN_REPOS"),
+				toml.TOML("reviewers.add_on_join_repos", path),
+			),
+		},
+
 		// Linkification.
 		&cli.StringSliceFlag{
 			Name:  "linkification-map",
@@ -12,10 +12,42 @@ func KVSliceToMap(pairs []string) map[string]string {
 	for _, kv := range pairs {
 		k, v, ok := strings.Cut(kv, "=")
 		if !ok {
-			slog.Error("invalid key-value pair in linkification map configuration", slog.String("kv", kv))
+			slog.Error("invalid key-value pair in map configuration", slog.String("kv", kv))
 			continue
 		}
 		m[strings.TrimSpace(k)] = strings.TrimSpace(v)
 	}
 	return m
 }
+
+// LabelRules converts the "github-label-rules" flag into a map of lowercase label names to RevChat behaviors.
+func LabelRules(pairs []string) map[string]string {
+	m := KVSliceToMap(pairs)
+	rules := make(map[string]string, len(m))
+	for label, rule := range m {
+		rules[strings.ToLower(label)] = strings.ToLower(rule)
+	}
+	return rules
+}
+
+// HasLabelRule checks whether any of the given PR labels (case-insensitive) is mapped to the given behavior.
+func HasLabelRule(rules map[string]string, labels []string, rule string) bool {
+	for _, label := range labels {
+		if rules[strings.ToLower(label)] == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// RepoSet converts a list of repository names (e.g. the "reviewers-auto-add-repos" flag)
+// into a set of lowercase "owner/repo" names. Empty entries are ignored.
+func RepoSet(repos []string) map[string]bool {
+	m := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		if repo = strings.ToLower(strings.TrimSpace(repo)); repo != "" {
+			m[repo] = true
+		}
+	}
+	return m
+}
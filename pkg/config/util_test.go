@@ -43,3 +43,57 @@ func TestKVSliceToMap(t *testing.T) {
 		})
 	}
 }
+
+func TestHasLabelRule(t *testing.T) {
+	rules := config.LabelRules(config.DefaultLabelRules)
+
+	tests := []struct {
+		name   string
+		labels []string
+		rule   string
+		want   bool
+	}{
+		{
+			name: "no_labels",
+			rule: config.LabelRuleNoMerge,
+		},
+		{
+			name:   "unrelated_labels",
+			labels: []string{"bug", "enhancement"},
+			rule:   config.LabelRuleNoMerge,
+		},
+		{
+			name:   "matching_label",
+			labels: []string{"bug", "do-not-merge"},
+			rule:   config.LabelRuleNoMerge,
+			want:   true,
+		},
+		{
+			name:   "case_insensitive_label",
+			labels: []string{"URGENT"},
+			rule:   config.LabelRuleUrgent,
+			want:   true,
+		},
+		{
+			name:   "different_rule",
+			labels: []string{"skip-revchat"},
+			rule:   config.LabelRuleUrgent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.HasLabelRule(rules, tt.labels, tt.rule); got != tt.want {
+				t.Errorf("HasLabelRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoSet(t *testing.T) {
+	got := config.RepoSet([]string{"Owner/Repo", " other/repo ", ""})
+	want := map[string]bool{"owner/repo": true, "other/repo": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RepoSet() = %v, want %v", got, want)
+	}
+}
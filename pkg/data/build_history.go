@@ -0,0 +1,62 @@
+package data
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data/internal"
+)
+
+type FlakyBuild = internal.FlakyBuild
+
+// BuildHistoryRetentionDays is the maximum age, in days, of the build history of repositories.
+const BuildHistoryRetentionDays = int(internal.BuildHistoryRetention / (24 * time.Hour))
+
+// RepoURL returns the URL of the repository that a PR belongs to, or an empty string if this isn't a PR URL.
+func RepoURL(prURL string) string {
+	return internal.RepoURL(prURL)
+}
+
+// RecordBuildTransition adds the given build status to the build history of the given PR's
+// repository, and reports whether this is a likely flaky build: a success after a failure in
+// the same commit (i.e. a rerun). Errors are logged but not returned, as they are not critical.
+func RecordBuildTransition(ctx workflow.Context, prURL, commitHash, key string, cs CommitStatus) bool {
+	repoURL := RepoURL(prURL)
+	if repoURL == "" {
+		return false
+	}
+
+	if ctx == nil { // For unit testing.
+		flaky, _ := internal.RecordBuildTransition(context.Background(), repoURL, commitHash, key, cs) //workflowcheck:ignore
+		return flaky
+	}
+
+	flaky := false
+	if err := executeLocalActivity(ctx, internal.RecordBuildTransition, &flaky, repoURL, commitHash, key, cs); err != nil {
+		logger.From(ctx).Error("failed to record build state transition", slog.Any("error", err),
+			slog.String("repo_url", repoURL), slog.String("commit_hash", commitHash))
+		return false
+	}
+
+	return flaky
+}
+
+// ListFlakyBuilds returns the build keys in the given repository which were rerun
+// successfully after a failure in the last few days, from the most to the least flaky.
+func ListFlakyBuilds(ctx workflow.Context, repoURL string, days int) ([]FlakyBuild, error) {
+	if ctx == nil { // For unit testing.
+		return internal.ListFlakyBuilds(context.Background(), repoURL, days) //workflowcheck:ignore
+	}
+
+	var builds []FlakyBuild
+	if err := executeLocalActivity(ctx, internal.ListFlakyBuilds, &builds, repoURL, days); err != nil {
+		logger.From(ctx).Error("failed to list flaky builds", slog.Any("error", err), slog.String("repo_url", repoURL))
+		return nil, err
+	}
+
+	return builds, nil
+}
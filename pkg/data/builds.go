@@ -0,0 +1,80 @@
+package data
+
+import (
+	"context"
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data/internal"
+)
+
+type (
+	CommitStatus = internal.CommitStatus
+	PRStatus     = internal.PRStatus
+)
+
+func ReadBuilds(ctx workflow.Context, prURL string) PRStatus {
+	if ctx == nil { // For unit testing.
+		status, err := internal.ReadBuilds(context.Background(), prURL) //workflowcheck:ignore
+		if err != nil {
+			return PRStatus{}
+		}
+		return *status
+	}
+
+	status := PRStatus{}
+	err := executeLocalActivity(ctx, internal.ReadBuilds, &status, prURL)
+	if err != nil {
+		logger.From(ctx).Error("failed to read PR's build states",
+			slog.Any("error", err), slog.String("pr_url", prURL))
+		return PRStatus{}
+	}
+
+	return status
+}
+
+// AllBuildsSuccessful reports whether the given PR has at least 2 builds, and all of them are successful.
+// The minimum is meant to avoid premature announcements, when only the first build is reported so far.
+func AllBuildsSuccessful(ctx workflow.Context, prURL string) bool {
+	prStatus := ReadBuilds(ctx, prURL)
+	if len(prStatus.Builds) < 2 {
+		return false
+	}
+
+	for _, build := range prStatus.Builds {
+		if build.State != "SUCCESSFUL" {
+			return false
+		}
+	} //workflowcheck:ignore
+
+	return true
+}
+
+// UpdateBuilds appends the given build status to the given PR,
+// unless the new status is based on a different (i.e. newer) commit,
+// in which case this function discards all previous build statuses.
+func UpdateBuilds(ctx workflow.Context, prURL, commitHash, key string, cs CommitStatus) {
+	if ctx == nil { // For unit testing.
+		_ = internal.UpdateBuilds(context.Background(), prURL, commitHash, key, cs) //workflowcheck:ignore
+		return
+	}
+
+	if err := executeLocalActivity(ctx, internal.UpdateBuilds, nil, prURL, commitHash, key, cs); err != nil {
+		logger.From(ctx).Error("failed to update PR's build states", slog.Any("error", err),
+			slog.String("pr_url", prURL), slog.String("commit_hash", commitHash))
+	}
+}
+
+func DeleteBuilds(ctx workflow.Context, prURL string) {
+	if ctx == nil { // For unit testing.
+		_ = internal.DeleteGenericPRFile(context.Background(), prURL+internal.BuildsFileSuffix) //workflowcheck:ignore
+		return
+	}
+
+	if err := executeLocalActivity(ctx, internal.DeleteGenericPRFile, nil, prURL+internal.BuildsFileSuffix); err != nil {
+		logger.From(ctx).Warn("failed to delete PR's build states",
+			slog.Any("error", err), slog.String("pr_url", prURL))
+	}
+}
@@ -7,16 +7,16 @@ import (
 	"github.com/tzrikka/revchat/pkg/data"
 )
 
-func TestBitbucketBuilds(t *testing.T) {
+func TestBuilds(t *testing.T) {
 	d := t.TempDir()
 	t.Setenv("XDG_DATA_HOME", d)
 
 	prURL := "https://bitbucket.org/workspace/repo/pull-requests/1"
 
 	// Initial state.
-	got := data.ReadBitbucketBuilds(nil, prURL)
+	got := data.ReadBuilds(nil, prURL)
 	if got.Builds != nil {
-		t.Fatalf("ReadBitbucketBuilds() = %#v, want %#v", got, data.PRStatus{})
+		t.Fatalf("ReadBuilds() = %#v, want %#v", got, data.PRStatus{})
 	}
 
 	// Update build status.
@@ -26,9 +26,9 @@ func TestBitbucketBuilds(t *testing.T) {
 		Desc:  "Build passed",
 		URL:   "http://build1",
 	}
-	data.UpdateBitbucketBuilds(nil, prURL, "commit1", "build1", cs1)
+	data.UpdateBuilds(nil, prURL, "commit1", "build1", cs1)
 
-	got = data.ReadBitbucketBuilds(nil, prURL)
+	got = data.ReadBuilds(nil, prURL)
 	want := data.PRStatus{
 		CommitHash: "commit1",
 		Builds: map[string]data.CommitStatus{
@@ -36,7 +36,7 @@ func TestBitbucketBuilds(t *testing.T) {
 		},
 	}
 	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, want)
+		t.Fatalf("ReadBuilds() = %v, want %v", got, want)
 	}
 
 	// Update with new commit.
@@ -46,9 +46,9 @@ func TestBitbucketBuilds(t *testing.T) {
 		Desc:  "Build failed",
 		URL:   "http://build2",
 	}
-	data.UpdateBitbucketBuilds(nil, prURL, "commit2", "build2", cs2)
+	data.UpdateBuilds(nil, prURL, "commit2", "build2", cs2)
 
-	got = data.ReadBitbucketBuilds(nil, prURL)
+	got = data.ReadBuilds(nil, prURL)
 	want = data.PRStatus{
 		CommitHash: "commit2",
 		Builds: map[string]data.CommitStatus{
@@ -56,14 +56,14 @@ func TestBitbucketBuilds(t *testing.T) {
 		},
 	}
 	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, want)
+		t.Fatalf("ReadBuilds() = %v, want %v", got, want)
 	}
 
 	// Delete builds.
-	data.DeleteBitbucketBuilds(nil, prURL)
+	data.DeleteBuilds(nil, prURL)
 
-	got = data.ReadBitbucketBuilds(nil, prURL)
+	got = data.ReadBuilds(nil, prURL)
 	if got.Builds != nil {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, data.PRStatus{})
+		t.Fatalf("ReadBuilds() = %v, want %v", got, data.PRStatus{})
 	}
 }
@@ -17,9 +17,10 @@ func CleanupPRData(ctx workflow.Context, channelID, prURL string) {
 		return
 	}
 
-	DeleteBitbucketBuilds(ctx, prURL)
+	DeleteBuilds(ctx, prURL)
 	DeleteDiffstat(ctx, prURL)
 	DeletePRSnapshot(ctx, prURL)
+	DeleteTasks(ctx, prURL)
 	DeleteTurns(ctx, prURL)
 
 	DeleteURLAndIDMapping(ctx, prURL)
@@ -0,0 +1,302 @@
+package internal
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tzrikka/revchat/pkg/config"
+	"github.com/tzrikka/xdg"
+)
+
+const (
+	// BuildHistoryDirSuffix is appended to a repository URL to get the path of its build history directory.
+	// The history is sharded by day into JSON Lines files (e.g. "2026-01-02.jsonl"): state transitions are
+	// appended to the file of the current day, and files older than [BuildHistoryRetention] are deleted,
+	// so existing data is never rewritten.
+	BuildHistoryDirSuffix = "_build_history"
+
+	// BuildHistoryRetention is the maximum age of build state transitions in the history of a repository.
+	BuildHistoryRetention = 90 * 24 * time.Hour
+
+	buildHistoryShardExt = ".jsonl"
+)
+
+var repoURLPattern = regexp.MustCompile(`^(https?://.+?)/pull(-requests|s)?/\d+`)
+
+// BuildTransition is a single build state change of a specific build key in a specific commit.
+type BuildTransition struct {
+	Time   time.Time `json:"time"`
+	Commit string    `json:"commit"`
+	Key    string    `json:"key"`
+	Name   string    `json:"name"`
+	State  string    `json:"state"`
+
+	// Failed is true if the build failed in this commit at any point up to and including this transition,
+	// so [RecordBuildTransition] needs to find only the latest transition, not the entire history.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// FlakyBuild summarizes the reruns of a specific build key in a repository, within a specific time window.
+type FlakyBuild struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Flips   int    `json:"flips"`   // Number of times the build failed and then succeeded in the same commit.
+	Commits int    `json:"commits"` // Number of commits in which the build finished (successfully or not).
+}
+
+// RepoURL returns the URL of the repository that a PR belongs to, or an empty string if this isn't a PR URL.
+func RepoURL(prURL string) string {
+	if m := repoURLPattern.FindStringSubmatch(prURL); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// RecordBuildTransition appends a build state to the history of the given repository, if it's
+// different from the last known state of the same build key in the same commit. It reports whether
+// this is a likely flaky build: a success after a failure in the same commit (i.e. a rerun).
+func RecordBuildTransition(_ context.Context, repoURL, commitHash, key string, cs CommitStatus) (bool, error) {
+	mu := getDataFileMutex(repoURL + BuildHistoryDirSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now().UTC()
+	last, err := lastBuildTransition(repoURL, now, commitHash, key)
+	if err != nil {
+		return false, err
+	}
+
+	failed := false
+	if last != nil {
+		if last.State == cs.State {
+			return false, nil
+		}
+		failed = last.Failed || last.State == "FAILED"
+	}
+
+	t := BuildTransition{Time: now, Commit: commitHash, Key: key, Name: cs.Name, State: cs.State}
+	t.Failed = failed || cs.State == "FAILED"
+	if err := appendBuildTransition(repoURL, t); err != nil {
+		return false, err
+	}
+
+	return failed && cs.State == "SUCCESSFUL", nil
+}
+
+// ListFlakyBuilds returns the build keys in the given repository which failed and then succeeded in the
+// same commit at least once in the last few days, sorted from the most to the least flaky (by number of
+// flips, then by the fraction of commits with flips, then alphabetically by key).
+func ListFlakyBuilds(_ context.Context, repoURL string, days int) ([]FlakyBuild, error) {
+	mu := getDataFileMutex(repoURL + BuildHistoryDirSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now().UTC()
+	transitions, err := readBuildHistory(repoURL, now, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return flakyBuilds(transitions, now.AddDate(0, 0, -days)), nil
+}
+
+func flakyBuilds(transitions []BuildTransition, since time.Time) []FlakyBuild {
+	type commitKey struct{ commit, key string }
+	failed := map[commitKey]bool{}
+	finished := map[commitKey]bool{}
+	stats := map[string]*FlakyBuild{}
+
+	for _, t := range transitions {
+		ck := commitKey{t.Commit, t.Key}
+		wasFailed := failed[ck]
+		if t.State == "FAILED" {
+			failed[ck] = true
+		}
+		if t.Time.Before(since) || (t.State != "SUCCESSFUL" && t.State != "FAILED") {
+			continue
+		}
+
+		fb, ok := stats[t.Key]
+		if !ok {
+			fb = &FlakyBuild{Key: t.Key}
+			stats[t.Key] = fb
+		}
+		fb.Name = t.Name
+		if !finished[ck] {
+			finished[ck] = true
+			fb.Commits++
+		}
+		if wasFailed && t.State == "SUCCESSFUL" {
+			fb.Flips++
+		}
+	}
+
+	var result []FlakyBuild
+	for _, fb := range stats {
+		if fb.Flips > 0 {
+			result = append(result, *fb)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b FlakyBuild) int {
+		if c := cmp.Compare(b.Flips, a.Flips); c != 0 {
+			return c
+		}
+		// Compare a.Flips / a.Commits with b.Flips / b.Commits, without floating-point division.
+		if c := cmp.Compare(b.Flips*a.Commits, a.Flips*b.Commits); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	return result
+}
+
+// buildHistoryDir returns the absolute path to the build history directory of the given repository.
+// This function creates the directory and any parent directories if they don't exist yet.
+func buildHistoryDir(repoURL string) (string, error) {
+	dir := urlPath(repoURL) + BuildHistoryDirSuffix
+	return xdg.CreateDir(xdg.DataHome, filepath.Join(config.DirName, dir))
+}
+
+// buildHistoryShards returns the chronological list of paths to the daily files of the given repository,
+// within the [BuildHistoryRetention] period. It may also delete expired daily files. This function
+// expects the calling function to hold the appropriate mutex for the given repository URL.
+func buildHistoryShards(repoURL string, now time.Time, prune bool) ([]string, error) {
+	dir, err := buildHistoryDir(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build history directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build history directory: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries { // Sorted by filename, i.e. chronologically.
+		day, err := time.Parse(time.DateOnly, strings.TrimSuffix(e.Name(), buildHistoryShardExt))
+		if e.IsDir() || !strings.HasSuffix(e.Name(), buildHistoryShardExt) || err != nil {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if now.Sub(day.AddDate(0, 0, 1)) > BuildHistoryRetention {
+			if prune {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// readBuildHistory returns the chronological list of build state transitions in the given repository,
+// within the [BuildHistoryRetention] period, across all commits (unlike [PRStatus] which contains only the
+// latest state of each build in a single PR). It may also delete expired daily files. This function
+// expects the calling function to hold the appropriate mutex for the given repository URL.
+func readBuildHistory(repoURL string, now time.Time, prune bool) ([]BuildTransition, error) {
+	paths, err := buildHistoryShards(repoURL, now, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	var transitions []BuildTransition
+	for _, path := range paths {
+		b, err := os.ReadFile(path) //gosec:disable G304 // URL received from signature-verified 3rd-party, suffix is hardcoded.
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		ts, err := decodeBuildTransitions(b)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, ts...)
+	}
+
+	return transitions, nil
+}
+
+// lastBuildTransition returns the latest state transition of a specific build key in a specific commit in
+// the given repository, or nil if there isn't any. It scans the daily files newest-first, and stops at the
+// first match, instead of reading the entire history. It may also delete expired daily files. This function
+// expects the calling function to hold the appropriate mutex for the given repository URL.
+func lastBuildTransition(repoURL string, now time.Time, commitHash, key string) (*BuildTransition, error) {
+	paths, err := buildHistoryShards(repoURL, now, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range slices.Backward(paths) {
+		b, err := os.ReadFile(path) //gosec:disable G304 // URL received from signature-verified 3rd-party, suffix is hardcoded.
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		if !bytes.Contains(b, []byte(commitHash)) {
+			continue // Skip decoding files that can't contain any match.
+		}
+
+		ts, err := decodeBuildTransitions(b)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range slices.Backward(ts) {
+			if t.Commit == commitHash && t.Key == key {
+				return &t, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func decodeBuildTransitions(b []byte) ([]BuildTransition, error) {
+	var transitions []BuildTransition
+	d := json.NewDecoder(bytes.NewReader(b))
+	for {
+		var t BuildTransition
+		if err := d.Decode(&t); err != nil {
+			if errors.Is(err, io.EOF) {
+				return transitions, nil
+			}
+			return nil, fmt.Errorf("failed to read/decode JSON: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+}
+
+// appendBuildTransition expects the calling function to hold the appropriate mutex for the given repository URL.
+func appendBuildTransition(repoURL string, t BuildTransition) error {
+	dir, err := buildHistoryDir(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to create build history directory: %w", err)
+	}
+
+	path := filepath.Join(dir, t.Time.Format(time.DateOnly)+buildHistoryShardExt)
+	appendFlags := os.O_APPEND | os.O_CREATE | os.O_WRONLY // != [fileFlags] to avoid truncation.
+	f, err := os.OpenFile(path, appendFlags, filePerms)    //gosec:disable G304 // URL received from signature-verified 3rd-party, suffix is hardcoded.
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(t); err != nil {
+		return fmt.Errorf("failed to append JSON line: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package internal_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tzrikka/revchat/pkg/config"
+	"github.com/tzrikka/revchat/pkg/data/internal"
+	"github.com/tzrikka/xdg"
+)
+
+func TestRepoURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		prURL string
+		want  string
+	}{
+		{
+			name:  "bitbucket_cloud",
+			prURL: "https://bitbucket.org/workspace/repo/pull-requests/1",
+			want:  "https://bitbucket.org/workspace/repo",
+		},
+		{
+			name:  "bitbucket_data_center",
+			prURL: "https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/2/overview",
+			want:  "https://bitbucket.example.com/projects/PROJ/repos/repo",
+		},
+		{
+			name:  "github",
+			prURL: "https://github.com/owner/repo/pull/3",
+			want:  "https://github.com/owner/repo",
+		},
+		{
+			name:  "not_a_pr",
+			prURL: "https://github.com/owner/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := internal.RepoURL(tt.prURL); got != tt.want {
+				t.Errorf("RepoURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHistory(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	repoURL := "https://bitbucket.org/workspace/repo"
+
+	// Initial state.
+	got, err := internal.ListFlakyBuilds(t.Context(), repoURL, 30)
+	if err != nil {
+		t.Fatalf("ListFlakyBuilds() error = %v", err)
+	}
+	if got != nil {
+		t.Fatalf("ListFlakyBuilds() = %v, want nil", got)
+	}
+
+	transitions := []struct {
+		commit, key, state string
+		wantFlaky          bool
+	}{
+		// Build "a" is flaky in 2 out of 3 commits.
+		{commit: "c1", key: "a", state: "INPROGRESS"},
+		{commit: "c1", key: "a", state: "FAILED"},
+		{commit: "c1", key: "a", state: "INPROGRESS"},
+		{commit: "c1", key: "a", state: "SUCCESSFUL", wantFlaky: true},
+		{commit: "c1", key: "a", state: "SUCCESSFUL"}, // Duplicate.
+		{commit: "c2", key: "a", state: "SUCCESSFUL"},
+		{commit: "c3", key: "a", state: "FAILED"},
+		{commit: "c3", key: "a", state: "SUCCESSFUL", wantFlaky: true},
+		// Build "b" is flaky in 1 out of 1 commit.
+		{commit: "c1", key: "b", state: "FAILED"},
+		{commit: "c1", key: "b", state: "SUCCESSFUL", wantFlaky: true},
+		// Build "c" is consistently failing, and then fixed in a different commit.
+		{commit: "c1", key: "c", state: "FAILED"},
+		{commit: "c2", key: "c", state: "FAILED"},
+		{commit: "c3", key: "c", state: "SUCCESSFUL"},
+		// Build "d" was stopped, and then succeeded.
+		{commit: "c1", key: "d", state: "STOPPED"},
+		{commit: "c1", key: "d", state: "SUCCESSFUL"},
+	}
+
+	for i, tr := range transitions {
+		cs := internal.CommitStatus{Name: "Build " + tr.key, State: tr.state}
+		flaky, err := internal.RecordBuildTransition(t.Context(), repoURL, tr.commit, tr.key, cs)
+		if err != nil {
+			t.Fatalf("RecordBuildTransition(%d) error = %v", i, err)
+		}
+		if flaky != tr.wantFlaky {
+			t.Errorf("RecordBuildTransition(%d) = %v, want %v", i, flaky, tr.wantFlaky)
+		}
+	}
+
+	got, err = internal.ListFlakyBuilds(t.Context(), repoURL, 30)
+	if err != nil {
+		t.Fatalf("ListFlakyBuilds() error = %v", err)
+	}
+	want := []internal.FlakyBuild{
+		{Key: "a", Name: "Build a", Flips: 2, Commits: 3},
+		{Key: "b", Name: "Build b", Flips: 1, Commits: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListFlakyBuilds() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildHistoryRetention(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	repoURL := "https://github.com/owner/repo"
+	dir := filepath.Join(d, config.DirName, "github.com/owner/repo"+internal.BuildHistoryDirSuffix)
+	if err := os.MkdirAll(dir, xdg.NewDirectoryPermissions); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := filepath.Join(dir, "2000-01-01.jsonl")
+	line := `{"time":"2000-01-01T00:00:00Z","commit":"c1","key":"a","name":"Build a","state":"FAILED"}` + "\n"
+	if err := os.WriteFile(expired, []byte(line), xdg.NewFilePermissions); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expired failure is ignored, so this isn't a flaky build.
+	cs := internal.CommitStatus{Name: "Build a", State: "SUCCESSFUL"}
+	flaky, err := internal.RecordBuildTransition(t.Context(), repoURL, "c1", "a", cs)
+	if err != nil {
+		t.Fatalf("RecordBuildTransition() error = %v", err)
+	}
+	if flaky {
+		t.Error("RecordBuildTransition() = true, want false")
+	}
+
+	if _, err := os.Stat(expired); !os.IsNotExist(err) {
+		t.Errorf("expired build history file wasn't deleted: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), ".jsonl") {
+		t.Errorf("build history files = %v, want 1 daily file", entries)
+	}
+}
+
+func TestBuildHistoryAcrossDays(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	repoURL := "https://github.com/owner/repo"
+	dir := filepath.Join(d, config.DirName, "github.com/owner/repo"+internal.BuildHistoryDirSuffix)
+	if err := os.MkdirAll(dir, xdg.NewDirectoryPermissions); err != nil {
+		t.Fatal(err)
+	}
+
+	// Build "a" in commit "c1" failed 2 days ago, and was rerun yesterday. Build "b" succeeded yesterday.
+	now := time.Now().UTC()
+	shards := map[string]string{
+		now.AddDate(0, 0, -2).Format(time.DateOnly): `{"commit":"c1","key":"a","state":"FAILED","failed":true}`,
+		now.AddDate(0, 0, -1).Format(time.DateOnly): `{"commit":"c1","key":"a","state":"INPROGRESS","failed":true}` + "\n" +
+			`{"commit":"c1","key":"b","state":"SUCCESSFUL"}`,
+	}
+	for day, lines := range shards {
+		if err := os.WriteFile(filepath.Join(dir, day+".jsonl"), []byte(lines+"\n"), xdg.NewFilePermissions); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		key, state string
+		wantFlaky  bool
+	}{
+		{key: "a", state: "SUCCESSFUL", wantFlaky: true},
+		{key: "a", state: "SUCCESSFUL"}, // Duplicate.
+		{key: "b", state: "SUCCESSFUL"}, // Duplicate of yesterday's state.
+		{key: "b", state: "FAILED"},
+		{key: "b", state: "SUCCESSFUL", wantFlaky: true},
+	}
+
+	for i, tt := range tests {
+		cs := internal.CommitStatus{Name: "Build " + tt.key, State: tt.state}
+		flaky, err := internal.RecordBuildTransition(t.Context(), repoURL, "c1", tt.key, cs)
+		if err != nil {
+			t.Fatalf("RecordBuildTransition(%d) error = %v", i, err)
+		}
+		if flaky != tt.wantFlaky {
+			t.Errorf("RecordBuildTransition(%d) = %v, want %v", i, flaky, tt.wantFlaky)
+		}
+	}
+}
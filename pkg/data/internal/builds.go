@@ -12,6 +12,9 @@ const (
 	BuildsFileSuffix = "_builds.json"
 )
 
+// CommitStatus represents a single build/check result. States are normalized
+// to Bitbucket's vocabulary, regardless of the PR's platform:
+// "INPROGRESS", "SUCCESSFUL", "FAILED", and "STOPPED".
 type CommitStatus struct {
 	Name  string `json:"name"`
 	State string `json:"state"`
@@ -20,26 +23,26 @@ type CommitStatus struct {
 }
 
 // PRStatus represents the current status of all reported
-// builds for a specific Bitbucket PR at a specific commit.
+// builds for a specific PR at a specific commit.
 type PRStatus struct {
 	CommitHash string                  `json:"commit_hash"`
 	Builds     map[string]CommitStatus `json:"builds"`
 }
 
-func ReadBitbucketBuilds(_ context.Context, prURL string) (*PRStatus, error) {
+func ReadBuilds(_ context.Context, prURL string) (*PRStatus, error) {
 	mu := getDataFileMutex(prURL + BuildsFileSuffix)
 	mu.Lock()
 	defer mu.Unlock()
 
-	return readBitbucketBuilds(prURL)
+	return readBuilds(prURL)
 }
 
-func UpdateBitbucketBuilds(_ context.Context, prURL, commitHash, key string, cs CommitStatus) error {
+func UpdateBuilds(_ context.Context, prURL, commitHash, key string, cs CommitStatus) error {
 	mu := getDataFileMutex(prURL + BuildsFileSuffix)
 	mu.Lock()
 	defer mu.Unlock()
 
-	status, err := readBitbucketBuilds(prURL)
+	status, err := readBuilds(prURL)
 	if err != nil {
 		return err
 	}
@@ -53,8 +56,8 @@ func UpdateBitbucketBuilds(_ context.Context, prURL, commitHash, key string, cs
 	return writeGenericJSONFile(prURL+BuildsFileSuffix, status)
 }
 
-// readBitbucketBuilds expects the calling function to hold the appropriate mutex for the given PR URL.
-func readBitbucketBuilds(prURL string) (*PRStatus, error) {
+// readBuilds expects the calling function to hold the appropriate mutex for the given PR URL.
+func readBuilds(prURL string) (*PRStatus, error) {
 	path, err := dataPath(prURL + BuildsFileSuffix)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file path: %w", err)
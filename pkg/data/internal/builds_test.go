@@ -7,20 +7,20 @@ import (
 	"github.com/tzrikka/revchat/pkg/data/internal"
 )
 
-func TestBitbucketBuilds(t *testing.T) {
+func TestBuilds(t *testing.T) {
 	d := t.TempDir()
 	t.Setenv("XDG_DATA_HOME", d)
 
 	prURL := "https://bitbucket.org/workspace/repo/pull-requests/1"
 
 	// Initial state.
-	got, err := internal.ReadBitbucketBuilds(t.Context(), prURL)
+	got, err := internal.ReadBuilds(t.Context(), prURL)
 	if err != nil {
-		t.Fatalf("ReadBitbucketBuilds() error = %v", err)
+		t.Fatalf("ReadBuilds() error = %v", err)
 	}
 
 	if got.Builds != nil {
-		t.Fatalf("ReadBitbucketBuilds() = %#v, want %#v", got, &internal.PRStatus{})
+		t.Fatalf("ReadBuilds() = %#v, want %#v", got, &internal.PRStatus{})
 	}
 
 	// Update build status.
@@ -30,13 +30,13 @@ func TestBitbucketBuilds(t *testing.T) {
 		Desc:  "Build passed",
 		URL:   "http://build1",
 	}
-	if err := internal.UpdateBitbucketBuilds(t.Context(), prURL, "commit1", "build1", cs1); err != nil {
-		t.Fatalf("UpdateBitbucketBuilds() error = %v", err)
+	if err := internal.UpdateBuilds(t.Context(), prURL, "commit1", "build1", cs1); err != nil {
+		t.Fatalf("UpdateBuilds() error = %v", err)
 	}
 
-	got, err = internal.ReadBitbucketBuilds(t.Context(), prURL)
+	got, err = internal.ReadBuilds(t.Context(), prURL)
 	if err != nil {
-		t.Fatalf("ReadBitbucketBuilds() error = %v", err)
+		t.Fatalf("ReadBuilds() error = %v", err)
 	}
 	want := &internal.PRStatus{
 		CommitHash: "commit1",
@@ -45,7 +45,7 @@ func TestBitbucketBuilds(t *testing.T) {
 		},
 	}
 	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, want)
+		t.Fatalf("ReadBuilds() = %v, want %v", got, want)
 	}
 
 	// Update with new commit.
@@ -55,13 +55,13 @@ func TestBitbucketBuilds(t *testing.T) {
 		Desc:  "Build failed",
 		URL:   "http://build2",
 	}
-	if err := internal.UpdateBitbucketBuilds(t.Context(), prURL, "commit2", "build2", cs2); err != nil {
-		t.Fatalf("UpdateBitbucketBuilds() error = %v", err)
+	if err := internal.UpdateBuilds(t.Context(), prURL, "commit2", "build2", cs2); err != nil {
+		t.Fatalf("UpdateBuilds() error = %v", err)
 	}
 
-	got, err = internal.ReadBitbucketBuilds(t.Context(), prURL)
+	got, err = internal.ReadBuilds(t.Context(), prURL)
 	if err != nil {
-		t.Fatalf("ReadBitbucketBuilds() error = %v", err)
+		t.Fatalf("ReadBuilds() error = %v", err)
 	}
 	want = &internal.PRStatus{
 		CommitHash: "commit2",
@@ -70,7 +70,7 @@ func TestBitbucketBuilds(t *testing.T) {
 		},
 	}
 	if !reflect.DeepEqual(got, want) {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, want)
+		t.Fatalf("ReadBuilds() = %v, want %v", got, want)
 	}
 
 	// Delete builds.
@@ -78,11 +78,11 @@ func TestBitbucketBuilds(t *testing.T) {
 		t.Fatalf("DeleteGenericPRFile() error = %v", err)
 	}
 
-	got, err = internal.ReadBitbucketBuilds(t.Context(), prURL)
+	got, err = internal.ReadBuilds(t.Context(), prURL)
 	if err != nil {
-		t.Fatalf("ReadBitbucketBuilds() error = %v", err)
+		t.Fatalf("ReadBuilds() error = %v", err)
 	}
 	if got.Builds != nil {
-		t.Fatalf("ReadBitbucketBuilds() = %v, want %v", got, &internal.PRStatus{})
+		t.Fatalf("ReadBuilds() = %v, want %v", got, &internal.PRStatus{})
 	}
 }
@@ -18,6 +18,8 @@ import (
 const (
 	fileFlags = os.O_CREATE | os.O_TRUNC | os.O_WRONLY
 	filePerms = xdg.NewFilePermissions
+
+	httpDir = "_http/"
 )
 
 // dataFileMutexMap is a package-level, concurrency-safe cache that maps string keys to [sync.Mutex] pointers,
@@ -42,7 +44,7 @@ func getDataFileMutex(key string) *sync.Mutex {
 // The relative path can be a filename, or a PR's URL with a file-content-type suffix.
 // This function creates the file and any parent directories if they don't exist yet.
 func dataPath(urlWithSuffix string) (string, error) {
-	path, err := xdg.CreateFilePath(xdg.DataHome, config.DirName, strings.TrimPrefix(urlWithSuffix, "https://"))
+	path, err := xdg.CreateFilePath(xdg.DataHome, config.DirName, urlPath(urlWithSuffix))
 	if err != nil {
 		return "", fmt.Errorf("failed to create data file path: %w", err)
 	}
@@ -51,6 +53,24 @@ func dataPath(urlWithSuffix string) (string, error) {
 	return path, nil
 }
 
+// urlPath converts a URL into a path relative to RevChat's data directory. The "https://" scheme is
+// implied, so it's omitted. URLs with an "http://" scheme (e.g. self-hosted Bitbucket Data Center
+// instances) are stored under the "_http" directory, which can't be a host name, see [pathURL].
+func urlPath(url string) string {
+	if host, found := strings.CutPrefix(url, "http://"); found {
+		return httpDir + host
+	}
+	return strings.TrimPrefix(url, "https://")
+}
+
+// pathURL is the inverse of [urlPath].
+func pathURL(path string) string {
+	if host, found := strings.CutPrefix(path, httpDir); found {
+		return "http://" + host
+	}
+	return "https://" + path
+}
+
 // fixEmptyJSONFile checks if the given path points to an empty JSON file.
 // If so, it writes an appropriate empty JSON structure to it (either "{}"
 // or "[]"). This is useful because empty files can't be decoded as JSON,
@@ -115,6 +115,42 @@ func TestDataPath(t *testing.T) {
 	}
 }
 
+func TestURLPath(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "https",
+			url:  "https://bitbucket.org/workspace/repo/pull-requests/1",
+			want: "bitbucket.org/workspace/repo/pull-requests/1",
+		},
+		{
+			name: "http",
+			url:  "http://bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/1",
+			want: "_http/bitbucket.example.com/projects/PRJ/repos/repo/pull-requests/1",
+		},
+		{
+			name: "filename",
+			url:  "users.json",
+			want: "users.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := urlPath(tt.url)
+			if got != tt.want {
+				t.Errorf("urlPath() = %q, want %q", got, tt.want)
+			}
+			if tt.url != tt.want && pathURL(got) != tt.url {
+				t.Errorf("pathURL() = %q, want %q", pathURL(got), tt.url)
+			}
+		})
+	}
+}
+
 func TestDeleteGenericPRFile(t *testing.T) {
 	d := t.TempDir()
 	t.Setenv("XDG_DATA_HOME", d)
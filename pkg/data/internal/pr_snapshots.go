@@ -77,7 +77,7 @@ func ReadPRSnapshot(_ context.Context, prURL string) (map[string]any, error) {
 }
 
 // FindPRsByCommit returns all (0 or more) the PR snapshots that are currently associated with the given
-// commit hash. This is used when processing Bitbucket commit events, to identify the relevant PR(s).
+// commit hash. This is used when processing commit and build events, to identify the relevant PR(s).
 // To do this, this function scans through all the PR snapshots and checks their current commit hashes.
 // Lastly, this function prunes redundant PR details, to reduce Temporal log size and noise.
 func FindPRsByCommit(ctx context.Context, hash string) (prs []map[string]any, err error) {
@@ -95,14 +95,14 @@ func FindPRsByCommit(ctx context.Context, hash string) (prs []map[string]any, er
 			return nil
 		}
 
-		prURL := "https://" + strings.TrimSuffix(path, PRSnapshotFileSuffix)
+		prURL := pathURL(strings.TrimSuffix(path, PRSnapshotFileSuffix))
 		snapshot, err := ReadPRSnapshot(ctx, prURL)
 		if err != nil || snapshot == nil {
 			return nil
 		}
 
 		// The hash from the event (hash) is always the full commit hash, but the one in the snapshot (prHash) may be truncated.
-		if prHash := prCommitHash(snapshot); prHash != "" && strings.HasPrefix(hash, prHash) {
+		if prHash := PRCommitHash(snapshot); prHash != "" && strings.HasPrefix(hash, prHash) {
 			prs = append(prs, snapshot)
 		}
 
@@ -113,7 +113,7 @@ func FindPRsByCommit(ctx context.Context, hash string) (prs []map[string]any, er
 	for _, pr := range prs {
 		for k := range pr {
 			switch k {
-			case "draft", "change_request_count", "task_count":
+			case "draft", "change_request_count", "task_count", "html_url", "labels":
 				// Don't touch these fields.
 			case "links":
 				links, ok := pr["links"].(map[string]any)
@@ -125,6 +125,16 @@ func FindPRsByCommit(ctx context.Context, hash string) (prs []map[string]any, er
 						delete(links, lk)
 					}
 				}
+			case "user": // GitHub PR author.
+				user, ok := pr["user"].(map[string]any)
+				if !ok {
+					break
+				}
+				for uk := range user {
+					if uk != "login" {
+						delete(user, uk)
+					}
+				}
 			case "participants":
 				ps, ok := pr["participants"].([]any)
 				if !ok {
@@ -150,7 +160,36 @@ func FindPRsByCommit(ctx context.Context, hash string) (prs []map[string]any, er
 	return prs, err
 }
 
-func prCommitHash(pr map[string]any) string {
+// ListPRsInRepo returns the URLs of all (0 or more) the PRs in the given repository that have snapshots.
+// This is used when a commit is not necessarily the latest one in its PRs, so [FindPRsByCommit] can't
+// find them by their current commit hashes, and the caller needs to check each PR's commits instead.
+func ListPRsInRepo(_ context.Context, repoURL string) (prURLs []string, err error) {
+	root, err := xdg.CreateDir(xdg.DataHome, config.DirName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	err = fs.WalkDir(os.DirFS(root), urlPath(repoURL), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && strings.HasSuffix(d.Name(), PRSnapshotFileSuffix) {
+			prURLs = append(prURLs, pathURL(strings.TrimSuffix(path, PRSnapshotFileSuffix)))
+		}
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil // No tracked PRs in this repository.
+	}
+
+	return prURLs, err
+}
+
+// PRCommitHash returns the hash of the latest commit in a Bitbucket Cloud or GitHub PR
+// snapshot, or an empty string if it's missing. Bitbucket hashes may be truncated.
+func PRCommitHash(pr map[string]any) string {
 	if hash := prCommitHashBitbucket(pr); hash != "" {
 		return hash
 	}
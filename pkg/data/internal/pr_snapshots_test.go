@@ -52,9 +52,9 @@ func TestPRCommitHash(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := prCommitHash(tt.pr)
+			got := PRCommitHash(tt.pr)
 			if got != tt.want {
-				t.Errorf("prCommitHash() = %q, want %q", got, tt.want)
+				t.Errorf("PRCommitHash() = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -80,6 +80,10 @@ func TestFindPRsByCommit(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(path, "pr3"+PRSnapshotFileSuffix), []byte(pr), xdg.NewFilePermissions); err != nil {
 		t.Fatalf("os.WriteFile() error = %v", err)
 	}
+	pr = `{"head":{"sha":"789abc"}}`
+	if err := os.WriteFile(filepath.Join(path, "pr4"+PRSnapshotFileSuffix), []byte(pr), xdg.NewFilePermissions); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
 
 	tests := []struct {
 		name    string
@@ -100,6 +104,11 @@ func TestFindPRsByCommit(t *testing.T) {
 			hash:    "def456",
 			wantLen: 2,
 		},
+		{
+			name:    "found_github",
+			hash:    "789abc",
+			wantLen: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -184,6 +193,106 @@ func TestFindPRsByCommit_pruning(t *testing.T) {
 	}
 }
 
+func TestFindPRsByCommit_pruningGitHub(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	path, err := xdg.CreateDir(xdg.DataHome, config.DirName)
+	if err != nil {
+		t.Fatalf("xdg.CreateDir() error = %v", err)
+	}
+
+	pr := `{
+		"head": {"sha": "abc123", "ref": "branch"},
+		"html_url": "https://github.com/owner/repo/pull/1",
+		"draft": false,
+		"labels": [{"name": "do-not-merge"}],
+		"user": {"login": "author", "id": 123, "type": "User"},
+		"title": "Title",
+		"number": 1
+	}`
+
+	if err := os.WriteFile(filepath.Join(path, "pr1"+PRSnapshotFileSuffix), []byte(pr), xdg.NewFilePermissions); err != nil {
+		t.Fatalf("os.WriteFile() error = %v", err)
+	}
+
+	got, err := FindPRsByCommit(t.Context(), "abc123")
+	if err != nil {
+		t.Fatalf("FindPRsByCommit() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("FindPRsByCommit() len = %d, want %d", len(got), 1)
+	}
+
+	m := got[0]
+	if len(m) != 4 {
+		t.Errorf("FindPRsByCommit() map len = %d, want %d", len(m), 4)
+	}
+	if m["html_url"] != "https://github.com/owner/repo/pull/1" {
+		t.Errorf("FindPRsByCommit() html_url field = %v, want %v", m["html_url"], "https://github.com/owner/repo/pull/1")
+	}
+	if _, found := m["draft"]; !found {
+		t.Errorf("FindPRsByCommit() draft field not found")
+	}
+	if _, found := m["labels"]; !found {
+		t.Errorf("FindPRsByCommit() labels field not found")
+	}
+	if user, ok := m["user"].(map[string]any); !ok || len(user) != 1 || user["login"] != "author" {
+		t.Errorf("FindPRsByCommit() user field = %v, want only the login", m["user"])
+	}
+}
+
+func TestListPRsInRepo(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	for _, prURL := range []string{
+		"https://bitbucket.org/workspace/repo/pull-requests/1",
+		"https://bitbucket.org/workspace/repo/pull-requests/2",
+		"https://bitbucket.org/workspace/repo2/pull-requests/3",
+	} {
+		if err := WritePRSnapshot(t.Context(), prURL, map[string]any{"id": 1}); err != nil {
+			t.Fatalf("WritePRSnapshot() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		repoURL string
+		want    []string
+	}{
+		{
+			name:    "multiple_prs",
+			repoURL: "https://bitbucket.org/workspace/repo",
+			want: []string{
+				"https://bitbucket.org/workspace/repo/pull-requests/1",
+				"https://bitbucket.org/workspace/repo/pull-requests/2",
+			},
+		},
+		{
+			name:    "single_pr",
+			repoURL: "https://bitbucket.org/workspace/repo2",
+			want:    []string{"https://bitbucket.org/workspace/repo2/pull-requests/3"},
+		},
+		{
+			name:    "untracked_repo",
+			repoURL: "https://bitbucket.org/workspace/repo3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ListPRsInRepo(t.Context(), tt.repoURL)
+			if err != nil {
+				t.Fatalf("ListPRsInRepo() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListPRsInRepo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // The unit tests below are the same as in data/pr_snapshots_test.go.
 
 func TestPRSnapshot(t *testing.T) {
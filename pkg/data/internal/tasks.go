@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	TasksFileSuffix = "_tasks.json"
+)
+
+// Task is the last-known state of a single Bitbucket PR task.
+type Task struct {
+	ID       int    `json:"id"`
+	Text     string `json:"text"`
+	Resolved bool   `json:"resolved,omitempty"`
+}
+
+// PRTasks is the last-known state of all the tasks in a specific Bitbucket PR,
+// and the ID of the Slack message which displays them as a checklist.
+type PRTasks struct {
+	ChecklistTS string `json:"checklist_ts,omitempty"`
+	Tasks       []Task `json:"tasks"`
+}
+
+func ReadTasks(_ context.Context, prURL string) (*PRTasks, error) {
+	mu := getDataFileMutex(prURL + TasksFileSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	return readTasks(prURL)
+}
+
+func WriteTasks(_ context.Context, prURL string, tasks PRTasks) error {
+	mu := getDataFileMutex(prURL + TasksFileSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	return writeGenericJSONFile(prURL+TasksFileSuffix, tasks)
+}
+
+// readTasks expects the calling function to hold the appropriate mutex for the given PR URL.
+func readTasks(prURL string) (*PRTasks, error) {
+	path, err := dataPath(prURL + TasksFileSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file path: %w", err)
+	}
+
+	f, err := os.Open(path) //gosec:disable G304 // URL received from signature-verified 3rd-party, suffix is hardcoded.
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &PRTasks{}, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	tasks := new(PRTasks)
+	if err := json.NewDecoder(f).Decode(tasks); err != nil {
+		return nil, fmt.Errorf("failed to read/decode JSON: %w", err)
+	}
+	return tasks, nil
+}
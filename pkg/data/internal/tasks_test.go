@@ -0,0 +1,57 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/data/internal"
+)
+
+func TestTasks(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	prURL := "https://bitbucket.org/workspace/repo/pull-requests/1"
+
+	// Initial state.
+	got, err := internal.ReadTasks(t.Context(), prURL)
+	if err != nil {
+		t.Fatalf("ReadTasks() error = %v", err)
+	}
+	if got.ChecklistTS != "" || got.Tasks != nil {
+		t.Fatalf("ReadTasks() = %#v, want %#v", got, &internal.PRTasks{})
+	}
+
+	// Write and read tasks.
+	want := &internal.PRTasks{
+		ChecklistTS: "1234567890.123456",
+		Tasks: []internal.Task{
+			{ID: 1, Text: "Fix typo"},
+			{ID: 2, Text: "Add tests", Resolved: true},
+		},
+	}
+	if err := internal.WriteTasks(t.Context(), prURL, *want); err != nil {
+		t.Fatalf("WriteTasks() error = %v", err)
+	}
+
+	got, err = internal.ReadTasks(t.Context(), prURL)
+	if err != nil {
+		t.Fatalf("ReadTasks() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadTasks() = %v, want %v", got, want)
+	}
+
+	// Delete tasks.
+	if err := internal.DeleteGenericPRFile(t.Context(), prURL+internal.TasksFileSuffix); err != nil {
+		t.Fatalf("DeleteGenericPRFile() error = %v", err)
+	}
+
+	got, err = internal.ReadTasks(t.Context(), prURL)
+	if err != nil {
+		t.Fatalf("ReadTasks() error = %v", err)
+	}
+	if got.Tasks != nil {
+		t.Fatalf("ReadTasks() = %v, want %v", got, &internal.PRTasks{})
+	}
+}
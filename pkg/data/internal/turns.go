@@ -16,6 +16,7 @@ import (
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/client"
 
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/config"
 	"github.com/tzrikka/timpani-api/pkg/jira"
 	"github.com/tzrikka/timpani-api/pkg/slack"
@@ -35,6 +36,7 @@ type PRTurns struct {
 	Reviewers map[string]bool      `json:"reviewers,omitempty"` // Email address -> is it their turn?
 	Activity  map[string]time.Time `json:"activity,omitempty"`  // When each user last interacted with the PR.
 	Approvers map[string]time.Time `json:"approvers,omitempty"` // When each user approved the PR.
+	Snoozed   map[string]time.Time `json:"snoozed,omitempty"`   // When each user's personal snooze expires.
 
 	FrozenAt time.Time `json:"frozen_at,omitzero"`
 	FrozenBy string    `json:"frozen_by,omitempty"`
@@ -96,6 +98,7 @@ func SetReviewerTurn(ctx context.Context, opts client.Options, prURL, email stri
 	}
 	// Valid and necessary state change.
 	t.Reviewers[email] = true
+	handOverAwayTurns(ctx, t, time.Now().UTC())
 
 	if err := writeTurns(prURL, t); err != nil {
 		return [2]bool{false, false}, err
@@ -130,6 +133,7 @@ func SwitchTurn(ctx context.Context, opts client.Options, prURL, email string, f
 				t.Reviewers[email] = false
 			}
 		}
+		handOverAwayTurns(ctx, t, time.Now().UTC())
 	}
 
 	t.Activity[email] = time.Now().UTC() // Record activity regardless of frozen state.
@@ -141,6 +145,38 @@ func SwitchTurn(ctx context.Context, opts client.Options, prURL, email string, f
 	return nil
 }
 
+// handOverAwayTurns switches the turns of reviewers who are out of office, and chose a delegate, to that
+// delegate (see [User.AwayAt]). This is called whenever turns are assigned, so it also covers turns which are
+// assigned after the away period started. Delegates who authored or already approved the PR, or who are away
+// too, can't receive turns, so in these cases the turns remain with the original reviewers.
+func handOverAwayTurns(ctx context.Context, t *PRTurns, now time.Time) {
+	for _, email := range slices.Sorted(maps.Keys(t.Reviewers)) {
+		if !t.Reviewers[email] {
+			continue
+		}
+
+		reviewer, _ := SelectUser(ctx, IndexByEmail, email)
+		p, away := reviewer.AwayAt(now)
+		if !away || p.Delegate == "" {
+			continue
+		}
+
+		delegate, _ := SelectUser(ctx, IndexBySlackID, p.Delegate)
+		if delegate.Email == "" || delegate.Email == t.Author {
+			continue
+		}
+		if _, found := t.Reviewers[delegate.Email]; !found && !t.Approvers[delegate.Email].IsZero() {
+			continue
+		}
+		if _, away := delegate.AwayAt(now); away {
+			continue
+		}
+
+		t.Reviewers[email] = false
+		t.Reviewers[delegate.Email] = true
+	}
+}
+
 // RemoveReviewerFromTurns completely removes a reviewer from the attention state of a specific PR. This is called when that
 // reviewer approves the PR, or is unassigned from it. This function is idempotent: if the reviewer does not exist, it does nothing.
 func RemoveReviewerFromTurns(ctx context.Context, opts client.Options, prURL, email string, approved bool) error {
@@ -231,6 +267,23 @@ func readAllParticipantEmails(ctx context.Context, opts client.Options, prURL st
 	return slices.Compact(emails), nil
 }
 
+// ReadParticipantRoles checks whether a specific user is the author of a specific PR, and whether they are tracked as
+// one of its reviewers (i.e. they didn't approve it yet). The results are returned as a single array, just like in
+// [SetReviewerTurn], because Temporal activities can't return multiple values.
+func ReadParticipantRoles(ctx context.Context, opts client.Options, prURL, email string) ([2]bool, error) {
+	mu := getDataFileMutex(prURL + TurnsFileSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	t, err := readTurns(ctx, opts, prURL)
+	if err != nil {
+		return [2]bool{}, err
+	}
+
+	_, isReviewer := t.Reviewers[email]
+	return [2]bool{t.Author == email, isReviewer}, nil
+}
+
 // ReadPRsPerSlackUser scans all stored PR turn files, and returns a mapping
 // from Slack user IDs to all the PR URLs they need to be reminded about.
 func ReadPRsPerSlackUser(ctx context.Context, op client.Options, currentTurn, authors, reviewers bool, filter []string) (map[string][]string, error) {
@@ -249,7 +302,7 @@ func ReadPRsPerSlackUser(ctx context.Context, op client.Options, currentTurn, au
 			return nil
 		}
 
-		prURL := "https://" + strings.TrimSuffix(path, TurnsFileSuffix)
+		prURL := pathURL(strings.TrimSuffix(path, TurnsFileSuffix))
 		var emails []string
 		if currentTurn {
 			emails, err = ReadCurrentTurnEmails(ctx, op, prURL)
@@ -404,6 +457,86 @@ func IsFrozen(ctx context.Context, opts client.Options, prURL string) (Frozen, e
 	return Frozen{At: t.FrozenAt, By: t.FrozenBy}, nil
 }
 
+// SnoozePR hides a specific PR from a specific user's reminders until the given time, without changing anyone's
+// turn. This also removes all expired snoozes from the PR's attention state. A zero time cancels the user's snooze.
+func SnoozePR(ctx context.Context, opts client.Options, prURL, email string, until time.Time) error {
+	mu := getDataFileMutex(prURL + TurnsFileSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	t, err := readTurns(ctx, opts, prURL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	maps.DeleteFunc(t.Snoozed, func(_ string, expiry time.Time) bool {
+		return !expiry.After(now)
+	})
+
+	if until.After(now) {
+		t.Snoozed[email] = until.UTC()
+	} else {
+		delete(t.Snoozed, email)
+	}
+
+	return writeTurns(prURL, t)
+}
+
+// GetSnoozeTime returns the time when a specific user's snooze of a specific PR expires.
+// If the user didn't snooze the PR, or the snooze already expired, it returns a zero timestamp.
+func GetSnoozeTime(ctx context.Context, opts client.Options, prURL, email string) (time.Time, error) {
+	mu := getDataFileMutex(prURL + TurnsFileSuffix)
+	mu.Lock()
+	defer mu.Unlock()
+
+	t, err := readTurns(ctx, opts, prURL)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if until := t.Snoozed[email]; until.After(time.Now().UTC()) {
+		return until, nil
+	}
+	return time.Time{}, nil
+}
+
+// ReadSnoozedPRs scans all stored PR turn files, and returns a mapping from the
+// URLs of the PRs that a specific user snoozed to the expiry times of these snoozes.
+func ReadSnoozedPRs(ctx context.Context, opts client.Options, email string) (map[string]time.Time, error) {
+	root, err := xdg.CreateDir(xdg.DataHome, config.DirName)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := map[string]time.Time{}
+	err = fs.WalkDir(os.DirFS(root), ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(d.Name(), TurnsFileSuffix) {
+			return nil
+		}
+
+		prURL := pathURL(strings.TrimSuffix(path, TurnsFileSuffix))
+		until, err := GetSnoozeTime(ctx, opts, prURL, email)
+		if err != nil {
+			return nil // Skip files with errors, but keep scanning the rest.
+		}
+
+		if !until.IsZero() {
+			prs[prURL] = until
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
 // writeTurns expects the calling function to hold the appropriate mutex for the given PR URL.
 func writeTurns(prURL string, t *PRTurns) error {
 	normalizeEmailAddresses(t)
@@ -423,7 +556,7 @@ func normalizeEmailAddresses(t *PRTurns) {
 		delete(t.Reviewers, user)
 	}
 
-	for _, m := range []map[string]time.Time{t.Activity, t.Approvers} {
+	for _, m := range []map[string]time.Time{t.Activity, t.Approvers, t.Snoozed} {
 		for user, timestamp := range m {
 			if strings.ToLower(user) == user {
 				continue
@@ -471,6 +604,9 @@ func readTurns(ctx context.Context, opts client.Options, prURL string) (*PRTurns
 	if t.Approvers == nil {
 		t.Approvers = make(map[string]time.Time)
 	}
+	if t.Snoozed == nil {
+		t.Snoozed = make(map[string]time.Time)
+	}
 
 	return t, nil
 }
@@ -503,6 +639,9 @@ func resetTurns(ctx context.Context, opts client.Options, prURL string, t *PRTur
 		if t.Approvers == nil {
 			t.Approvers = make(map[string]time.Time)
 		}
+		if t.Snoozed == nil {
+			t.Snoozed = make(map[string]time.Time)
+		}
 
 		if err := writeTurns(prURL, t); err != nil {
 			return nil, fmt.Errorf("failed to reset turns file: %w", err)
@@ -530,7 +669,7 @@ func resetTurns(ctx context.Context, opts client.Options, prURL string, t *PRTur
 		}
 	}
 
-	t = &PRTurns{Author: author, Reviewers: reviewers, Activity: activity, Approvers: approvers}
+	t = &PRTurns{Author: author, Reviewers: reviewers, Activity: activity, Approvers: approvers, Snoozed: map[string]time.Time{}}
 	if err := writeTurns(prURL, t); err != nil {
 		return nil, fmt.Errorf("failed to reset turns file: %w", err)
 	}
@@ -599,6 +738,9 @@ func bitbucketIDToEmail(ctx context.Context, opts client.Options, accountID, acc
 	if accountID == "" {
 		return "" // Unknown user, so no point to look-up in Jira.
 	}
+	if email, ok := strings.CutPrefix(accountID, datacenter.AccountIDPrefix); ok {
+		return email // Bitbucket Data Center users are identified by their email addresses.
+	}
 
 	if user, _ := SelectUser(ctx, IndexByBitbucketID, accountID); user.Email != "" {
 		return user.Email // No need to check for errors here, it's a prerequisite for the result to be non-empty.
@@ -776,3 +776,76 @@ func TestNudge(t *testing.T) {
 		t.Fatalf("ReadCurrentTurnEmails() = %v, want %v", got, want)
 	}
 }
+
+func TestTurnsOfAwayReviewers(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+	usersDB = nil // Don't reuse users from other tests.
+
+	now := time.Now().UTC()
+	for _, u := range [][2]string{{"rev1@example.com", "U1"}, {"rev2@example.com", "U2"}, {"delegate@example.com", "U3"}} {
+		if _, err := UpsertUser(t.Context(), u[0], "", "", "", u[1], ""); err != nil {
+			t.Fatalf("UpsertUser() error = %v", err)
+		}
+	}
+	away := []AwayPeriod{{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Delegate: "U3"}}
+	if _, err := SetAwayPeriods(t.Context(), "U1", away); err != nil {
+		t.Fatalf("SetAwayPeriods() error = %v", err)
+	}
+
+	url := "https://bitbucket.org/workspace/repo/pull-requests/1"
+	if err := InitTurns(url, "author@example.com"); err != nil {
+		t.Fatalf("InitTurns() error = %v", err)
+	}
+
+	// Rev1 is added as a reviewer while they're away --> it's the delegate's turn instead.
+	if _, err := SetReviewerTurn(t.Context(), client.Options{}, url, "rev1@example.com", false); err != nil {
+		t.Fatalf("SetReviewerTurn() error = %v", err)
+	}
+	if _, err := SetReviewerTurn(t.Context(), client.Options{}, url, "rev2@example.com", false); err != nil {
+		t.Fatalf("SetReviewerTurn() error = %v", err)
+	}
+
+	got, err := ReadCurrentTurnEmails(t.Context(), client.Options{}, url)
+	if err != nil {
+		t.Fatalf("ReadCurrentTurnEmails() error = %v", err)
+	}
+	want := []string{"author@example.com", "delegate@example.com", "rev2@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadCurrentTurnEmails() = %v, want %v", got, want)
+	}
+
+	// The delegate responds, and then the author responds --> still not rev1's turn.
+	if err := SwitchTurn(t.Context(), client.Options{}, url, "delegate@example.com", false); err != nil {
+		t.Fatalf("SwitchTurn() error = %v", err)
+	}
+	if err := SwitchTurn(t.Context(), client.Options{}, url, "author@example.com", false); err != nil {
+		t.Fatalf("SwitchTurn() error = %v", err)
+	}
+
+	got, err = ReadCurrentTurnEmails(t.Context(), client.Options{}, url)
+	if err != nil {
+		t.Fatalf("ReadCurrentTurnEmails() error = %v", err)
+	}
+	want = []string{"author@example.com", "delegate@example.com", "rev2@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadCurrentTurnEmails() = %v, want %v", got, want)
+	}
+
+	// Rev1 is back --> their turns aren't handed over anymore.
+	if _, err := SetAwayPeriods(t.Context(), "U1", nil); err != nil {
+		t.Fatalf("SetAwayPeriods() error = %v", err)
+	}
+	if err := SwitchTurn(t.Context(), client.Options{}, url, "author@example.com", false); err != nil {
+		t.Fatalf("SwitchTurn() error = %v", err)
+	}
+
+	got, err = ReadCurrentTurnEmails(t.Context(), client.Options{}, url)
+	if err != nil {
+		t.Fatalf("ReadCurrentTurnEmails() error = %v", err)
+	}
+	want = []string{"delegate@example.com", "rev1@example.com", "rev2@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadCurrentTurnEmails() = %v, want %v", got, want)
+	}
+}
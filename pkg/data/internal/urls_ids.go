@@ -84,7 +84,7 @@ const (
 )
 
 var (
-	PullRequestURLPattern = regexp.MustCompile(`https://[^/]+/[^/]+/[^/]+/pull(-requests)?/\d+`)
+	PullRequestURLPattern = regexp.MustCompile(`https?://[^/]+/(projects/)?[^/]+/(repos/)?[^/]+/pull(-requests)?/\d+`)
 	SlackChannelIDPattern = regexp.MustCompile(`^C[A-Z0-9]+`)
 )
 
@@ -9,6 +9,8 @@ import (
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 )
 
 const (
@@ -37,6 +39,9 @@ type User struct {
 	// Slack user IDs, controlled by the un/follow Slack commands, used when creating channels.
 	Followers []string `json:"followers,omitempty"`
 
+	// Out-of-office periods, controlled by the away Slack command, or imported from ICS calendar files.
+	Away []AwayPeriod `json:"away,omitempty"`
+
 	Created time.Time `json:"created,omitzero"`
 	Updated time.Time `json:"updated,omitzero"`
 	Deleted time.Time `json:"deleted,omitzero"`
@@ -46,6 +51,27 @@ func (u User) IsOptedIn() bool {
 	return u.ThrippyLink != ""
 }
 
+// AwayPeriod is a time range in which a user is out of office. During this time RevChat stops sending
+// them reminders, and (optionally) the user's turns are handed to a delegate, identified by a Slack ID.
+type AwayPeriod struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Delegate string    `json:"delegate,omitempty"`
+
+	// HandedOver indicates that the user's existing turns were already handed to the delegate.
+	HandedOver bool `json:"handed_over,omitempty"`
+}
+
+// AwayAt returns the user's out-of-office period that contains the given time, if there is one.
+func (u User) AwayAt(t time.Time) (AwayPeriod, bool) {
+	for _, p := range u.Away {
+		if !t.Before(p.Start) && t.Before(p.End) {
+			return p, true
+		}
+	}
+	return AwayPeriod{}, false
+}
+
 // Users is an indexed copy of a collection of [User] entries.
 // This should really be stored in a relational database.
 type Users struct {
@@ -222,6 +248,36 @@ func RemoveFollower(_ context.Context, followerSlackID string) error {
 	return usersDB.writeUsersFile()
 }
 
+// SetAwayPeriods discards periods which already ended (or are empty), and sorts the rest.
+func SetAwayPeriods(_ context.Context, slackID string, periods []AwayPeriod) (User, error) {
+	mu := getDataFileMutex(usersFile)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := initUsersDBIfNeeded(); err != nil {
+		return User{}, err
+	}
+
+	i, err := usersDB.findUserIndex("", "", "", "", slackID)
+	if err != nil || i < 0 {
+		return User{}, err
+	}
+
+	now := time.Now().UTC()
+	periods = slices.DeleteFunc(slices.Clone(periods), func(p AwayPeriod) bool {
+		return !p.End.After(now) || !p.End.After(p.Start)
+	})
+	slices.SortFunc(periods, func(a, b AwayPeriod) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	usersDB.entries[i].Away = periods
+	usersDB.entries[i].Updated = now
+
+	user := usersDB.entries[i]
+	return user, usersDB.writeUsersFile()
+}
+
 func (u *Users) findUserIndex(email, realName, bitbucketID, githubID, slackID string) (int, error) {
 	emailIndex, emailFound := u.emailIndex[email]
 	nameIndex, nameFound := u.nameIndex[realName]
@@ -276,6 +332,10 @@ func SelectUser(_ context.Context, indexType int, id string) (User, error) {
 		index = usersDB.emailIndex
 	case IndexByBitbucketID:
 		index = usersDB.bitbucketIndex
+		// Bitbucket Data Center pseudo account IDs are based on email addresses.
+		if email, ok := strings.CutPrefix(id, datacenter.AccountIDPrefix); ok {
+			index, id = usersDB.emailIndex, email
+		}
 	case IndexByGitHubID:
 		index = usersDB.githubIndex
 	case IndexBySlackID:
@@ -2,6 +2,7 @@ package internal_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/tzrikka/revchat/pkg/data/internal"
 )
@@ -59,3 +60,45 @@ func TestUsers(t *testing.T) {
 		t.Errorf("SelectUser() optedIn = %v, want %v", gotUser.IsOptedIn(), false)
 	}
 }
+
+func TestAwayPeriods(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	slackID := "U123"
+	if _, err := internal.UpsertUser(t.Context(), "email@example.com", "", "", "", slackID, ""); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+
+	now := time.Now().UTC()
+	ended := internal.AwayPeriod{Start: now.Add(-48 * time.Hour), End: now.Add(-24 * time.Hour)}
+	future := internal.AwayPeriod{Start: now.Add(48 * time.Hour), End: now.Add(72 * time.Hour)}
+	current := internal.AwayPeriod{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Delegate: "U456"}
+
+	got, err := internal.SetAwayPeriods(t.Context(), slackID, []internal.AwayPeriod{future, ended, current})
+	if err != nil {
+		t.Fatalf("SetAwayPeriods() error = %v", err)
+	}
+	if len(got.Away) != 2 {
+		t.Fatalf("SetAwayPeriods() = %d periods, want 2", len(got.Away))
+	}
+	if !got.Away[0].Start.Equal(current.Start) || !got.Away[1].Start.Equal(future.Start) {
+		t.Errorf("SetAwayPeriods() = %v, want sorted by start time", got.Away)
+	}
+
+	p, ok := got.AwayAt(now)
+	if !ok || p.Delegate != current.Delegate {
+		t.Errorf("AwayAt(now) = %v, %v, want %v, true", p, ok, current)
+	}
+	if _, ok := got.AwayAt(now.Add(24 * time.Hour)); ok {
+		t.Error("AwayAt(now + 24h) = true, want false")
+	}
+
+	got, err = internal.SelectUser(t.Context(), internal.IndexBySlackID, slackID)
+	if err != nil {
+		t.Fatalf("SelectUser() error = %v", err)
+	}
+	if len(got.Away) != 2 {
+		t.Errorf("SelectUser() = %d away periods, want 2", len(got.Away))
+	}
+}
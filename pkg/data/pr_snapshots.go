@@ -53,6 +53,27 @@ func FindPRsByCommit(ctx workflow.Context, hash string) ([]map[string]any, error
 	return prs, nil
 }
 
+// PRCommitHash returns the hash of the latest commit in the given PR snapshot, or an
+// empty string if it's missing. Bitbucket hashes may be truncated, GitHub hashes aren't.
+func PRCommitHash(pr map[string]any) string {
+	return internal.PRCommitHash(pr)
+}
+
+// ListPRsInRepo returns the URLs of all (0 or more) the PRs in the given repository that have snapshots.
+func ListPRsInRepo(ctx workflow.Context, repoURL string) ([]string, error) {
+	if ctx == nil { // For unit testing.
+		return internal.ListPRsInRepo(context.Background(), repoURL) //workflowcheck:ignore
+	}
+
+	var prURLs []string
+	if err := executeLocalActivity(ctx, internal.ListPRsInRepo, &prURLs, repoURL); err != nil {
+		logger.From(ctx).Error("failed to list PR snapshots in repository", slog.Any("error", err), slog.String("repo_url", repoURL))
+		return nil, err
+	}
+
+	return prURLs, nil
+}
+
 func DeletePRSnapshot(ctx workflow.Context, prURL string) {
 	if ctx == nil { // For unit testing.
 		_ = internal.DeleteGenericPRFile(context.Background(), prURL+internal.PRSnapshotFileSuffix) //workflowcheck:ignore
@@ -0,0 +1,58 @@
+package data
+
+import (
+	"context"
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data/internal"
+)
+
+type (
+	Task    = internal.Task
+	PRTasks = internal.PRTasks
+)
+
+// ReadTasks returns the last-known state of a Bitbucket PR's tasks, and the ID of their checklist
+// message in Slack. If they are not available (e.g. in case of an error), it returns an empty state.
+func ReadTasks(ctx workflow.Context, prURL string) PRTasks {
+	if ctx == nil { // For unit testing.
+		tasks, err := internal.ReadTasks(context.Background(), prURL) //workflowcheck:ignore
+		if err != nil {
+			return PRTasks{}
+		}
+		return *tasks
+	}
+
+	tasks := PRTasks{}
+	if err := executeLocalActivity(ctx, internal.ReadTasks, &tasks, prURL); err != nil {
+		logger.From(ctx).Error("failed to read PR's tasks", slog.Any("error", err), slog.String("pr_url", prURL))
+		return PRTasks{}
+	}
+
+	return tasks
+}
+
+func WriteTasks(ctx workflow.Context, prURL string, tasks PRTasks) {
+	if ctx == nil { // For unit testing.
+		_ = internal.WriteTasks(context.Background(), prURL, tasks) //workflowcheck:ignore
+		return
+	}
+
+	if err := executeLocalActivity(ctx, internal.WriteTasks, nil, prURL, tasks); err != nil {
+		logger.From(ctx).Error("failed to write PR's tasks", slog.Any("error", err), slog.String("pr_url", prURL))
+	}
+}
+
+func DeleteTasks(ctx workflow.Context, prURL string) {
+	if ctx == nil { // For unit testing.
+		_ = internal.DeleteGenericPRFile(context.Background(), prURL+internal.TasksFileSuffix) //workflowcheck:ignore
+		return
+	}
+
+	if err := executeLocalActivity(ctx, internal.DeleteGenericPRFile, nil, prURL+internal.TasksFileSuffix); err != nil {
+		logger.From(ctx).Warn("failed to delete PR's tasks", slog.Any("error", err), slog.String("pr_url", prURL))
+	}
+}
@@ -176,6 +176,31 @@ func RemoveReviewerFromTurns(ctx workflow.Context, opts client.Options, prURL, e
 	return nil
 }
 
+// LoadParticipantRoles checks whether a specific user is the author of a specific PR, and whether they are tracked
+// as one of its reviewers (i.e. they didn't approve it yet). Errors are logged, and result in false values.
+func LoadParticipantRoles(ctx workflow.Context, opts client.Options, prURL, email string) (isAuthor, isReviewer bool) {
+	email = strings.ToLower(email)
+	if email == "" || email == "bot" {
+		return false, false
+	}
+
+	var roles [2]bool
+	var err error
+	if ctx == nil { // For unit testing.
+		roles, err = internal.ReadParticipantRoles(context.Background(), opts, prURL, email) //workflowcheck:ignore
+	} else {
+		err = executeLocalActivity(ctx, internal.ReadParticipantRoles, &roles, opts, prURL, email)
+	}
+
+	if err != nil {
+		logger.From(ctx).Warn("failed to read PR attention state", slog.Any("error", err),
+			slog.String("pr_url", prURL), slog.String("email", email))
+		return false, false
+	}
+
+	return roles[0], roles[1]
+}
+
 // GetActivityTime returns the last activity timestamp of a specific user in a specific PR.
 // If the user is not found or is a bot, this function returns a zero timestamp.
 func GetActivityTime(ctx workflow.Context, opts client.Options, prURL, email string) time.Time {
@@ -277,3 +302,72 @@ func IsFrozen(ctx workflow.Context, opts client.Options, prURL string) (time.Tim
 
 	return frozen.At, frozen.By
 }
+
+// SnoozePR hides a specific PR from a specific user's reminders until the given time,
+// without changing anyone's turn. Snoozes expire automatically, and a zero time cancels
+// the user's snooze. If the user is not found or is a bot, this function does nothing.
+func SnoozePR(ctx workflow.Context, opts client.Options, prURL, email string, until time.Time) error {
+	email = strings.ToLower(email)
+	if email == "" || email == "bot" {
+		return nil
+	}
+
+	if ctx == nil { // For unit testing.
+		return internal.SnoozePR(context.Background(), opts, prURL, email, until) //workflowcheck:ignore
+	}
+
+	if err := executeLocalActivity(ctx, internal.SnoozePR, nil, opts, prURL, email, until); err != nil {
+		logger.From(ctx).Error("failed to snooze PR for user", slog.Any("error", err),
+			slog.String("pr_url", prURL), slog.String("email", email))
+		return err
+	}
+
+	return nil
+}
+
+// GetSnoozeTime returns the time when a specific user's snooze of a specific PR expires.
+// If the user didn't snooze the PR, or the snooze already expired, it returns a zero timestamp.
+func GetSnoozeTime(ctx workflow.Context, opts client.Options, prURL, email string) time.Time {
+	email = strings.ToLower(email)
+	if email == "" || email == "bot" {
+		return time.Time{}
+	}
+
+	if ctx == nil { // For unit testing.
+		t, _ := internal.GetSnoozeTime(context.Background(), opts, prURL, email) //workflowcheck:ignore
+		return t
+	}
+
+	var t time.Time
+	if err := executeLocalActivity(ctx, internal.GetSnoozeTime, &t, opts, prURL, email); err != nil {
+		logger.From(ctx).Error("failed to get PR snooze timestamp", slog.Any("error", err),
+			slog.String("pr_url", prURL), slog.String("email", email))
+		return time.Time{}
+	}
+
+	return t
+}
+
+// ListSnoozedPRs returns a mapping from the URLs of the PRs that a specific user snoozed to the
+// expiry times of these snoozes. Expired snoozes are ignored. Errors are logged, and result in nil.
+func ListSnoozedPRs(ctx workflow.Context, opts client.Options, email string) map[string]time.Time {
+	email = strings.ToLower(email)
+	if email == "" || email == "bot" {
+		return nil
+	}
+
+	var prs map[string]time.Time
+	var err error
+	if ctx == nil { // For unit testing.
+		prs, err = internal.ReadSnoozedPRs(context.Background(), opts, email) //workflowcheck:ignore
+	} else {
+		err = executeLocalActivity(ctx, internal.ReadSnoozedPRs, &prs, opts, email)
+	}
+
+	if err != nil {
+		logger.From(ctx).Error("failed to list snoozed PRs", slog.Any("error", err), slog.String("email", email))
+		return nil
+	}
+
+	return prs
+}
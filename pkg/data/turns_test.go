@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.temporal.io/sdk/client"
 
@@ -629,3 +630,86 @@ func TestNudge(t *testing.T) {
 		t.Fatalf("LoadCurrentTurnEmails() = %v, want %v", got, want)
 	}
 }
+
+func TestLoadParticipantRoles(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	url := "https://github.com/owner/repo/pull/1"
+	data.InitTurns(nil, url, "author@example.com")
+	if _, _, err := data.SetReviewerTurn(nil, client.Options{}, url, "rev1@example.com", false); err != nil {
+		t.Fatalf("SetReviewerTurn() error = %v", err)
+	}
+	if _, _, err := data.SetReviewerTurn(nil, client.Options{}, url, "rev2@example.com", false); err != nil {
+		t.Fatalf("SetReviewerTurn() error = %v", err)
+	}
+	if err := data.RemoveReviewerFromTurns(nil, client.Options{}, url, "rev2@example.com", true); err != nil {
+		t.Fatalf("RemoveReviewerFromTurns() error = %v", err)
+	}
+
+	tests := []struct {
+		email        string
+		wantAuthor   bool
+		wantReviewer bool
+	}{
+		{email: "Author@Example.com", wantAuthor: true},
+		{email: "rev1@example.com", wantReviewer: true},
+		{email: "rev2@example.com"}, // Already approved.
+		{email: "other@example.com"},
+		{email: "bot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			isAuthor, isReviewer := data.LoadParticipantRoles(nil, client.Options{}, url, tt.email)
+			if isAuthor != tt.wantAuthor || isReviewer != tt.wantReviewer {
+				t.Errorf("LoadParticipantRoles() = (%v, %v), want (%v, %v)", isAuthor, isReviewer, tt.wantAuthor, tt.wantReviewer)
+			}
+		})
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	d := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", d)
+
+	url := "https://bitbucket.org/workspace/repo/pull-requests/1"
+	data.InitTurns(nil, url, "author@example.com")
+
+	email := "reviewer@example.com"
+	if got := data.GetSnoozeTime(nil, client.Options{}, url, email); !got.IsZero() {
+		t.Fatalf("GetSnoozeTime() = %v, want zero time", got)
+	}
+
+	until := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	if err := data.SnoozePR(nil, client.Options{}, url, "Reviewer@Example.com", until); err != nil {
+		t.Fatalf("SnoozePR() error = %v", err)
+	}
+	if got := data.GetSnoozeTime(nil, client.Options{}, url, email); !got.Equal(until) {
+		t.Fatalf("GetSnoozeTime() = %v, want %v", got, until)
+	}
+	want := map[string]time.Time{url: until}
+	if got := data.ListSnoozedPRs(nil, client.Options{}, email); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListSnoozedPRs() = %v, want %v", got, want)
+	}
+
+	// Expired snoozes are ignored.
+	if err := data.SnoozePR(nil, client.Options{}, url, email, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("SnoozePR() error = %v", err)
+	}
+	if got := data.GetSnoozeTime(nil, client.Options{}, url, email); !got.IsZero() {
+		t.Fatalf("GetSnoozeTime() = %v, want zero time", got)
+	}
+	if got := data.ListSnoozedPRs(nil, client.Options{}, email); len(got) > 0 {
+		t.Fatalf("ListSnoozedPRs() = %v, want empty", got)
+	}
+
+	// Snoozing doesn't change anyone's turn.
+	emails, err := data.LoadCurrentTurnEmails(nil, client.Options{}, url)
+	if err != nil {
+		t.Fatalf("LoadCurrentTurnEmails() error = %v", err)
+	}
+	if want := []string{"author@example.com"}; !reflect.DeepEqual(emails, want) {
+		t.Fatalf("LoadCurrentTurnEmails() = %v, want %v", emails, want)
+	}
+}
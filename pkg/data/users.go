@@ -14,7 +14,10 @@ import (
 	"github.com/tzrikka/revchat/pkg/data/internal"
 )
 
-type User = internal.User
+type (
+	User       = internal.User
+	AwayPeriod = internal.AwayPeriod
+)
 
 var usersCache = cache.New[User](10*time.Minute, cache.DefaultCleanupInterval)
 
@@ -80,6 +83,31 @@ func followOrUnfollowUser(ctx workflow.Context, fn followUnfollowFunc, followerS
 	}
 
 	// Now that the user is fully updated and persisted, also cache the new version.
+	cacheUser(user)
+	return true
+}
+
+// SetAwayPeriods replaces the out-of-office periods of a user, identified by their Slack ID.
+// Periods which already ended are discarded, and the rest are sorted by their start time.
+func SetAwayPeriods(ctx workflow.Context, slackID string, periods []AwayPeriod) error {
+	if ctx == nil { // For unit tests.
+		_, err := internal.SetAwayPeriods(context.Background(), slackID, periods) //workflowcheck:ignore
+		return err
+	}
+
+	var user User
+	if err := executeLocalActivity(ctx, internal.SetAwayPeriods, &user, slackID, periods); err != nil {
+		logger.From(ctx).Error("failed to set user's away periods", slog.Any("error", err),
+			slog.String("slack_id", slackID), slog.Int("periods", len(periods)))
+		return err
+	}
+
+	// Now that the user is fully updated and persisted, also cache the new version.
+	cacheUser(user)
+	return nil
+}
+
+func cacheUser(user User) {
 	if user.Email != "" && user.Email != "bot" {
 		usersCache.Set(user.Email, user, cache.DefaultExpiration)
 	}
@@ -92,8 +120,6 @@ func followOrUnfollowUser(ctx workflow.Context, fn followUnfollowFunc, followerS
 	if user.SlackID != "" {
 		usersCache.Set(user.SlackID, user, cache.DefaultExpiration)
 	}
-
-	return true
 }
 
 func RemoveFollower(ctx workflow.Context, followerSlackID string) {
@@ -23,12 +23,12 @@ type CodeOwners struct {
 
 // CountOwnedFiles counts how many of the given file paths are owned by the given
 // user, according to the "CODEOWNERS" file in the given branch (a PR's destination).
-func CountOwnedFiles(ctx workflow.Context, workspace, repo, branch, commit string, fullNames, paths []string) int {
+func CountOwnedFiles(ctx workflow.Context, src SourceFetcher, workspace, repo, branch, commit string, fullNames, paths []string) int {
 	if len(fullNames) == 0 || len(paths) == 0 {
 		return 0
 	}
 
-	c := parseCodeOwnersFile(ctx, getBitbucketSourceFile(ctx, workspace, repo, branch, commit, "CODEOWNERS"), true)
+	c, _ := src.CodeOwners(ctx, workspace, repo, branch, commit, true) // The error is already logged.
 	if c == nil {
 		return 0
 	}
@@ -64,35 +64,45 @@ func CountOwnedFiles(ctx workflow.Context, workspace, repo, branch, commit strin
 
 // GotAllRequiredApprovals checks whether all required approvals are present for the given
 // file paths, according to the "CODEOWNERS" file in the given branch (a PR's destination).
-func GotAllRequiredApprovals(ctx workflow.Context, workspace, repo, branch, commit string, paths, approvers []string) bool {
+// This is Bitbucket's rule: each file requires approvals from all of its code owners.
+func GotAllRequiredApprovals(ctx workflow.Context, src SourceFetcher, workspace, repo, branch, commit string, paths, approvers []string) bool {
 	if len(paths) == 0 {
 		return false
 	}
 
-	c := parseCodeOwnersFile(ctx, getBitbucketSourceFile(ctx, workspace, repo, branch, commit, "CODEOWNERS"), true)
+	c, _ := src.CodeOwners(ctx, workspace, repo, branch, commit, true) // The error is already logged.
 	if c == nil {
 		return false
 	}
 
-	for _, p := range paths {
-		owners, err := c.getOwners(p)
-		if err != nil {
-			logger.From(ctx).Error("failed to check CODEOWNERS path pattern",
-				slog.Any("error", err), slog.String("file_path", p))
-			return false
-		}
-		if !c.allApproved(ctx, approvers, owners, true) {
-			return false
-		}
+	return c.pathsApproved(ctx, paths, approvers, true)
+}
+
+// GotAnyOwnerApprovals is similar to [GotAllRequiredApprovals], but it implements GitHub's rule instead:
+// each file requires an approval from at least one of its code owners, and files without owners don't.
+func GotAnyOwnerApprovals(ctx workflow.Context, src SourceFetcher, workspace, repo, branch, commit string, paths, approvers []string) bool {
+	if len(paths) == 0 {
+		return false
 	}
 
-	return true
+	c, _ := src.CodeOwners(ctx, workspace, repo, branch, commit, true) // The error is already logged.
+	if c == nil {
+		return false
+	}
+
+	return c.pathsApproved(ctx, paths, approvers, false)
 }
 
 // OwnersPerPath retrieves the list of code owners for each of the given file paths,
 // according to the "CODEOWNERS" file in the given branch (a PR's destination).
-func OwnersPerPath(ctx workflow.Context, workspace, repo, branch, commit string, paths []string, flatten bool) (owners, groups map[string][]string) {
-	c := parseCodeOwnersFile(ctx, getBitbucketSourceFile(ctx, workspace, repo, branch, commit, "CODEOWNERS"), flatten)
+func OwnersPerPath(
+	ctx workflow.Context,
+	src SourceFetcher,
+	workspace, repo, branch, commit string,
+	paths []string,
+	flatten bool,
+) (owners, groups map[string][]string) {
+	c, _ := src.CodeOwners(ctx, workspace, repo, branch, commit, flatten) // The error is already logged.
 	if c == nil {
 		return nil, nil
 	}
@@ -249,6 +259,27 @@ func (c *CodeOwners) expandMember(ctx workflow.Context, name string) ([]string,
 	return expanded, len(expanded)
 }
 
+// pathsApproved checks the approvals of the code owners of each of the given file paths:
+// approvals of all of them (Bitbucket's rule), or of at least one of them (GitHub's rule).
+func (c *CodeOwners) pathsApproved(ctx workflow.Context, paths, approvers []string, needAll bool) bool {
+	for _, p := range paths {
+		owners, err := c.getOwners(p)
+		if err != nil {
+			logger.From(ctx).Error("failed to check CODEOWNERS path pattern",
+				slog.Any("error", err), slog.String("file_path", p))
+			return false
+		}
+		if len(owners) == 0 && !needAll {
+			continue
+		}
+		if !c.allApproved(ctx, approvers, owners, needAll) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (c *CodeOwners) allApproved(ctx workflow.Context, approvers, owners []string, needAll bool) bool {
 	if specialOwners, found := c.Groups["@FallbackOwners"]; needAll && found {
 		if c.allApproved(ctx, approvers, specialOwners, false) {
@@ -291,6 +291,79 @@ func TestCodeOwnersAllApproved(t *testing.T) {
 	}
 }
 
+func TestCodeOwnersPathsApproved(t *testing.T) {
+	co := &CodeOwners{
+		PathList: []string{"/docs/**/*", "/generated/**/*", "**/*"},
+		Groups:   map[string][]string{},
+		Paths: map[string][]string{
+			"/docs/**/*":      {"user1", "user2"},
+			"/generated/**/*": {},
+			"**/*":            {"user3", "org/ghosts"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		paths     []string
+		approvers []string
+		needAll   bool
+		want      bool
+	}{
+		{
+			name:      "all_owners_approved",
+			paths:     []string{"docs/a.md", "generated/b.go"},
+			approvers: []string{"user1", "user2"},
+			needAll:   true,
+			want:      true,
+		},
+		{
+			name:      "one_owner_approved_need_all",
+			paths:     []string{"docs/a.md", "generated/b.go"},
+			approvers: []string{"user1"},
+			needAll:   true,
+			want:      false,
+		},
+		{
+			name:      "one_owner_approved_need_any",
+			paths:     []string{"docs/a.md", "generated/b.go"},
+			approvers: []string{"user1"},
+			want:      true,
+		},
+		{
+			name:      "unknown_team_need_all",
+			paths:     []string{"main.go"},
+			approvers: []string{"user3"},
+			needAll:   true,
+			want:      false,
+		},
+		{
+			name:      "unknown_team_need_any",
+			paths:     []string{"main.go"},
+			approvers: []string{"user3"},
+			want:      true,
+		},
+		{
+			name:      "one_file_not_approved",
+			paths:     []string{"docs/a.md", "main.go"},
+			approvers: []string{"user1"},
+			want:      false,
+		},
+		{
+			name:  "no_owners_need_any",
+			paths: []string{"generated/b.go"},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := co.pathsApproved(nil, tt.paths, tt.approvers, tt.needAll); got != tt.want {
+				t.Errorf("pathsApproved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCodeOwnersGetOwners(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -3,6 +3,8 @@
 //
 // This functionality is based on comparing the PR's diffstat against the
 // "CODEOWNERS" and "highrisk.txt" files in the PR's destination branch.
+// In GitHub repositories, the "CODEOWNERS" file may be located in the
+// ".github" directory, the root directory, or the "docs" directory.
 //
 // This is used in Slack: daily reminders, the status Slack command,
 // and ready-to-merge notifications.
@@ -0,0 +1,134 @@
+package files
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/cache"
+	"github.com/tzrikka/revchat/pkg/data"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
+)
+
+// teamCache is a cache for GitHub team members, for the same reasons as [fileCache].
+var teamCache = cache.New[[]string](10*time.Minute, cache.DefaultCleanupInterval)
+
+// teamMembersFunc returns the real names (or GitHub logins, if unknown) of the members
+// of a GitHub team ("org/team"). It is a parameter only to facilitate unit testing.
+type teamMembersFunc func(ctx workflow.Context, team string) []string
+
+// parseGitHubCodeOwnersFile parses a GitHub "CODEOWNERS" file into the same structure as Bitbucket's files:
+// individual users (specified as "@login" or email addresses) are identified by their real names (if they
+// are known to RevChat), and teams ("@org/team") are groups whose members are retrieved from GitHub. See:
+// https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners#codeowners-syntax
+func parseGitHubCodeOwnersFile(ctx workflow.Context, fileContent string, flatten bool, teamMembers teamMembersFunc) *CodeOwners {
+	if fileContent == "" {
+		return nil
+	}
+
+	c := &CodeOwners{
+		Groups: map[string][]string{},
+		Paths:  map[string][]string{},
+		Users:  map[string]bool{},
+	}
+
+	for line := range strings.Lines(fileContent) {
+		fields := strings.Fields(stripGitHubComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Unlike Bitbucket, a path pattern without owners is valid: it means that matching files
+		// have no owners. Also, duplicate patterns override each other instead of being merged.
+		pathPattern := normalizePattern(fields[0])
+		members := make([]string, 0, len(fields)-1)
+		for _, owner := range fields[1:] {
+			members = append(members, c.githubOwner(ctx, owner, teamMembers))
+		}
+
+		c.PathList = append(c.PathList, pathPattern)
+		c.Paths[pathPattern] = members
+	}
+
+	slices.Reverse(c.PathList) // CODEOWNERS semantics: last match wins.
+	if flatten {
+		c.expandGroups(ctx)
+	}
+	return c
+}
+
+// stripGitHubComment removes a comment from a line in a GitHub "CODEOWNERS" file. Escaped
+// hash signs ("\#", e.g. in paths of files which start with "#") don't start comments.
+func stripGitHubComment(line string) string {
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '#':
+			return sb.String()
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '#':
+			sb.WriteByte('#')
+			i++
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	return sb.String()
+}
+
+// githubOwner converts a GitHub code owner into the same naming scheme as Bitbucket code owners. If the owner
+// is a team, this function also stores its members as a group, so it can be expanded or explained later.
+func (c *CodeOwners) githubOwner(ctx workflow.Context, owner string, teamMembers teamMembersFunc) string {
+	name, isRef := strings.CutPrefix(owner, "@")
+	switch {
+	case isRef && strings.Contains(name, "/"): // Team.
+		if _, found := c.Groups[owner]; found {
+			return owner
+		}
+		members := teamMembers(ctx, name)
+		if len(members) == 0 {
+			return name // Can't expand the team, so treat it as an opaque individual owner (without the group prefix).
+		}
+		c.Groups[owner] = members
+		return owner
+
+	case isRef: // User.
+		return githubUserName(ctx, name)
+
+	default: // Email address.
+		if user := data.SelectUserByEmail(ctx, owner); user.RealName != "" {
+			return user.RealName
+		}
+		return owner
+	}
+}
+
+// githubTeamMembers is the default [teamMembersFunc], which retrieves team members from GitHub.
+func githubTeamMembers(ctx workflow.Context, team string) []string {
+	if members, ok := teamCache.Get(team); ok {
+		return members
+	}
+
+	org, slug, _ := strings.Cut(team, "/")
+	users, err := github.ListTeamMembers(ctx, org, slug)
+	if err != nil {
+		return nil
+	}
+
+	members := make([]string, 0, len(users))
+	for _, u := range users {
+		members = append(members, githubUserName(ctx, u.Login))
+	}
+
+	teamCache.Set(team, members, cache.DefaultExpiration)
+	return members
+}
+
+// githubUserName returns the real name of a GitHub user, if they are known to RevChat, or their login otherwise.
+func githubUserName(ctx workflow.Context, login string) string {
+	if user := data.SelectUserByGitHubID(ctx, login); user.RealName != "" {
+		return user.RealName
+	}
+	return login
+}
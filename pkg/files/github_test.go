@@ -0,0 +1,134 @@
+package files
+
+import (
+	"reflect"
+	"testing"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+func TestParseGitHubCodeOwnersFile(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if err := data.UpsertUser(nil, "alice@example.com", "Alice A", "", "alice", "", ""); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+
+	teamMembers := func(_ workflow.Context, team string) []string {
+		if team == "org/devs" {
+			return []string{"Alice A", "bob"}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		flatten bool
+		want    *CodeOwners
+	}{
+		{
+			name: "empty_file",
+		},
+		{
+			name: "comment",
+			file: `# Ignore me`,
+			want: &CodeOwners{
+				Groups: map[string][]string{},
+				Paths:  map[string][]string{},
+				Users:  map[string]bool{},
+			},
+		},
+		{
+			name: "users",
+			file: `
+			*.go @alice @bob # Trailing comment
+			/docs/ alice@example.com carol@example.com
+			`,
+			want: &CodeOwners{
+				PathList: []string{"/docs/**/*", "**/*.go"},
+				Groups:   map[string][]string{},
+				Paths: map[string][]string{
+					"**/*.go":    {"Alice A", "bob"},
+					"/docs/**/*": {"Alice A", "carol@example.com"},
+				},
+				Users: map[string]bool{},
+			},
+		},
+		{
+			name: "teams",
+			file: `
+			* @org/devs
+			/vendor/ @org/ghosts
+			`,
+			want: &CodeOwners{
+				PathList: []string{"/vendor/**/*", "**/*"},
+				Groups:   map[string][]string{"@org/devs": {"Alice A", "bob"}},
+				Paths: map[string][]string{
+					"**/*":         {"@org/devs"},
+					"/vendor/**/*": {"org/ghosts"},
+				},
+				Users: map[string]bool{},
+			},
+		},
+		{
+			name:    "flattened_teams",
+			file:    `* @org/devs @carol`,
+			flatten: true,
+			want: &CodeOwners{
+				PathList: []string{"**/*"},
+				Groups:   map[string][]string{"@org/devs": {"Alice A", "bob"}},
+				Paths:    map[string][]string{"**/*": {"Alice A", "bob", "carol"}},
+				Users:    map[string]bool{"Alice A": true, "bob": true, "carol": true},
+			},
+		},
+		{
+			name:    "flattened_unknown_team",
+			file:    `* @org/devs @org/ghosts`,
+			flatten: true,
+			want: &CodeOwners{
+				PathList: []string{"**/*"},
+				Groups:   map[string][]string{"@org/devs": {"Alice A", "bob"}},
+				Paths:    map[string][]string{"**/*": {"Alice A", "bob", "org/ghosts"}},
+				Users:    map[string]bool{"Alice A": true, "bob": true, "org/ghosts": true},
+			},
+		},
+		{
+			name: "escaped_hash",
+			file: `\#notes.md @alice # The path starts with "#"`,
+			want: &CodeOwners{
+				PathList: []string{"**/#notes.md"},
+				Groups:   map[string][]string{},
+				Paths:    map[string][]string{"**/#notes.md": {"Alice A"}},
+				Users:    map[string]bool{},
+			},
+		},
+		{
+			name: "no_owners_and_overrides",
+			file: `
+			* @alice
+			/generated/
+			* @bob
+			`,
+			want: &CodeOwners{
+				PathList: []string{"**/*", "/generated/**/*", "**/*"},
+				Groups:   map[string][]string{},
+				Paths: map[string][]string{
+					"**/*":            {"bob"},
+					"/generated/**/*": {},
+				},
+				Users: map[string]bool{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGitHubCodeOwnersFile(nil, tt.file, tt.flatten, teamMembers)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGitHubCodeOwnersFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,8 +8,9 @@ import (
 
 // CountHighRiskFiles counts how many of the given file paths are considered high risk,
 // according to the "highrisk.txt" file in the given branch (a PR's destination).
-func CountHighRiskFiles(ctx workflow.Context, workspace, repo, branch, commit string, paths []string) int {
-	hr := parseHighRiskFile(getBitbucketSourceFile(ctx, workspace, repo, branch, commit, "highrisk.txt"))
+func CountHighRiskFiles(ctx workflow.Context, src SourceFetcher, workspace, repo, branch, commit string, paths []string) int {
+	file, _ := src.GetSourceFile(ctx, workspace, repo, branch, commit, "highrisk.txt") // The error is already logged.
+	hr := parseHighRiskFile(file)
 
 	count := 0
 	for _, p := range paths {
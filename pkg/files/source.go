@@ -2,12 +2,15 @@ package files
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/cache"
-	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
 )
 
 // fileCache is a cache for "CODEOWNERS" and "highrisk.txt" files.
@@ -16,17 +19,115 @@ import (
 // but on the other hand we don't want this data to become stale.
 var fileCache = cache.New[string](10*time.Minute, cache.DefaultCleanupInterval)
 
-func getBitbucketSourceFile(ctx workflow.Context, workspace, repo, branch, commit, path string) string {
+// SourceFetcher retrieves and interprets files in a PR's destination
+// branch, in a way that is specific to the PR's source-control platform.
+type SourceFetcher interface {
+	// GetSourceFile returns the content of a file, or an empty string if it doesn't exist.
+	// Failures to read it are reported as errors, and aren't cached (unlike missing files).
+	GetSourceFile(ctx workflow.Context, owner, repo, branch, commit, path string) (string, error)
+
+	// CodeOwners returns the parsed "CODEOWNERS" file, or nil if it doesn't exist.
+	// Failures to read it are reported as errors, to distinguish them from missing files.
+	CodeOwners(ctx workflow.Context, owner, repo, branch, commit string, flatten bool) (*CodeOwners, error)
+}
+
+// NewSourceFetcher returns the [SourceFetcher] which is suitable for the given PR URL.
+func NewSourceFetcher(prURL string) SourceFetcher {
+	if strings.HasPrefix(prURL, "https://bitbucket.org/") {
+		return bitbucketSource{}
+	}
+	if u := datacenter.ParseURL(prURL); u != nil {
+		return dataCenterSource{baseURL: u.BaseURL}
+	}
+	return githubSource{}
+}
+
+type bitbucketSource struct{}
+
+func (bitbucketSource) GetSourceFile(ctx workflow.Context, workspace, repo, branch, commit, path string) (string, error) {
 	key := fmt.Sprintf("%s:%s:%s:%s", workspace, repo, branch, path)
 	if file, ok := fileCache.Get(key); ok {
-		return file
+		return file, nil
 	}
 
-	file, err := activities.GetSourceFile(ctx, workspace, repo, branch, commit, path)
+	file, err := bitbucket.GetSourceFile(ctx, workspace, repo, branch, commit, path)
 	if err != nil {
-		return ""
+		return "", err
 	}
 
 	fileCache.Set(key, file, cache.DefaultExpiration)
-	return file
+	return file, nil
+}
+
+func (b bitbucketSource) CodeOwners(ctx workflow.Context, workspace, repo, branch, commit string, flatten bool) (*CodeOwners, error) {
+	file, err := b.GetSourceFile(ctx, workspace, repo, branch, commit, "CODEOWNERS")
+	if err != nil {
+		return nil, err
+	}
+	return parseCodeOwnersFile(ctx, file, flatten), nil
+}
+
+// dataCenterSource is similar to [bitbucketSource], but for self-hosted Bitbucket Data Center instances.
+type dataCenterSource struct {
+	baseURL string
+}
+
+func (d dataCenterSource) GetSourceFile(ctx workflow.Context, projectKey, repo, branch, commit, path string) (string, error) {
+	key := fmt.Sprintf("%s:%s:%s:%s:%s", d.baseURL, projectKey, repo, branch, path)
+	if file, ok := fileCache.Get(key); ok {
+		return file, nil
+	}
+
+	file, err := bitbucket.GetDataCenterSourceFile(ctx, d.baseURL, projectKey, repo, branch, commit, path)
+	if err != nil {
+		return "", err
+	}
+
+	fileCache.Set(key, file, cache.DefaultExpiration)
+	return file, nil
+}
+
+func (d dataCenterSource) CodeOwners(ctx workflow.Context, projectKey, repo, branch, commit string, flatten bool) (*CodeOwners, error) {
+	file, err := d.GetSourceFile(ctx, projectKey, repo, branch, commit, "CODEOWNERS")
+	if err != nil {
+		return nil, err
+	}
+	return parseCodeOwnersFile(ctx, file, flatten), nil
+}
+
+type githubSource struct{}
+
+// githubCodeOwnersPaths are the locations where GitHub looks for a "CODEOWNERS" file, in order of precedence:
+// https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners#codeowners-file-location
+var githubCodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+func (githubSource) GetSourceFile(ctx workflow.Context, owner, repo, branch, commit, path string) (string, error) {
+	key := fmt.Sprintf("github:%s:%s:%s:%s", owner, repo, branch, path)
+	if file, ok := fileCache.Get(key); ok {
+		return file, nil
+	}
+
+	// Missing files are common here (see [githubCodeOwnersPaths]), so we cache
+	// them too, to avoid repeating useless API calls. But we don't cache errors,
+	// so transient failures don't hide existing files for a long time.
+	file, err := github.GetSourceFile(ctx, owner, repo, branch, commit, path)
+	if err != nil {
+		return "", err
+	}
+
+	fileCache.Set(key, file, cache.DefaultExpiration)
+	return file, nil
+}
+
+func (g githubSource) CodeOwners(ctx workflow.Context, owner, repo, branch, commit string, flatten bool) (*CodeOwners, error) {
+	for _, path := range githubCodeOwnersPaths {
+		file, err := g.GetSourceFile(ctx, owner, repo, branch, commit, path)
+		if err != nil {
+			return nil, err
+		}
+		if file != "" {
+			return parseGitHubCodeOwnersFile(ctx, file, flatten, githubTeamMembers), nil
+		}
+	}
+	return nil, nil
 }
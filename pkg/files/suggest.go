@@ -0,0 +1,70 @@
+package files
+
+import (
+	"slices"
+)
+
+// MinimalOwnersCover returns a small set of code owners who, together, own all the given
+// file paths (based on the output of [OwnersPerPath] with flattened groups), and the paths
+// which can't be covered because all of their owners are excluded. Paths without owners
+// are ignored, because there's no one to cover them anyway.
+//
+// Finding a minimal set cover is NP-hard, so this function uses the standard greedy
+// approximation: it repeatedly picks the owner who covers the most uncovered paths.
+// Ties are broken in favor of preferred owners (e.g. existing reviewers), and then
+// alphabetically, so the result is deterministic.
+func MinimalOwnersCover(paths []string, owners map[string][]string, preferred []string, excluded map[string]bool) (cover, uncovered []string) {
+	uncoveredPaths := map[string]bool{}
+	candidates := map[string][]string{} // Owner --> owned paths.
+	for _, p := range paths {
+		if len(owners[p]) == 0 {
+			continue
+		}
+		uncoveredPaths[p] = true
+		for _, o := range owners[p] {
+			if !excluded[o] {
+				candidates[o] = append(candidates[o], p)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	} //workflowcheck:ignore
+	slices.Sort(names)
+
+	for len(uncoveredPaths) > 0 {
+		best, bestCount := "", 0
+		for _, name := range names {
+			count := 0
+			for _, p := range candidates[name] {
+				if uncoveredPaths[p] {
+					count++
+				}
+			}
+			if count > bestCount || (count == bestCount && count > 0 && slices.Contains(preferred, name) && !slices.Contains(preferred, best)) {
+				best, bestCount = name, count
+			}
+		}
+
+		if bestCount == 0 {
+			break // All the remaining paths are owned only by excluded owners.
+		}
+
+		cover = append(cover, best)
+		for _, p := range candidates[best] {
+			delete(uncoveredPaths, p)
+		}
+	}
+
+	for _, p := range paths {
+		if uncoveredPaths[p] {
+			uncovered = append(uncovered, p)
+			delete(uncoveredPaths, p) // Ignore duplicate paths.
+		}
+	}
+
+	slices.Sort(cover)
+	return cover, uncovered
+}
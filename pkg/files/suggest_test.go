@@ -0,0 +1,74 @@
+package files
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMinimalOwnersCover(t *testing.T) {
+	owners := map[string][]string{
+		"a.go":   {"Alice", "Bob"},
+		"b.go":   {"Bob", "Carol"},
+		"c.go":   {"Carol"},
+		"d.go":   {"Dave"},
+		"e.txt":  {},
+		"f.yaml": {"Alice", "Carol"},
+	}
+
+	tests := []struct {
+		name          string
+		paths         []string
+		preferred     []string
+		excluded      map[string]bool
+		wantCover     []string
+		wantUncovered []string
+	}{
+		{
+			name: "no_paths",
+		},
+		{
+			name:  "no_owners",
+			paths: []string{"e.txt", "unknown.go"},
+		},
+		{
+			name:      "single_owner",
+			paths:     []string{"a.go", "b.go"},
+			wantCover: []string{"Bob"},
+		},
+		{
+			name:      "greedy",
+			paths:     []string{"a.go", "b.go", "c.go", "d.go", "e.txt", "f.yaml"},
+			wantCover: []string{"Alice", "Carol", "Dave"},
+		},
+		{
+			name:      "alphabetical_tie_break",
+			paths:     []string{"f.yaml"},
+			wantCover: []string{"Alice"},
+		},
+		{
+			name:      "preferred_tie_break",
+			paths:     []string{"f.yaml"},
+			preferred: []string{"Carol"},
+			wantCover: []string{"Carol"},
+		},
+		{
+			name:          "excluded",
+			paths:         []string{"a.go", "c.go", "d.go"},
+			excluded:      map[string]bool{"Alice": true, "Dave": true},
+			wantCover:     []string{"Bob", "Carol"},
+			wantUncovered: []string{"d.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCover, gotUncovered := MinimalOwnersCover(tt.paths, owners, tt.preferred, tt.excluded)
+			if !reflect.DeepEqual(gotCover, tt.wantCover) {
+				t.Errorf("MinimalOwnersCover() cover = %v, want %v", gotCover, tt.wantCover)
+			}
+			if !reflect.DeepEqual(gotUncovered, tt.wantUncovered) {
+				t.Errorf("MinimalOwnersCover() uncovered = %v, want %v", gotUncovered, tt.wantUncovered)
+			}
+		})
+	}
+}
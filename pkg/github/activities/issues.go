@@ -25,3 +25,33 @@ func CreateIssueComment(ctx workflow.Context, thrippyID, owner, repo string, iss
 
 	return resp.HTMLURL, nil
 }
+
+func UpdateIssueComment(ctx workflow.Context, thrippyID, owner, repo string, commentID int, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	if _, err := github.IssuesCommentsUpdate(ctx, thrippyID, owner, repo, commentID, msg); err != nil {
+		logger.From(ctx).Error("failed to update GitHub issue comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
+
+func DeleteIssueComment(ctx workflow.Context, thrippyID, owner, repo string, commentID int) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	if err := github.IssuesCommentsDelete(ctx, thrippyID, owner, repo, commentID); err != nil {
+		logger.From(ctx).Error("failed to delete GitHub issue comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
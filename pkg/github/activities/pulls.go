@@ -5,10 +5,12 @@ import (
 	"log/slog"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
 	"github.com/tzrikka/timpani-api/pkg/github"
 )
 
@@ -63,6 +65,57 @@ func CreateReviewCommentReply(ctx workflow.Context, thrippyID, owner, repo strin
 	return resp.HTMLURL, nil
 }
 
+func UpdateReviewComment(ctx workflow.Context, thrippyID, owner, repo string, commentID int, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	if _, err := github.PullRequestsCommentsUpdate(ctx, thrippyID, owner, repo, commentID, msg); err != nil {
+		logger.From(ctx).Error("failed to update GitHub review comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
+
+func DeleteReviewComment(ctx workflow.Context, thrippyID, owner, repo string, commentID int) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	if err := github.PullRequestsCommentsDelete(ctx, thrippyID, owner, repo, commentID); err != nil {
+		logger.From(ctx).Error("failed to delete GitHub review comment", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("comment_id", commentID))
+		return err
+	}
+
+	return nil
+}
+
+func UpdateReview(ctx workflow.Context, thrippyID, owner, repo string, prID, reviewID int, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	_, err := github.PullRequestsReviewsUpdate(ctx, github.PullRequestsReviewsUpdateRequest{
+		PullRequestsReviewsRequest: github.PullRequestsReviewsRequest{
+			ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID, ReviewID: reviewID,
+		},
+		Body: msg,
+	})
+	if err != nil {
+		logger.From(ctx).Error("failed to update GitHub PR review", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner), slog.String("repo", repo),
+			slog.Int("pr_id", prID), slog.Int("review_id", reviewID))
+		return err
+	}
+
+	return nil
+}
+
 var commentURLPattern = regexp.MustCompile(`^https://[^/]+/([^/]+)/([^/]+)/pull/(\d+)([^\s\d]+(\d+))?`)
 
 // GetPullRequest allows the Thrippy link ID to be empty, even though it is encouraged to specify it.
@@ -106,3 +159,167 @@ func ListPullRequestFiles(ctx workflow.Context, thrippyID, owner, repo string, p
 
 	return files, nil
 }
+
+func ApprovePullRequest(ctx workflow.Context, thrippyID, owner, repo string, prID int) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	_, err := github.PullRequestsReviewsCreate(ctx, github.PullRequestsReviewsCreateRequest{PullRequestsRequest: pr, Event: "APPROVE"})
+	if err != nil {
+		logger.From(ctx).Error("failed to approve GitHub PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return err
+	}
+
+	return nil
+}
+
+func DismissReview(ctx workflow.Context, thrippyID, owner, repo string, prID, reviewID int, msg string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	_, err := github.PullRequestsReviewsDismiss(ctx, github.PullRequestsReviewsDismissRequest{
+		PullRequestsReviewsRequest: github.PullRequestsReviewsRequest{
+			ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID, ReviewID: reviewID,
+		},
+		Message: msg,
+		Event:   "DISMISS",
+	})
+	if err != nil {
+		logger.From(ctx).Error("failed to dismiss GitHub PR review", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner), slog.String("repo", repo),
+			slog.Int("pr_id", prID), slog.Int("review_id", reviewID))
+		return err
+	}
+
+	return nil
+}
+
+// ListPullRequestReviews returns all the reviews of a PR, in chronological order:
+// https://docs.github.com/en/rest/pulls/reviews?apiVersion=2022-11-28#list-reviews-for-a-pull-request
+func ListPullRequestReviews(ctx workflow.Context, thrippyID, owner, repo string, prID int) ([]github.Review, error) {
+	if thrippyID == "" {
+		return nil, errors.New("missing user authentication credentials")
+	}
+
+	req := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	reviews, err := timpani.ExecuteActivity[[]github.Review](ctx, "github.pulls.reviews.list", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list GitHub PR's reviews", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return nil, err
+	}
+
+	return *reviews, nil
+}
+
+// LatestReviews returns the latest approval, change request or dismissal of each reviewer (by login),
+// based on a PR's list of reviews. Comments don't override the previous state of a reviewer.
+func LatestReviews(reviews []github.Review) map[string]github.Review {
+	latest := map[string]github.Review{}
+	for _, r := range reviews {
+		if s := strings.ToLower(r.State); s != "commented" && s != "pending" {
+			latest[r.User.Login] = r
+		}
+	}
+	return latest
+}
+
+// pullRequestsReviewersRequest is based on:
+//   - https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#request-reviewers-for-a-pull-request
+//   - https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#remove-requested-reviewers-from-a-pull-request
+type pullRequestsReviewersRequest struct {
+	github.PullRequestsRequest
+
+	Reviewers     []string `json:"reviewers"`
+	TeamReviewers []string `json:"team_reviewers,omitempty"`
+}
+
+// RemoveRequestedReviewers removes review requests from a PR, for the given GitHub users:
+// https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#remove-requested-reviewers-from-a-pull-request
+func RemoveRequestedReviewers(ctx workflow.Context, thrippyID, owner, repo string, prID int, logins []string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	req := pullRequestsReviewersRequest{PullRequestsRequest: pr, Reviewers: logins}
+	if _, err := timpani.ExecuteActivity[github.PullRequest](ctx, "github.pulls.removeRequestedReviewers", req); err != nil {
+		logger.From(ctx).Error("failed to remove GitHub PR's requested reviewers", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return err
+	}
+
+	return nil
+}
+
+// MergePullRequest merges a PR using the given method ("merge", "squash", "rebase",
+// or empty for the repository's default), on behalf of the given user:
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#merge-a-pull-request
+func MergePullRequest(ctx workflow.Context, thrippyID, owner, repo string, prID int, method string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	resp, err := github.PullRequestsMerge(ctx, github.PullRequestsMergeRequest{PullRequestsRequest: pr, MergeMethod: method})
+	if err != nil {
+		logger.From(ctx).Error("failed to merge GitHub PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return err
+	}
+
+	if !resp.Merged {
+		logger.From(ctx).Error("GitHub PR not merged", slog.String("message", resp.Message),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return errors.New("GitHub PR not merged: " + resp.Message)
+	}
+
+	return nil
+}
+
+// ClosePullRequest closes a PR without merging it, on behalf of the given user:
+// https://docs.github.com/en/rest/pulls/pulls?apiVersion=2022-11-28#update-a-pull-request
+func ClosePullRequest(ctx workflow.Context, thrippyID, owner, repo string, prID int) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	req := github.PullRequestsUpdateRequest{PullRequestsRequest: pr, State: "closed"}
+	if _, err := timpani.ExecuteActivity[github.PullRequest](ctx, github.PullRequestsUpdateActivityName, req); err != nil {
+		logger.From(ctx).Error("failed to close GitHub PR", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return err
+	}
+
+	return nil
+}
+
+// RequestReviewers requests reviews from the given GitHub users:
+// https://docs.github.com/en/rest/pulls/review-requests?apiVersion=2022-11-28#request-reviewers-for-a-pull-request
+func RequestReviewers(ctx workflow.Context, thrippyID, owner, repo string, prID int, logins []string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	pr := github.PullRequestsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, PullNumber: prID}
+	req := pullRequestsReviewersRequest{PullRequestsRequest: pr, Reviewers: logins}
+	if _, err := timpani.ExecuteActivity[github.PullRequest](ctx, "github.pulls.requestReviewers", req); err != nil {
+		logger.From(ctx).Error("failed to request GitHub PR reviewers", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.Int("pr_id", prID))
+		return err
+	}
+
+	return nil
+}
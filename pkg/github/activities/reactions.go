@@ -0,0 +1,112 @@
+package activities
+
+import (
+	"errors"
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+)
+
+// reactionsRequest is based on:
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#create-reaction-for-an-issue-comment
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#create-reaction-for-a-pull-request-review-comment
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#list-reactions-for-an-issue-comment
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#list-reactions-for-a-pull-request-review-comment
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#delete-an-issue-comment-reaction
+//   - https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#delete-a-pull-request-comment-reaction
+type reactionsRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Owner      string `json:"owner"`
+	Repo       string `json:"repo"`
+	CommentID  int    `json:"comment_id"`
+	Content    string `json:"content,omitempty"`
+	ReactionID int    `json:"reaction_id,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+}
+
+// reaction is based on:
+// https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#list-reactions-for-an-issue-comment
+type reaction struct {
+	ID      int    `json:"id"`
+	Content string `json:"content"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// CreateIssueCommentReaction adds a reaction (e.g. "+1", "heart", "rocket") to a PR's issue comment, on behalf of the given user.
+func CreateIssueCommentReaction(ctx workflow.Context, thrippyID, owner, repo string, commentID int, content string) error {
+	return createReaction(ctx, "github.reactions.createForIssueComment", thrippyID, owner, repo, commentID, content)
+}
+
+// CreateReviewCommentReaction adds a reaction (e.g. "+1", "heart", "rocket") to a PR's review comment, on behalf of the given user.
+func CreateReviewCommentReaction(ctx workflow.Context, thrippyID, owner, repo string, commentID int, content string) error {
+	return createReaction(ctx, "github.reactions.createForPullRequestReviewComment", thrippyID, owner, repo, commentID, content)
+}
+
+// DeleteIssueCommentReaction removes the given user's reaction from a PR's issue comment, if it exists.
+func DeleteIssueCommentReaction(ctx workflow.Context, thrippyID, owner, repo string, commentID int, content, login string) error {
+	return deleteReaction(ctx, "github.reactions.listForIssueComment", "github.reactions.deleteForIssueComment",
+		thrippyID, owner, repo, commentID, content, login)
+}
+
+// DeleteReviewCommentReaction removes the given user's reaction from a PR's review comment, if it exists.
+func DeleteReviewCommentReaction(ctx workflow.Context, thrippyID, owner, repo string, commentID int, content, login string) error {
+	return deleteReaction(ctx, "github.reactions.listForPullRequestReviewComment", "github.reactions.deleteForPullRequestComment",
+		thrippyID, owner, repo, commentID, content, login)
+}
+
+func createReaction(ctx workflow.Context, activity, thrippyID, owner, repo string, commentID int, content string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req := reactionsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, CommentID: commentID, Content: content}
+	if _, err := timpani.ExecuteActivity[reaction](ctx, activity, req); err != nil {
+		logger.From(ctx).Error("failed to create GitHub reaction", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner), slog.String("repo", repo),
+			slog.Int("comment_id", commentID), slog.String("content", content))
+		return err
+	}
+
+	return nil
+}
+
+// deleteReaction finds the ID of the user's reaction, because GitHub doesn't support deleting reactions by their content.
+func deleteReaction(ctx workflow.Context, listActivity, deleteActivity, thrippyID, owner, repo string, commentID int, content, login string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req := reactionsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, CommentID: commentID, Content: content, PerPage: 100}
+	reactions, err := timpani.ExecuteActivity[[]reaction](ctx, listActivity, req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list GitHub reactions", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner), slog.String("repo", repo),
+			slog.Int("comment_id", commentID), slog.String("content", content))
+		return err
+	}
+
+	for _, r := range *reactions {
+		if r.User.Login != login || r.Content != content {
+			continue
+		}
+
+		req = reactionsRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, CommentID: commentID, ReactionID: r.ID}
+		if _, err := timpani.ExecuteActivity[map[string]any](ctx, deleteActivity, req); err != nil {
+			logger.From(ctx).Error("failed to delete GitHub reaction", slog.Any("error", err),
+				slog.String("thrippy_id", thrippyID), slog.String("owner", owner), slog.String("repo", repo),
+				slog.Int("comment_id", commentID), slog.Int("reaction_id", r.ID))
+			return err
+		}
+		return nil
+	}
+
+	logger.From(ctx).Debug("GitHub reaction not found", slog.String("owner", owner), slog.String("repo", repo),
+		slog.Int("comment_id", commentID), slog.String("content", content), slog.String("login", login))
+	return nil
+}
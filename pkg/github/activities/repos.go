@@ -0,0 +1,99 @@
+package activities
+
+import (
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+)
+
+// reposGetContentRequest is based on:
+// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#get-repository-content
+type reposGetContentRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Path  string `json:"path"`
+	Ref   string `json:"ref,omitempty"`
+}
+
+// reposGetContentResponse is based on:
+// https://docs.github.com/en/rest/repos/contents?apiVersion=2022-11-28#get-repository-content
+type reposGetContentResponse struct {
+	Type     string `json:"type"` // "file", "dir", "symlink", "submodule".
+	Encoding string `json:"encoding,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// GetSourceFile returns the content of a file in the given commit (or branch, if
+// the commit is unspecified), using the default Thrippy link of the GitHub App.
+// If the file doesn't exist, this function returns an empty string without an error.
+func GetSourceFile(ctx workflow.Context, owner, repo, branch, commit, path string) (string, error) {
+	ref := commit
+	if ref == "" {
+		ref = branch
+	}
+
+	req := reposGetContentRequest{Owner: owner, Repo: repo, Path: path, Ref: ref}
+	resp, err := timpani.ExecuteActivity[reposGetContentResponse](ctx, "github.repos.getContent", req)
+	if err != nil && strings.Contains(err.Error(), "404 Not Found") {
+		return "", nil
+	}
+	if err != nil {
+		logger.From(ctx).Warn("failed to read GitHub source file",
+			slog.Any("error", err), slog.String("owner", owner), slog.String("repo", repo),
+			slog.String("branch", branch), slog.String("commit", commit), slog.String("path", path))
+		return "", err
+	}
+
+	if resp.Type != "file" || resp.Encoding != "base64" {
+		logger.From(ctx).Warn("unexpected GitHub source file type or encoding",
+			slog.String("owner", owner), slog.String("repo", repo), slog.String("path", path),
+			slog.String("type", resp.Type), slog.String("encoding", resp.Encoding))
+		return "", errors.New("unexpected GitHub source file type or encoding")
+	}
+
+	// GitHub splits base64-encoded content into multiple lines.
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(resp.Content, "\n", ""))
+	if err != nil {
+		logger.From(ctx).Warn("failed to decode GitHub source file", slog.Any("error", err),
+			slog.String("owner", owner), slog.String("repo", repo), slog.String("path", path))
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// gitDeleteRefRequest is based on:
+// https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#delete-a-reference
+type gitDeleteRefRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Ref   string `json:"ref"`
+}
+
+// DeleteBranch deletes a branch (e.g. the source branch of a merged PR), on behalf of the given user:
+// https://docs.github.com/en/rest/git/refs?apiVersion=2022-11-28#delete-a-reference
+func DeleteBranch(ctx workflow.Context, thrippyID, owner, repo, branch string) error {
+	if thrippyID == "" {
+		return errors.New("missing user authentication credentials")
+	}
+
+	req := gitDeleteRefRequest{ThrippyLinkID: thrippyID, Owner: owner, Repo: repo, Ref: "heads/" + branch}
+	if _, err := timpani.ExecuteActivity[map[string]any](ctx, "github.git.deleteRef", req); err != nil {
+		logger.From(ctx).Error("failed to delete GitHub branch", slog.Any("error", err),
+			slog.String("thrippy_id", thrippyID), slog.String("owner", owner),
+			slog.String("repo", repo), slog.String("branch", branch))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package activities
+
+import (
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+	"github.com/tzrikka/timpani-api/pkg/github"
+)
+
+// teamsListMembersRequest is based on:
+// https://docs.github.com/en/rest/teams/members?apiVersion=2022-11-28#list-team-members
+type teamsListMembersRequest struct {
+	ThrippyLinkID string `json:"thrippy_link_id,omitempty"`
+
+	Org      string `json:"org"`
+	TeamSlug string `json:"team_slug"`
+}
+
+// ListTeamMembers returns the members of a GitHub team, including members of child teams,
+// using the default Thrippy link of the GitHub App (which requires the "Members" permission).
+func ListTeamMembers(ctx workflow.Context, org, teamSlug string) ([]github.User, error) {
+	req := teamsListMembersRequest{Org: org, TeamSlug: teamSlug}
+	users, err := timpani.ExecuteActivity[[]github.User](ctx, "github.teams.listMembersInOrg", req)
+	if err != nil {
+		logger.From(ctx).Error("failed to list GitHub team members", slog.Any("error", err),
+			slog.String("org", org), slog.String("team_slug", teamSlug))
+		return nil, err
+	}
+
+	return *users, nil
+}
@@ -0,0 +1,193 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/github/activities"
+)
+
+// BeautifyInlineComment adds an informative prefix to the comment's text.
+// If the comment contains a suggestion code block, it replaces that block
+// with a diff snippet, based on the commented lines in the comment's commit
+// (or in the comment's diff hunk, as a fallback), and also returns that diff
+// snippet separately, to attach it to the Slack message.
+func BeautifyInlineComment(ctx workflow.Context, comment PullComment, msg string) (string, []byte) {
+	msg = inlineCommentPrefix(comment) + msg
+
+	suggestion, ok := extractSuggestionBlock(comment.Body)
+	if !ok {
+		return msg, nil
+	}
+
+	firstLine, lastLine := commentLines(comment)
+	srcLines := sourceLines(ctx, comment, firstLine, lastLine)
+	if srcLines == nil {
+		srcLines = hunkLines(comment.DiffHunk, comment.Side, lastLine-firstLine+1)
+	}
+
+	diff := spliceSuggestion(ctx, firstLine, srcLines, suggestion)
+	if diff == nil {
+		return msg, nil
+	}
+
+	if suggestion != "" {
+		suggestion += "\n"
+	}
+	msg = strings.Replace(msg, "```suggestion\n"+suggestion, "```\n"+string(diff), 1)
+
+	return msg, diff
+}
+
+// inlineCommentPrefix constructs a prefix to a PR review comment,
+// indicating its type (file/inline) and location (path and line/s).
+func inlineCommentPrefix(comment PullComment) string {
+	line1, line2 := commentLines(comment)
+
+	subject := "Inline"
+	location := "in"
+	switch {
+	case comment.SubjectType == "file" || line2 == 0: // No line info.
+		subject = "File"
+	case line1 == line2: // Single line.
+		location = fmt.Sprintf("in line %d in", line1)
+	default: // Multiple lines.
+		location = fmt.Sprintf("in lines %d-%d in", line1, line2)
+	}
+
+	return fmt.Sprintf("<%s|%s comment> %s `%s`:\n", comment.HTMLURL, subject, location, comment.Path)
+}
+
+// commentLines returns the first and last lines of a PR review comment. If the comment is
+// outdated (i.e. its lines are no longer in the PR's diff), it returns the original lines.
+func commentLines(comment PullComment) (first, last int) {
+	first, last = comment.StartLine, comment.Line
+	if last == 0 {
+		first, last = comment.OriginalStartLine, comment.OriginalLine
+	}
+
+	if first == 0 || first > last {
+		first = last
+	}
+
+	return first, last
+}
+
+var repoURLPattern = regexp.MustCompile(`^https://[^/]+/([^/]+)/([^/]+)/pull/`)
+
+// sourceLines returns the commented lines in the file at the comment's commit. Suggestions can only
+// be made on the "RIGHT" side of the diff, so this function doesn't support the "LEFT" side.
+func sourceLines(ctx workflow.Context, comment PullComment, firstLine, lastLine int) []string {
+	if comment.Side == "LEFT" {
+		return nil
+	}
+
+	url := repoURLPattern.FindStringSubmatch(comment.HTMLURL)
+	if len(url) < 3 {
+		logger.From(ctx).Error("failed to parse GitHub comment URL", slog.String("comment_url", comment.HTMLURL))
+		return nil
+	}
+
+	// Same logic as in [commentLines]: outdated comments refer to their original commit.
+	commit := comment.CommitID
+	if comment.Line == 0 {
+		commit = comment.OriginalCommitID
+	}
+
+	file, err := activities.GetSourceFile(ctx, url[1], url[2], "", commit, comment.Path)
+	if err != nil {
+		return nil
+	}
+
+	return fileLines(file, firstLine, lastLine)
+}
+
+// fileLines returns the lines in the given range (1-based, inclusive) of the given file content.
+func fileLines(file string, first, last int) []string {
+	lines := strings.Split(file, "\n")
+	if first < 1 || first > last || last > len(lines) {
+		return nil
+	}
+	return lines[first-1 : last]
+}
+
+// extractSuggestionBlock extracts the suggestion code block from a PR review comment.
+func extractSuggestionBlock(raw string) (string, bool) {
+	_, s, ok := strings.Cut(raw, "```suggestion\n")
+	if !ok {
+		return "", false
+	}
+
+	i := strings.LastIndex(s, "```")
+	if i == -1 {
+		return "", false
+	}
+
+	return strings.TrimSuffix(s[:i], "\n"), true
+}
+
+// hunkLines returns the last n lines in the given side ("LEFT" or "RIGHT") of a diff hunk,
+// without their diff prefixes. GitHub diff hunks in PR review comments always end with
+// the last commented line, so these are the lines that a suggestion would replace.
+func hunkLines(hunk, side string, n int) []string {
+	skip := byte('-')
+	if side == "LEFT" {
+		skip = '+'
+	}
+
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSuffix(hunk, "\n"), "\n") {
+		if line == "" || line[0] == skip || line[0] == '\\' || strings.HasPrefix(line, "@@") {
+			continue
+		}
+		lines = append(lines, line[1:])
+	}
+
+	if n < 1 || n > len(lines) {
+		return nil
+	}
+	return lines[len(lines)-n:]
+}
+
+// spliceSuggestion replaces the given source lines with the
+// suggestion, and returns the result as a diff snippet.
+func spliceSuggestion(ctx workflow.Context, firstLine int, srcLines []string, suggestion string) []byte {
+	lenFrom := len(srcLines)
+	lenTo := 0
+	if suggestion != "" {
+		lenTo = strings.Count(suggestion, "\n") + 1
+	}
+
+	// If the source lines don't match the comment, fall back to a minimalistic code block.
+	if firstLine < 1 || lenFrom == 0 {
+		logger.From(ctx).Warn("mistake in generating pretty diff")
+		return nil
+	}
+
+	diff := new(bytes.Buffer)
+	fmt.Fprintf(diff, "@@ -%d,%d ", firstLine, lenFrom) //workflowcheck:ignore // Deterministic output, not a file.
+	if lenTo > 0 {
+		fmt.Fprintf(diff, "+%d,%d ", firstLine, lenTo) //workflowcheck:ignore // Deterministic output, not a file.
+	}
+	diff.WriteString("@@\n")
+
+	for _, line := range srcLines {
+		diff.WriteString("-" + line + "\n")
+	}
+
+	if suggestion == "" {
+		return diff.Bytes()
+	}
+
+	for line := range strings.SplitSeq(suggestion, "\n") {
+		diff.WriteString("+" + line + "\n")
+	}
+
+	return diff.Bytes()
+}
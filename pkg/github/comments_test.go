@@ -0,0 +1,178 @@
+package github
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInlineCommentPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		c    PullComment
+		want string
+	}{
+		{
+			name: "file_comment",
+			c:    PullComment{HTMLURL: "http://example.com", Path: "test.txt", SubjectType: "file"},
+			want: "<http://example.com|File comment> in `test.txt`:\n",
+		},
+		{
+			name: "single_line",
+			c:    PullComment{HTMLURL: "http://example.com", Path: "test.txt", SubjectType: "line", Line: 1},
+			want: "<http://example.com|Inline comment> in line 1 in `test.txt`:\n",
+		},
+		{
+			name: "multiple_lines",
+			c:    PullComment{HTMLURL: "http://example.com", Path: "test.txt", SubjectType: "line", StartLine: 2, Line: 3},
+			want: "<http://example.com|Inline comment> in lines 2-3 in `test.txt`:\n",
+		},
+		{
+			name: "outdated_lines",
+			c:    PullComment{HTMLURL: "http://example.com", Path: "test.txt", OriginalStartLine: 4, OriginalLine: 5},
+			want: "<http://example.com|Inline comment> in lines 4-5 in `test.txt`:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inlineCommentPrefix(tt.c); got != tt.want {
+				t.Errorf("inlineCommentPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHunkLines(t *testing.T) {
+	hunk := "@@ -1,4 +1,4 @@\n Line 1\n-Line 2\n+New 2\n Line 3\n\\ No newline at end of file"
+
+	tests := []struct {
+		name string
+		side string
+		n    int
+		want []string
+	}{
+		{
+			name: "right_side_single_line",
+			side: "RIGHT",
+			n:    1,
+			want: []string{"Line 3"},
+		},
+		{
+			name: "right_side_multiple_lines",
+			side: "RIGHT",
+			n:    2,
+			want: []string{"New 2", "Line 3"},
+		},
+		{
+			name: "left_side_multiple_lines",
+			side: "LEFT",
+			n:    2,
+			want: []string{"Line 2", "Line 3"},
+		},
+		{
+			name: "too_many_lines",
+			side: "RIGHT",
+			n:    4,
+		},
+		{
+			name: "no_lines",
+			side: "RIGHT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hunkLines(hunk, tt.side, tt.n); !slices.Equal(got, tt.want) {
+				t.Errorf("hunkLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileLines(t *testing.T) {
+	file := "Line 1\nLine 2\nLine 3\n"
+
+	tests := []struct {
+		name        string
+		first, last int
+		want        []string
+	}{
+		{
+			name:  "single_line",
+			first: 2,
+			last:  2,
+			want:  []string{"Line 2"},
+		},
+		{
+			name:  "multiple_lines",
+			first: 1,
+			last:  3,
+			want:  []string{"Line 1", "Line 2", "Line 3"},
+		},
+		{
+			name:  "out_of_range",
+			first: 3,
+			last:  5,
+		},
+		{
+			name:  "reversed_range",
+			first: 2,
+			last:  1,
+		},
+		{
+			name: "no_lines",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileLines(file, tt.first, tt.last); !slices.Equal(got, tt.want) {
+				t.Errorf("fileLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpliceSuggestion(t *testing.T) {
+	tests := []struct {
+		name       string
+		firstLine  int
+		srcLines   []string
+		suggestion string
+		want       string
+	}{
+		{
+			name:       "replace_single_line",
+			firstLine:  1,
+			srcLines:   []string{"Line 1"},
+			suggestion: "New 1",
+			want:       "@@ -1,1 +1,1 @@\n-Line 1\n+New 1\n",
+		},
+		{
+			name:       "replace_2_lines_with_3",
+			firstLine:  3,
+			srcLines:   []string{"Line 3", "Line 4"},
+			suggestion: "New 3\nNew 4\nNew 5",
+			want:       "@@ -3,2 +3,3 @@\n-Line 3\n-Line 4\n+New 3\n+New 4\n+New 5\n",
+		},
+		{
+			name:      "delete_lines",
+			firstLine: 2,
+			srcLines:  []string{"Line 2", "Line 3"},
+			want:      "@@ -2,2 @@\n-Line 2\n-Line 3\n",
+		},
+		{
+			name:       "missing_source_lines",
+			firstLine:  1,
+			suggestion: "New 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(spliceSuggestion(nil, tt.firstLine, tt.srcLines, tt.suggestion)); got != tt.want {
+				t.Errorf("spliceSuggestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package github
+
+import (
+	"log/slog"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/timpani-api/pkg/github"
+)
+
+// Diffstat returns the list of files in a PR, for the same purposes as Bitbucket diffstats
+// (e.g. code ownership and high-risk file detection). See [data.LoadDiffstatPaths].
+func Diffstat(ctx workflow.Context, event PullRequestEvent) []github.File {
+	owner, repo, found := strings.Cut(event.PullRequest.Base.Repo.FullName, "/")
+	if !found {
+		logger.From(ctx).Error("failed to parse GitHub owner and repository name",
+			slog.String("full_name", event.PullRequest.Base.Repo.FullName))
+		return nil
+	}
+
+	user := data.SelectUserByGitHubID(ctx, event.Sender.Login)
+
+	files, err := github.PullRequestsListFiles(ctx, user.ThrippyLink, owner, repo, event.PullRequest.Number)
+	if err != nil {
+		logger.From(ctx).Error("failed to list GitHub PR's files", slog.Any("error", err),
+			slog.String("thrippy_id", user.ThrippyLink), slog.String("pr_url", event.PullRequest.HTMLURL))
+		return nil
+	}
+
+	return files
+}
@@ -6,6 +6,30 @@ import (
 	"github.com/tzrikka/timpani-api/pkg/github"
 )
 
+// CheckRunEvent is based on:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_run
+type CheckRunEvent struct {
+	Action   string   `json:"action"`
+	CheckRun CheckRun `json:"check_run"`
+	Sender   User     `json:"sender"`
+
+	// Repository   `json:"repository"`
+	// Organization `json:"organization"`
+	// Installation `json:"installation"`
+}
+
+// CheckSuiteEvent is based on:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_suite
+type CheckSuiteEvent struct {
+	Action     string     `json:"action"`
+	CheckSuite CheckSuite `json:"check_suite"`
+	Sender     User       `json:"sender"`
+
+	// Repository   `json:"repository"`
+	// Organization `json:"organization"`
+	// Installation `json:"installation"`
+}
+
 // IssueCommentEvent is based on:
 // https://docs.github.com/en/webhooks/webhook-events-and-payloads#issue_comment
 type IssueCommentEvent struct {
@@ -24,17 +48,20 @@ type IssueCommentEvent struct {
 // PullRequestEvent is based on:
 // https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request
 type PullRequestEvent struct {
-	Action      string      `json:"action"`
-	Number      int         `json:"number"`
-	PullRequest PullRequest `json:"pull_request"`
-	Sender      User        `json:"sender"`
-
-	Assignee          *User    `json:"assignee,omitempty"`
-	RequestedReviewer *User    `json:"requested_reviewer,omitempty"`
-	RequestedTeam     *Team    `json:"requested_team,omitempty"`
-	Changes           *Changes `json:"changes,omitempty"`
-	Before            *string  `json:"before,omitempty"`
-	After             *string  `json:"after,omitempty"`
+	Action      string             `json:"action"`
+	Number      int                `json:"number"`
+	PullRequest LabeledPullRequest `json:"pull_request"`
+	Sender      User               `json:"sender"`
+
+	Assignee          *User      `json:"assignee,omitempty"`
+	RequestedReviewer *User      `json:"requested_reviewer,omitempty"`
+	RequestedTeam     *Team      `json:"requested_team,omitempty"`
+	Label             *Label     `json:"label,omitempty"`
+	Milestone         *Milestone `json:"milestone,omitempty"`
+	Changes           *Changes   `json:"changes,omitempty"`
+	Before            *string    `json:"before,omitempty"`
+	After             *string    `json:"after,omitempty"`
+	Reason            *string    `json:"reason,omitempty"` // Auto-merge disabled, or PR dequeued.
 
 	// Repository   `json:"repository"`
 	// Organization `json:"organization"`
@@ -86,6 +113,25 @@ type PullRequestReviewThreadEvent struct {
 	// Installation `json:"installation"`
 }
 
+// StatusEvent is based on:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#status
+type StatusEvent struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	Name        string `json:"name"` // Repository's full name.
+	Context     string `json:"context"`
+	State       string `json:"state"` // "pending", "success", "failure", "error".
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Sender      User   `json:"sender"`
+
+	// Branches     `json:"branches"`
+	// Commit       `json:"commit"`
+	// Repository   `json:"repository"`
+	// Organization `json:"organization"`
+	// Installation `json:"installation"`
+}
+
 type (
 	Issue        = github.Issue
 	IssueComment = github.IssueComment
@@ -122,9 +168,72 @@ type Organization struct {
 	Login  string `json:"login"`
 }
 
+// LabeledPullRequest extends Timpani's [github.PullRequest] with details that it doesn't support (yet).
+type LabeledPullRequest struct {
+	PullRequest
+
+	Labels    []Label    `json:"labels,omitempty"`
+	Milestone *Milestone `json:"milestone,omitempty"`
+}
+
+// Label is based on:
+// https://docs.github.com/en/rest/issues/labels?apiVersion=2022-11-28
+type Label struct {
+	ID          int64  `json:"id"`
+	NodeID      string `json:"node_id"`
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Milestone is based on:
+// https://docs.github.com/en/rest/issues/milestones?apiVersion=2022-11-28
+type Milestone struct {
+	ID      int64  `json:"id"`
+	NodeID  string `json:"node_id"`
+	HTMLURL string `json:"html_url"`
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"` // "open" or "closed".
+}
+
 // Thread is based on:
 // https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request_review_thread
 type Thread struct {
 	NodeID   string        `json:"node_id"`
 	Comments []PullComment `json:"comments"`
 }
+
+// CheckRun is based on:
+//   - https://docs.github.com/en/rest/checks/runs?apiVersion=2022-11-28
+//   - https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_run
+type CheckRun struct {
+	ID         int64  `json:"id"`
+	NodeID     string `json:"node_id"`
+	HTMLURL    string `json:"html_url"`
+	DetailsURL string `json:"details_url,omitempty"`
+
+	Name       string         `json:"name"`
+	HeadSHA    string         `json:"head_sha"`
+	Status     string         `json:"status"`               // "queued", "in_progress", "completed", etc.
+	Conclusion string         `json:"conclusion,omitempty"` // "success", "failure", "cancelled", etc.
+	Output     CheckRunOutput `json:"output"`
+	CheckSuite CheckSuite     `json:"check_suite"`
+}
+
+type CheckRunOutput struct {
+	Title   string `json:"title,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// CheckSuite is based on:
+//   - https://docs.github.com/en/rest/checks/suites?apiVersion=2022-11-28
+//   - https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_suite
+type CheckSuite struct {
+	ID         int64  `json:"id"`
+	NodeID     string `json:"node_id"`
+	HeadBranch string `json:"head_branch,omitempty"`
+	HeadSHA    string `json:"head_sha"`
+	Status     string `json:"status,omitempty"`     // "queued", "in_progress", "completed", etc.
+	Conclusion string `json:"conclusion,omitempty"` // "success", "failure", "cancelled", etc.
+}
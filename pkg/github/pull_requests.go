@@ -1,6 +1,8 @@
 package github
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -13,7 +15,7 @@ import (
 	"github.com/tzrikka/revchat/pkg/users"
 )
 
-// InitPRData saves the initial state of a new PR: snapshots of PR metadata,
+// InitPRData saves the initial state of a new PR: snapshots of PR metadata and files,
 // and a 2-way ID mapping for syncs between GitHub and Slack. If there are
 // errors, they are logged but ignored, as we can try to recreate the data later.
 func InitPRData(ctx workflow.Context, event PullRequestEvent, prChannelID, slackAlertsChannel string) {
@@ -22,7 +24,8 @@ func InitPRData(ctx workflow.Context, event PullRequestEvent, prChannelID, slack
 			err, "PR", event.PullRequest.HTMLURL, "Channel", fmt.Sprintf("`%s` (<#%s>)", prChannelID, prChannelID))
 	}
 
-	data.StorePRSnapshot(ctx, event.PullRequest.HTMLURL, event.PullRequest)
+	StorePRSnapshot(ctx, event.PullRequest)
+	data.StoreDiffstat(ctx, event.PullRequest.HTMLURL, Diffstat(ctx, event))
 
 	email := users.GitHubIDToEmail(ctx, event.Sender.Login)
 	if email == "" {
@@ -36,6 +39,99 @@ func InitPRData(ctx workflow.Context, event PullRequestEvent, prChannelID, slack
 	data.InitTurns(ctx, event.PullRequest.HTMLURL, email)
 }
 
+// Snapshot is a GitHub PR snapshot, with additional details that are populated and used by RevChat, not GitHub.
+type Snapshot struct {
+	LabeledPullRequest
+
+	// MergeQueueState is "queued" while the PR is in the repository's merge queue,
+	// because unlike auto-merge, GitHub doesn't report this in PR event payloads.
+	MergeQueueState string `json:"merge_queue_state,omitempty"`
+}
+
+// LoadPRSnapshot reads a snapshot of a PR, which is used to detect and analyze metadata
+// changes. If a snapshot doesn't exist, this function returns a nil pointer and no error.
+func LoadPRSnapshot(ctx workflow.Context, prURL string) (*Snapshot, error) {
+	m, err := data.LoadPRSnapshot(ctx, prURL)
+	if err != nil || m == nil {
+		return nil, err // Error may or may not be nil, but in either case there's no snapshot to return.
+	}
+
+	snapshot := new(Snapshot)
+	if err := mapToStruct(m, snapshot); err != nil {
+		logger.From(ctx).Error("previous snapshot of GitHub PR is invalid",
+			slog.Any("error", err), slog.String("pr_url", prURL))
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// StorePRSnapshot writes a snapshot of a PR, while persisting the
+// details that are populated by RevChat across snapshots. It also
+// returns the new snapshot, for the convenience of the caller.
+func StorePRSnapshot(ctx workflow.Context, pr LabeledPullRequest) Snapshot {
+	snapshot := Snapshot{LabeledPullRequest: pr}
+	if prev, _ := LoadPRSnapshot(ctx, pr.HTMLURL); prev != nil {
+		snapshot.MergeQueueState = prev.MergeQueueState
+	}
+
+	data.StorePRSnapshot(ctx, pr.HTMLURL, snapshot)
+	return snapshot
+}
+
+// StoreMergeQueueState writes a snapshot of a PR, with the given merge-queue state (empty = not queued).
+func StoreMergeQueueState(ctx workflow.Context, pr LabeledPullRequest, state string) {
+	data.StorePRSnapshot(ctx, pr.HTMLURL, Snapshot{LabeledPullRequest: pr, MergeQueueState: state})
+}
+
+// FindPRsByCommit returns all (0 or more) the PR snapshots that are currently associated with the given commit hash.
+// Note that these snapshots are pruned: see [data.FindPRsByCommit] for the list of retained PR details.
+func FindPRsByCommit(ctx workflow.Context, hash string) ([]*LabeledPullRequest, error) {
+	ms, err := data.FindPRsByCommit(ctx, hash)
+	if err != nil || ms == nil {
+		return nil, err
+	}
+
+	prs := make([]*LabeledPullRequest, 0, len(ms))
+	for _, m := range ms {
+		pr := new(LabeledPullRequest)
+		if err := mapToStruct(m, pr); err != nil {
+			logger.From(ctx).Error("snapshot of GitHub PR is invalid",
+				slog.Any("error", err), slog.String("commit_hash", hash))
+			continue
+		}
+		if pr.HTMLURL == "" { // Bitbucket PR with the same commit hash, e.g. in a mirrored repository.
+			continue
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, nil
+}
+
+// mapToStruct converts a map-based representation of JSON data into a [LabeledPullRequest] or [Snapshot] struct.
+func mapToStruct(m, pr any) error {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(m); err != nil {
+		return err
+	} //workflowcheck:ignore // Deterministic output, not a file.
+
+	if err := json.NewDecoder(buf).Decode(pr); err != nil {
+		return err
+	} //workflowcheck:ignore // Deterministic input, not a file.
+
+	return nil
+}
+
+// LabelNames returns the names of the given PR labels.
+func LabelNames(labels []Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
 func userLogins(us []User) []string {
 	if len(us) == 0 {
 		return nil
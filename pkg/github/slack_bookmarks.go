@@ -7,9 +7,15 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
 	"github.com/tzrikka/timpani-api/pkg/slack"
 )
 
+const (
+	maxBookmarkTitleLen = 200
+)
+
 func newBookmarkTitles(pr *PullRequest, issue *Issue) []string {
 	switch {
 	case pr != nil:
@@ -39,7 +45,8 @@ func SetChannelBookmarks(ctx workflow.Context, channelID, prURL string, pr PullR
 	_ = slack.BookmarksAdd(ctx, channelID, titles[3], prURL+"/commits", ":pushpin:")
 	_ = slack.BookmarksAdd(ctx, channelID, titles[4], prURL+"/files", ":open_file_folder:")
 	_ = slack.BookmarksAdd(ctx, channelID, titles[5], prURL+".diff", ":hammer_and_wrench:")
-	_ = slack.BookmarksAdd(ctx, channelID, "Checks (0)", prURL+"/checks", ":vertical_traffic_light:")
+	_ = slack.BookmarksAdd(ctx, channelID, "Checks: no results", prURL+"/checks", ":vertical_traffic_light:")
+	_ = slack.BookmarksAdd(ctx, channelID, mergeBookmarkTitle(pr, ""), prURL, ":checkered_flag:")
 }
 
 // UpdateChannelBookmarks updates the bookmarks in the PR's Slack channel, based on the latest PR event.
@@ -63,3 +70,45 @@ func UpdateChannelBookmarks(ctx workflow.Context, pr *PullRequest, issue *Issue,
 		}
 	}
 }
+
+// UpdateChannelBuildsBookmark updates the "Checks" bookmark in the PR's Slack channel, based on the latest check
+// or status event. This is a deferred call that doesn't return an error, because handling the event itself is more important.
+func UpdateChannelBuildsBookmark(ctx workflow.Context, channelID, prURL string) {
+	// Unlike Bitbucket build descriptions, GitHub check names are short and informative.
+	activities.UpdateBuildsBookmark(ctx, channelID, prURL, "Checks", func(b data.CommitStatus) string {
+		return b.Name
+	})
+}
+
+// UpdateChannelMergeBookmark updates the "Merge" bookmark in the PR's Slack channel, based on the latest auto-merge
+// or merge-queue event. This is a deferred call that doesn't return an error, because handling the event itself is more important.
+func UpdateChannelMergeBookmark(ctx workflow.Context, channelID string, pr PullRequest, mergeQueueState string) {
+	bookmarks, err := slack.BookmarksList(ctx, channelID)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Slack channel bookmarks", slog.Any("error", err))
+		return
+	}
+	if len(bookmarks) < 8 {
+		return // Channels which were created before this bookmark was introduced.
+	}
+
+	title := mergeBookmarkTitle(pr, mergeQueueState)
+	if title == bookmarks[7].Title {
+		return
+	}
+
+	if err := slack.BookmarksEditTitle(ctx, channelID, bookmarks[7].ID, title); err != nil {
+		logger.From(ctx).Error("failed to update Slack channel's merge bookmark", slog.Any("error", err))
+	}
+}
+
+func mergeBookmarkTitle(pr PullRequest, mergeQueueState string) string {
+	switch {
+	case mergeQueueState != "":
+		return "Merge queue: " + mergeQueueState
+	case pr.AutoMerge != nil:
+		return "Auto-merge: " + pr.AutoMerge.MergeMethod
+	default:
+		return "Auto-merge: off"
+	}
+}
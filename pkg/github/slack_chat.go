@@ -11,6 +11,7 @@ import (
 
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/markdown"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
 	"github.com/tzrikka/revchat/pkg/users"
 	"github.com/tzrikka/timpani-api/pkg/slack"
@@ -118,12 +119,13 @@ func impersonateUserInBoth(ctx workflow.Context, url, channelID, threadTS, idPre
 }
 
 // uploadDiff uploads the given diff content to Slack and modifies the message
-// to reference this uploaded file instead of Bitbucket's minimalistic code block.
+// to reference this uploaded file instead of a minimalistic code block.
 // It returns the modified message and the uploaded file ID (or an empty string).
 func uploadDiff(ctx workflow.Context, diff []byte, url, msg string) (string, string) {
-	parts := strings.Split(url, "-")
-	filename := parts[len(parts)-1] + ".diff"
-	title := "Diff " + parts[len(parts)-1]
+	id := url[strings.LastIndexAny(url, "/#")+1:] // E.g. "discussion_r123".
+	id = strings.TrimPrefix(id, "discussion_r")
+	filename := id + ".diff"
+	title := "Diff " + id
 
 	file, err := activities.Upload(ctx, diff, filename, title, "diff", "text/x-diff", "", "")
 	if err != nil || file == nil {
@@ -131,7 +133,7 @@ func uploadDiff(ctx workflow.Context, diff []byte, url, msg string) (string, str
 	}
 
 	// Success: replace the code block in the message with a prettier rendering of the file.
-	parts = strings.Split(msg, "\n```")
+	parts := strings.Split(msg, "\n```")
 	msg = fmt.Sprintf("%s<%s| >", parts[0], file.Permalink)
 	if len(parts) > 2 {
 		msg += parts[2]
@@ -198,6 +200,27 @@ func impersonateUser(ctx workflow.Context, user User) (displayName, icon string)
 	return users.SlackIDToDisplayName(ctx, id), users.SlackIDToIcon(ctx, id)
 }
 
+func DeleteSlackMsg(ctx workflow.Context, url string) error {
+	ids, err := msgIDsForCommentURL(ctx, url)
+	if err != nil || ids == nil {
+		return err
+	}
+
+	data.DeleteURLAndIDMapping(ctx, url)
+
+	return activities.DeleteMessage(ctx, ids[0], ids[len(ids)-1])
+}
+
+func EditSlackMsg(ctx workflow.Context, url, msg string) error {
+	ids, err := msgIDsForCommentURL(ctx, url)
+	if err != nil || ids == nil {
+		return err
+	}
+
+	msg = markdown.ShortenSlackURLs(url, msg)
+	return activities.UpdateMessage(ctx, ids[0], ids[len(ids)-1], msg)
+}
+
 func msgIDsForCommentURL(ctx workflow.Context, url string) ([]string, error) {
 	ids, err := data.SwitchURLAndID(ctx, url)
 	if err != nil {
@@ -0,0 +1,287 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/cache"
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/config"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/github"
+	ghactivities "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// We don't want to spam the channel with "ready to merge" messages in times of frequent
+// builds, so we throttle these messages to at most once per hour per PR. This is not
+// a critical or common need, so a non-persistent in-memory cache is good enough.
+var mergeReadiness = cache.New[bool](time.Hour, cache.DefaultCleanupInterval)
+
+var prURLPattern = regexp.MustCompile(`^https://[^/]+/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// CheckRunWorkflow mirrors check run updates in the corresponding PR's Slack channel:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_run
+func (c Config) CheckRunWorkflow(ctx workflow.Context, event github.CheckRunEvent) error {
+	switch event.Action {
+	case "created", "completed":
+		// Handled below.
+	case "rerequested", "requested_action":
+		return nil // A new check run will be created, or this isn't a status update.
+	default:
+		logger.From(ctx).Error("unrecognized GitHub check run event action", slog.String("action", event.Action))
+		return errors.New("unrecognized GitHub check run event action: " + event.Action)
+	}
+
+	cr := event.CheckRun
+	desc := cr.Output.Title
+	if desc == "" {
+		desc = checkRunDesc(cr)
+	}
+
+	url := cr.HTMLURL
+	if url == "" {
+		url = cr.DetailsURL
+	}
+
+	cs := data.CommitStatus{Name: cr.Name, State: checkRunState(cr), Desc: desc, URL: url}
+	return c.updateCommitStatus(ctx, cr.HeadSHA, cr.Name, cs)
+}
+
+// CheckSuiteWorkflow handles check suite completions, which indicate that all the check runs of a
+// specific GitHub App are done. Individual check runs are already announced by [Config.CheckRunWorkflow],
+// so the only thing left to do here is to reevaluate the PR's merge readiness:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#check_suite
+func (c Config) CheckSuiteWorkflow(ctx workflow.Context, event github.CheckSuiteEvent) error {
+	switch event.Action {
+	case "completed":
+		// Handled below.
+	case "requested", "rerequested":
+		return nil // Check runs will be created and announced individually.
+	default:
+		logger.From(ctx).Error("unrecognized GitHub check suite event action", slog.String("action", event.Action))
+		return errors.New("unrecognized GitHub check suite event action: " + event.Action)
+	}
+
+	hash := event.CheckSuite.HeadSHA
+	prs, err := github.FindPRsByCommit(ctx, hash)
+	if err != nil {
+		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to associate commit hash with PR", err)
+	}
+
+	for _, pr := range prs {
+		channelID, found := activities.LookupChannel(ctx, pr.HTMLURL)
+		if !found {
+			continue
+		}
+
+		github.UpdateChannelBuildsBookmark(ctx, channelID, pr.HTMLURL)
+		if data.ReadBuilds(ctx, pr.HTMLURL).CommitHash == hash {
+			err = errors.Join(err, c.announceMergeReadiness(ctx, channelID, pr))
+		}
+	}
+
+	return err
+}
+
+// StatusWorkflow mirrors commit status updates (which are reported by external
+// services, unlike check runs) in the corresponding PR's Slack channel:
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#status
+func (c Config) StatusWorkflow(ctx workflow.Context, event github.StatusEvent) error {
+	cs := data.CommitStatus{Name: event.Context, State: statusState(event.State), Desc: event.Description, URL: event.TargetURL}
+	return c.updateCommitStatus(ctx, event.SHA, event.Context, cs)
+}
+
+// updateCommitStatus associates a check run or commit status with 0 or more PRs, and announces
+// it in their Slack channels. If a PR is ready to be merged, it announces that too. If a PR's Slack
+// channel is already archived (but we still store data for it), this function cleans up the data.
+//
+// This function uses the HTMLURL, Draft, Labels and User.Login details of [github.PullRequest] snapshots.
+// This is relevant for PR detail pruning in [data.FindPRsByCommit].
+func (c Config) updateCommitStatus(ctx workflow.Context, hash, key string, cs data.CommitStatus) error {
+	// Commit status --> commit hash --> 0 or more [github.PullRequest] instances.
+	prs, err := github.FindPRsByCommit(ctx, hash)
+	if err != nil {
+		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to associate commit hash with PR", err)
+	}
+
+	if len(prs) == 0 {
+		logger.From(ctx).Debug("PR not found for commit status", slog.String("hash", hash),
+			slog.String("build_name", cs.Name), slog.String("build_url", cs.URL))
+		// This is not a problem: the commit may not belong to any open PR,
+		// or may be obsoleted by a newer commit in the snapshot.
+		return nil
+	}
+
+	// Build history is recorded per repository, even if multiple PRs share the same commit.
+	flaky := map[string]bool{}
+	for _, pr := range prs {
+		repoURL := data.RepoURL(pr.HTMLURL)
+		if _, found := flaky[repoURL]; !found {
+			flaky[repoURL] = data.RecordBuildTransition(ctx, pr.HTMLURL, hash, key, cs)
+		}
+		err = errors.Join(err, c.updatePRCommitStatus(ctx, pr, hash, key, cs, flaky[repoURL]))
+	}
+
+	return err
+}
+
+func (c Config) updatePRCommitStatus(ctx workflow.Context, pr *github.LabeledPullRequest, hash, key string, cs data.CommitStatus, flaky bool) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	prURL := pr.HTMLURL
+	channelID, found := activities.LookupChannel(ctx, prURL)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBuildsBookmark(ctx, channelID, prURL)
+
+	// Check runs are reported when they're created and again when they're completed, and
+	// statuses may be reported repeatedly, so we announce only changes in the build's state.
+	prev := data.ReadBuilds(ctx, prURL)
+	data.UpdateBuilds(ctx, prURL, hash, key, cs)
+	if prev.CommitHash == hash && prev.Builds[key].State == cs.State {
+		return nil
+	}
+
+	desc, _, _ := strings.Cut(cs.Desc, "\n")
+	msg := fmt.Sprintf(`%s "%s" build status: %s`, activities.BuildStateEmoji(cs.State), cs.Name, desc)
+	if cs.URL != "" {
+		msg = fmt.Sprintf(`%s "%s" build status: <%s|%s>`, activities.BuildStateEmoji(cs.State), cs.Name, cs.URL, desc)
+	}
+	if flaky {
+		msg += activities.FlakyBuildNote
+	}
+	err := activities.PostMessage(ctx, channelID, msg)
+
+	// If the channel is archived but we still store data for it, clean it up. We don't consider this a server error.
+	if err != nil && strings.Contains(err.Error(), "is_archived") {
+		data.CleanupPRData(ctx, channelID, prURL)
+		return nil
+	}
+
+	if cs.State != "SUCCESSFUL" {
+		return err
+	}
+
+	return errors.Join(err, c.announceMergeReadiness(ctx, channelID, pr))
+}
+
+// announceMergeReadiness announces if the PR is ready to be merged, using the same criteria
+// as Bitbucket PRs: not a draft, all builds are successful, the PR has enough approvals,
+// and no pending change requests. Reviews are not included in check and status events,
+// so unlike the other criteria they are retrieved from GitHub (only if all else is ready),
+// on behalf of the PR's author. PRs with labels that are mapped to the "no-merge" rule
+// are never announced, and neither are any PRs if looking up reviews isn't enabled.
+func (c Config) announceMergeReadiness(ctx workflow.Context, channelID string, pr *github.LabeledPullRequest) error {
+	prURL := pr.HTMLURL
+	if pr.Draft || config.HasLabelRule(c.LabelRules, github.LabelNames(pr.Labels), config.LabelRuleNoMerge) {
+		return nil
+	}
+	if !data.AllBuildsSuccessful(ctx, prURL) {
+		return nil
+	}
+
+	if announced, _ := mergeReadiness.Get(prURL); announced {
+		return nil
+	}
+	if !c.ListReviews {
+		return nil
+	}
+
+	thrippyID := data.SelectUserByGitHubID(ctx, pr.User.Login).ThrippyLink
+	if thrippyID == "" {
+		logger.From(ctx).Debug("can't check GitHub PR's reviews without the author's authorization",
+			slog.String("pr_url", prURL), slog.String("author", pr.User.Login))
+		return nil
+	}
+
+	approvals, changeRequests, err := countReviews(ctx, thrippyID, prURL)
+	if err != nil || approvals < c.MinApprovals || changeRequests > 0 {
+		return err
+	}
+
+	logger.From(ctx).Info("GitHub PR is ready to be merged", slog.String("pr_url", prURL))
+	if err := activities.PostMessage(ctx, channelID, "<!here> this PR is ready to be merged! :tada:"); err != nil {
+		return err
+	}
+
+	mergeReadiness.Set(prURL, true, cache.DefaultExpiration)
+	return nil
+}
+
+// countReviews counts the latest approvals and change requests of the reviewers of the given PR.
+func countReviews(ctx workflow.Context, thrippyID, prURL string) (approvals, changeRequests int, err error) {
+	url := prURLPattern.FindStringSubmatch(prURL)
+	if len(url) < 4 {
+		logger.From(ctx).Error("failed to parse GitHub PR's URL", slog.String("url", prURL))
+		return 0, 0, errors.New("invalid GitHub PR URL: " + prURL)
+	}
+
+	prID, err := strconv.Atoi(url[3])
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse PR number %q: %w", url[3], err)
+	}
+
+	reviews, err := ghactivities.ListPullRequestReviews(ctx, thrippyID, url[1], url[2], prID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, r := range ghactivities.LatestReviews(reviews) {
+		switch strings.ToLower(r.State) {
+		case "approved":
+			approvals++
+		case "changes_requested":
+			changeRequests++
+		}
+	} //workflowcheck:ignore
+
+	return approvals, changeRequests, nil
+}
+
+// checkRunState normalizes a GitHub check run's status and conclusion
+// to the same build states that Bitbucket uses, for consistency.
+func checkRunState(cr github.CheckRun) string {
+	if cr.Status != "completed" {
+		return "INPROGRESS"
+	}
+
+	switch cr.Conclusion {
+	case "success", "neutral", "skipped":
+		return "SUCCESSFUL"
+	case "cancelled", "stale":
+		return "STOPPED"
+	default: // "failure", "timed_out", "action_required", "startup_failure".
+		return "FAILED"
+	}
+}
+
+// checkRunDesc returns a human-readable fallback description of a check run, if it has no output title.
+func checkRunDesc(cr github.CheckRun) string {
+	s := cr.Status
+	if s == "completed" {
+		s = cr.Conclusion
+	}
+	return strings.ReplaceAll(s, "_", " ")
+}
+
+// statusState normalizes a GitHub commit status state to
+// the same build states that Bitbucket uses, for consistency.
+func statusState(state string) string {
+	switch state {
+	case "pending":
+		return "INPROGRESS"
+	case "success":
+		return "SUCCESSFUL"
+	default: // "failure", "error".
+		return "FAILED"
+	}
+}
@@ -0,0 +1,79 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/github"
+)
+
+func TestCheckRunState(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   github.CheckRun
+		want string
+	}{
+		{
+			name: "queued",
+			cr:   github.CheckRun{Status: "queued"},
+			want: "INPROGRESS",
+		},
+		{
+			name: "in_progress",
+			cr:   github.CheckRun{Status: "in_progress"},
+			want: "INPROGRESS",
+		},
+		{
+			name: "success",
+			cr:   github.CheckRun{Status: "completed", Conclusion: "success"},
+			want: "SUCCESSFUL",
+		},
+		{
+			name: "skipped",
+			cr:   github.CheckRun{Status: "completed", Conclusion: "skipped"},
+			want: "SUCCESSFUL",
+		},
+		{
+			name: "cancelled",
+			cr:   github.CheckRun{Status: "completed", Conclusion: "cancelled"},
+			want: "STOPPED",
+		},
+		{
+			name: "failure",
+			cr:   github.CheckRun{Status: "completed", Conclusion: "failure"},
+			want: "FAILED",
+		},
+		{
+			name: "timed_out",
+			cr:   github.CheckRun{Status: "completed", Conclusion: "timed_out"},
+			want: "FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkRunState(tt.cr); got != tt.want {
+				t.Errorf("checkRunState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusState(t *testing.T) {
+	tests := []struct {
+		state string
+		want  string
+	}{
+		{state: "pending", want: "INPROGRESS"},
+		{state: "success", want: "SUCCESSFUL"},
+		{state: "failure", want: "FAILED"},
+		{state: "error", want: "FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			if got := statusState(tt.state); got != tt.want {
+				t.Errorf("statusState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/config"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/github"
 	"github.com/tzrikka/revchat/pkg/markdown"
@@ -45,18 +46,20 @@ func (c Config) PullRequestWorkflow(ctx workflow.Context, event github.PullReque
 	case "locked", "unlocked":
 		return prLocked(ctx, event)
 
-	// Ignored actions.
 	case "auto_merge_enabled", "auto_merge_disabled":
+		return c.prAutoMerge(ctx, event)
 	case "enqueued", "dequeued":
+		return c.prMergeQueue(ctx, event)
+
 	case "labeled", "unlabeled":
+		return c.prLabeled(ctx, event)
 	case "milestoned", "demilestoned":
+		return c.prMilestoned(ctx, event)
 
 	default:
 		logger.From(ctx).Error("unrecognized GitHub PR event action", slog.String("action", event.Action))
 		return errors.New("unrecognized GitHub PR event action: " + event.Action)
 	}
-
-	return nil
 }
 
 // prOpened initializes a new Slack channel for a newly-created or reopened PR.
@@ -67,8 +70,14 @@ func (c Config) PullRequestWorkflow(ctx workflow.Context, event github.PullReque
 // Use a user token ("xoxp-...") to unarchive conversations rather than a bot token.
 //
 // Partial workaround: treat "reopened" events as "opened". Drawback: losing pre-archiving channel history.
+//
+// This function is also called when a label which is mapped to the "skip" rule is removed from a PR.
 func (c Config) prOpened(ctx workflow.Context, event github.PullRequestEvent) error {
 	pr := event.PullRequest
+	if config.HasLabelRule(c.LabelRules, github.LabelNames(pr.Labels), config.LabelRuleSkip) {
+		logger.From(ctx).Debug("ignoring GitHub PR - labeled to be skipped", slog.String("pr_url", pr.HTMLURL))
+		return nil
+	}
 
 	maxLen, prefix, private := c.SlackChannelNameMaxLength, c.SlackChannelNamePrefix, c.SlackChannelsArePrivate
 	channelID, err := slack.CreateChannel(ctx, pr.Number, pr.Title, pr.HTMLURL, maxLen, prefix, private)
@@ -85,11 +94,14 @@ func (c Config) prOpened(ctx workflow.Context, event github.PullRequestEvent) er
 	// Channel cosmetics.
 	activities.SetChannelTopic(ctx, channelID, pr.HTMLURL)
 	activities.SetChannelDescription(ctx, c.TemporalOpts, channelID, pr.Title, pr.HTMLURL, "")
-	github.SetChannelBookmarks(ctx, channelID, pr.HTMLURL, pr)
+	github.SetChannelBookmarks(ctx, channelID, pr.HTMLURL, pr.PullRequest)
 
 	msg := "%s created this PR: " + markdown.LinkifyTitle(ctx, c.LinkifyMap, pr.HTMLURL, pr.Title)
-	if event.Action == "reopened" {
+	switch event.Action {
+	case "reopened":
 		msg = strings.Replace(msg, "created", "reopened", 1)
+	case "unlabeled":
+		msg = strings.Replace(msg, "created", "stopped skipping", 1)
 	}
 	if body := strings.TrimSpace(pr.Body); body != "" && body != pr.Title {
 		msg += "\n\n" + markdown.GitHubToSlack(ctx, body, pr.HTMLURL)
@@ -97,7 +109,7 @@ func (c Config) prOpened(ctx workflow.Context, event github.PullRequestEvent) er
 	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
 
 	followerIDs := data.SelectUserByGitHubID(ctx, pr.User.Login).Followers
-	err = activities.InviteUsersToChannel(ctx, c.TemporalOpts, channelID, pr.HTMLURL, github.ChannelMembers(ctx, pr), followerIDs)
+	err = activities.InviteUsersToChannel(ctx, c.TemporalOpts, channelID, pr.HTMLURL, github.ChannelMembers(ctx, pr.PullRequest), followerIDs)
 	if err != nil {
 		// True = send an error DM only if the user is opted-in.
 		if userID := users.GitHubIDToSlackID(ctx, event.Sender.Login, true); userID != "" {
@@ -109,6 +121,9 @@ func (c Config) prOpened(ctx workflow.Context, event github.PullRequestEvent) er
 		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to invite users to Slack channel for "+pr.HTMLURL, err)
 	}
 
+	if event.Action == "opened" {
+		c.autoAddReviewers(ctx, channelID, pr.PullRequest)
+	}
 	return nil
 }
 
@@ -152,6 +167,7 @@ func (c Config) prConvertedToDraft(ctx workflow.Context, event github.PullReques
 		return nil
 	}
 
+	github.StorePRSnapshot(ctx, event.PullRequest) // Draft state affects merge readiness.
 	github.MentionUserInMsg(ctx, channelID, event.Sender, "%s marked this PR as a draft. :construction:")
 	email := users.GitHubIDToEmail(ctx, event.PullRequest.User.Login)
 	_, _, err := data.SetReviewerTurn(ctx, c.TemporalOpts, event.PullRequest.HTMLURL, email, true)
@@ -168,10 +184,11 @@ func (c Config) prReadyForReview(ctx workflow.Context, event github.PullRequestE
 		return nil
 	}
 
+	github.StorePRSnapshot(ctx, event.PullRequest) // Draft state affects merge readiness.
 	github.MentionUserInMsg(ctx, channelID, event.Sender, "%s marked this PR as ready for review. :eyes:")
 	err := data.SwitchTurn(ctx, c.TemporalOpts, event.PullRequest.HTMLURL, users.GitHubIDToEmail(ctx, event.Sender.Login), true)
 
-	members := github.ChannelMembers(ctx, event.PullRequest)
+	members := github.ChannelMembers(ctx, event.PullRequest.PullRequest)
 	return errors.Join(err, activities.InviteUsersToChannel(ctx, c.TemporalOpts, channelID, event.PullRequest.HTMLURL, members, nil))
 }
 
@@ -189,12 +206,12 @@ func lookupChannel(ctx workflow.Context, pr github.PullRequest) (string, bool) {
 // https://docs.github.com/pull-requests/collaborating-with-pull-requests/proposing-changes-to-your-work-with-pull-requests/requesting-a-pull-request-review
 func (c Config) prReviewRequests(ctx workflow.Context, event github.PullRequestEvent) error {
 	// If we're not tracking this PR, there's no need/way to announce this event.
-	channelID, found := lookupChannel(ctx, event.PullRequest)
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
 	if !found {
 		return nil
 	}
 
-	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest.PullRequest, nil, channelID)
 
 	prURL := event.PullRequest.HTMLURL
 	var errs []error
@@ -260,12 +277,12 @@ func (c Config) prReviewRequests(ctx workflow.Context, event github.PullRequestE
 func (c Config) prEdited(ctx workflow.Context, event github.PullRequestEvent) error {
 	// If we're not tracking this PR, there's no need/way to announce this event.
 	pr := event.PullRequest
-	channelID, found := lookupChannel(ctx, pr)
+	channelID, found := lookupChannel(ctx, pr.PullRequest)
 	if !found {
 		return nil
 	}
 
-	defer github.UpdateChannelBookmarks(ctx, &pr, nil, channelID)
+	defer github.UpdateChannelBookmarks(ctx, &pr.PullRequest, nil, channelID)
 
 	email := users.GitHubIDToEmail(ctx, event.Sender.Login)
 
@@ -307,14 +324,18 @@ func (c Config) prEdited(ctx workflow.Context, event github.PullRequestEvent) er
 // branch was updated from the base branch or new commits were pushed to the head branch.
 func (c Config) prSynchronized(ctx workflow.Context, event github.PullRequestEvent) error {
 	// If we're not tracking this PR, there's no need/way to announce this event.
-	channelID, found := lookupChannel(ctx, event.PullRequest)
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
 	if !found {
 		return nil
 	}
 
+	// Keep the PR's head commit up to date, to associate check and status events with this PR.
+	github.StorePRSnapshot(ctx, event.PullRequest)
+	data.StoreDiffstat(ctx, event.PullRequest.HTMLURL, github.Diffstat(ctx, event))
+
 	email := users.GitHubIDToEmail(ctx, event.Sender.Login)
 	data.UpdateActivityTime(ctx, c.TemporalOpts, event.PullRequest.HTMLURL, email)
-	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest.PullRequest, nil, channelID)
 
 	if event.After == nil {
 		logger.From(ctx).Warn("'after' field in GitHub PR synchronize event is nil")
@@ -328,6 +349,150 @@ func (c Config) prSynchronized(ctx workflow.Context, event github.PullRequestEve
 	return nil
 }
 
+// prAutoMerge announces that auto-merge was enabled or disabled for a PR. For more information,
+// see "Automatically merging a pull request":
+// https://docs.github.com/en/pull-requests/collaborating-with-pull-requests/incorporating-changes-from-a-pull-request/automatically-merging-a-pull-request
+func (c Config) prAutoMerge(ctx workflow.Context, event github.PullRequestEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
+	if !found {
+		return nil
+	}
+
+	snapshot := github.StorePRSnapshot(ctx, event.PullRequest)
+	defer github.UpdateChannelMergeBookmark(ctx, channelID, event.PullRequest.PullRequest, snapshot.MergeQueueState)
+
+	msg := ":robot_face: %s disabled auto-merge for this PR" + reasonSuffix(event.Reason) + "."
+	if event.Action == "auto_merge_enabled" {
+		msg = ":robot_face: %s enabled auto-merge for this PR"
+		if am := event.PullRequest.AutoMerge; am != nil && am.MergeMethod != "" {
+			msg += fmt.Sprintf(" (merge method: `%s`)", am.MergeMethod)
+		}
+		msg += "."
+	}
+
+	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
+	return nil
+}
+
+// prMergeQueue announces that a PR was added to or removed from the merge queue. For more information,
+// see "Merging a pull request with a merge queue":
+// https://docs.github.com/en/pull-requests/collaborating-with-pull-requests/incorporating-changes-from-a-pull-request/merging-a-pull-request-with-a-merge-queue
+func (c Config) prMergeQueue(ctx workflow.Context, event github.PullRequestEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event. This also covers
+	// PRs which are dequeued because they were merged: that is announced when the PR is closed.
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
+	if !found {
+		return nil
+	}
+
+	state, msg := "queued", ":train: %s added this PR to the merge queue."
+	if event.Action == "dequeued" {
+		state, msg = "", ":no_entry: %s removed this PR from the merge queue"+reasonSuffix(event.Reason)+"."
+	}
+
+	github.StoreMergeQueueState(ctx, event.PullRequest, state)
+	defer github.UpdateChannelMergeBookmark(ctx, channelID, event.PullRequest.PullRequest, state)
+
+	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
+	return nil
+}
+
+// prLabeled announces that a label was added to or removed from a PR. Labels which are mapped to the "skip"
+// rule (see the "github-label-rules" flag) also stop or restart the tracking of the PR in a Slack channel.
+// For more information, see "Managing labels":
+// https://docs.github.com/en/issues/using-labels-and-milestones-to-track-work/managing-labels
+func (c Config) prLabeled(ctx workflow.Context, event github.PullRequestEvent) error {
+	if event.Label == nil {
+		logger.From(ctx).Warn("'label' field in GitHub PR label event is nil")
+		return nil
+	}
+
+	name := event.Label.Name
+	skip := c.LabelRules[strings.ToLower(name)] == config.LabelRuleSkip
+
+	// If we're not tracking this PR, there's no need/way to announce this event,
+	// but this event may mean that we need to start tracking the PR (again).
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
+	if !found {
+		if skip && event.Action == "unlabeled" && event.PullRequest.State == "open" {
+			return c.prOpened(ctx, event)
+		}
+		return nil
+	}
+
+	github.StorePRSnapshot(ctx, event.PullRequest)
+
+	// Label names may contain any character, including Slack's control characters and backticks.
+	label := markdown.EscapeSlack(strings.ReplaceAll(name, "`", "'"))
+	msg := ":label: %s added the label `" + label + "` to this PR."
+	if event.Action == "unlabeled" {
+		msg = ":label: %s removed the label `" + label + "` from this PR."
+	}
+	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
+
+	if !skip || event.Action != "labeled" {
+		return nil
+	}
+
+	prURL := event.PullRequest.HTMLURL
+	_ = activities.PostMessage(ctx, channelID, ":no_bell: RevChat is configured to skip PRs with this label, so this channel will be archived.")
+	data.CleanupPRData(ctx, channelID, prURL)
+
+	if err := activities.ArchiveChannel(ctx, channelID, prURL); err != nil {
+		err = errors.Join(err, activities.PostMessage(ctx, channelID, ":boom: Failed to archive this channel."))
+		return activities.AlertError(ctx, c.SlackAlertsChannel, "failed to archive Slack channel for "+prURL, err)
+	}
+
+	return nil
+}
+
+// prMilestoned announces that a PR was added to or removed from a milestone. For more information,
+// see "About milestones":
+// https://docs.github.com/en/issues/using-labels-and-milestones-to-track-work/about-milestones
+func (c Config) prMilestoned(ctx workflow.Context, event github.PullRequestEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest.PullRequest)
+	if !found {
+		return nil
+	}
+
+	github.StorePRSnapshot(ctx, event.PullRequest)
+
+	m := event.Milestone
+	if m == nil {
+		m = event.PullRequest.Milestone
+	}
+	if m == nil {
+		logger.From(ctx).Warn("'milestone' field in GitHub PR milestone event is nil")
+		return nil
+	}
+
+	title := markdown.EscapeSlack(m.Title)
+	msg := fmt.Sprintf(":triangular_flag_on_post: %%s added this PR to the milestone <%s|%s>.", m.HTMLURL, title)
+	if event.Action == "demilestoned" {
+		msg = fmt.Sprintf(":triangular_flag_on_post: %%s removed this PR from the milestone <%s|%s>.", m.HTMLURL, title)
+	}
+	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
+
+	return nil
+}
+
+// reasonSuffix converts an optional reason in a GitHub PR event into a human-readable message
+// suffix. Enum-style reasons (e.g. "CI_FAILURE") are converted to lowercase words.
+func reasonSuffix(reason *string) string {
+	if reason == nil || strings.TrimSpace(*reason) == "" {
+		return ""
+	}
+
+	r := strings.TrimSpace(*reason)
+	if r == strings.ToUpper(r) {
+		r = strings.ReplaceAll(strings.ToLower(r), "_", " ")
+	}
+
+	return fmt.Sprintf(" (reason: _%s_)", r)
+}
+
 // prLocked announces that conversation on a PR was locked or unlocked. For more information, see "Locking conversations":
 // https://docs.github.com/en/communities/moderating-comments-and-conversations/locking-conversations
 func prLocked(ctx workflow.Context, event github.PullRequestEvent) error {
@@ -3,23 +3,28 @@ package workflows
 import (
 	"errors"
 	"log/slog"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/github"
+	"github.com/tzrikka/revchat/pkg/markdown"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/users"
 )
 
 // PullRequestReviewWorkflow is an entrypoint to mirror all GitHub pull request review events in the PR's
 // Slack channel: https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request_review
-func PullRequestReviewWorkflow(ctx workflow.Context, event github.PullRequestReviewEvent) error {
+func (c Config) PullRequestReviewWorkflow(ctx workflow.Context, event github.PullRequestReviewEvent) error {
 	switch event.Action {
 	case "submitted":
-		return prReviewSubmitted(ctx)
+		return c.prReviewSubmitted(ctx, event)
 	case "edited":
-		return prReviewEdited(ctx)
+		return prReviewEdited(ctx, event)
 	case "dismissed":
-		return prReviewDismissed(ctx)
+		return c.prReviewDismissed(ctx, event)
 	default:
 		logger.From(ctx).Error("unrecognized GitHub PR review event action", slog.String("action", event.Action))
 		return errors.New("unrecognized GitHub PR review event action: " + event.Action)
@@ -28,19 +33,126 @@ func PullRequestReviewWorkflow(ctx workflow.Context, event github.PullRequestRev
 
 // A review on a pull request was submitted. This is interesting when
 // the review state is "approved", and/or the review body isn't empty.
-func prReviewSubmitted(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review submitted event - not implemented yet")
-	return nil
+func (c Config) prReviewSubmitted(ctx workflow.Context, event github.PullRequestReviewEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	prURL := event.PullRequest.HTMLURL
+	email := users.GitHubIDToEmail(ctx, event.Review.User.Login)
+	var err error
+
+	// Don't abort in case of errors in any of the following cases, it's important
+	// to handle and announce them even if our internal state becomes stale.
+	switch strings.ToLower(event.Review.State) {
+	case "approved":
+		github.MentionUserInMsg(ctx, channelID, event.Review.User, "%s approved this PR. :+1:")
+
+		err = data.RemoveReviewerFromTurns(ctx, c.TemporalOpts, prURL, email, true)
+		if err != nil {
+			_ = activities.AlertError(ctx, c.SlackAlertsChannel, "failed to remove approver from PR turns", err, "Email", email)
+		}
+
+	case "changes_requested":
+		github.MentionUserInMsg(ctx, channelID, event.Review.User, "%s requested changes in this PR. :warning:")
+		err = data.SwitchTurn(ctx, c.TemporalOpts, prURL, email, false)
+
+	// Review comments are mirrored individually, so there's nothing to announce here except the review body (if any).
+	case "commented":
+		err = data.SwitchTurn(ctx, c.TemporalOpts, prURL, email, false)
+
+	default:
+		logger.From(ctx).Error("unrecognized GitHub PR review state", slog.String("state", event.Review.State))
+		err = errors.New("unrecognized GitHub PR review state: " + event.Review.State)
+		return activities.AlertError(ctx, c.SlackAlertsChannel, "", err)
+	}
+
+	body := strings.TrimSpace(event.Review.Body)
+	if body == "" {
+		return err
+	}
+
+	msg := markdown.GitHubToSlack(ctx, body, event.Review.HTMLURL)
+	return errors.Join(err, github.ImpersonateUserInMsg(ctx, event.Review.HTMLURL, channelID, event.Review.User, msg, nil))
 }
 
 // The body comment on a pull request review was edited.
-func prReviewEdited(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review edited event - not implemented yet")
-	return nil
+func prReviewEdited(ctx workflow.Context, event github.PullRequestReviewEvent) error {
+	// If we're not tracking this PR, there's no need/way to mirror this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	// If the review body was edited in Slack, don't try to update it there again.
+	if strings.HasSuffix(event.Review.Body, "\n\n[This comment was updated by RevChat]: #") {
+		logger.From(ctx).Debug("ignoring self-triggered GitHub event")
+		return nil
+	}
+
+	reviewURL := event.Review.HTMLURL
+	body := strings.TrimSpace(event.Review.Body)
+
+	// If the review body was empty when it was submitted, there's no Slack message to update yet.
+	if ids, _ := data.SwitchURLAndID(ctx, reviewURL); ids == "" {
+		if body == "" {
+			return nil
+		}
+		msg := markdown.GitHubToSlack(ctx, body, reviewURL)
+		return github.ImpersonateUserInMsg(ctx, reviewURL, channelID, event.Review.User, msg, nil)
+	}
+
+	if body == "" {
+		return github.DeleteSlackMsg(ctx, reviewURL)
+	}
+
+	return github.EditSlackMsg(ctx, reviewURL, markdown.GitHubToSlack(ctx, body, reviewURL))
 }
 
-// A review on a pull request was dismissed.
-func prReviewDismissed(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review dismissed event - not implemented yet")
-	return nil
+// A review on a pull request was dismissed. If it was an approval,
+// it's the reviewer's turn again, just like before they approved.
+func (c Config) prReviewDismissed(ctx workflow.Context, event github.PullRequestReviewEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	// Don't use fmt.Sprintf() here to avoid issues with % signs in the display name.
+	reviewer := github.SlackDisplayName(ctx, event.Review.User)
+	msg := "%s dismissed <" + event.Review.HTMLURL + "|the review> of " + reviewer + ". :-1:"
+	github.MentionUserInMsg(ctx, channelID, event.Sender, msg)
+
+	prURL := event.PullRequest.HTMLURL
+	data.UpdateActivityTime(ctx, c.TemporalOpts, prURL, users.GitHubIDToEmail(ctx, event.Sender.Login))
+
+	// If the reviewer isn't opted-in, don't add them back to the PR's attention
+	// state (just like the logic in other places, e.g. PR creation and PR updates).
+	email := users.GitHubIDToEmail(ctx, event.Review.User.Login)
+	if user := data.SelectUserByEmail(ctx, email); !user.IsOptedIn() {
+		return nil
+	}
+
+	// Only dismissed approvals give the turn back to the reviewer. GitHub reports the state of
+	// dismissed reviews as "dismissed", so we rely on the PR's attention state instead: approvals
+	// remove reviewers from it, while other reviews (i.e. change requests) leave them there.
+	if isAuthor, isReviewer := data.LoadParticipantRoles(ctx, c.TemporalOpts, prURL, email); isAuthor || isReviewer {
+		return nil
+	}
+	// Nudges don't affect untracked reviewers, they only report whether they approved the PR.
+	if _, approved, err := data.SetReviewerTurn(ctx, c.TemporalOpts, prURL, email, true); err != nil || !approved {
+		return err
+	}
+
+	_, _, err := data.SetReviewerTurn(ctx, c.TemporalOpts, prURL, email, false)
+	if err != nil {
+		_ = activities.AlertError(ctx, c.SlackAlertsChannel, "failed to restore reviewer's turn in PR", err, "Email", email)
+	}
+	return err
 }
@@ -2,24 +2,30 @@ package workflows
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/github"
+	"github.com/tzrikka/revchat/pkg/markdown"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/users"
 )
 
 // PullRequestReviewCommentWorkflow is an entrypoint to mirror all GitHub pull request review comment events in the
 // PR's Slack channel: https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request_review_comment
-func PullRequestReviewCommentWorkflow(ctx workflow.Context, event github.PullRequestReviewCommentEvent) error {
+func (c Config) PullRequestReviewCommentWorkflow(ctx workflow.Context, event github.PullRequestReviewCommentEvent) error {
 	switch event.Action {
 	case "created":
-		return prReviewCommentCreated(ctx)
+		return c.prReviewCommentCreated(ctx, event)
 	case "edited":
-		return prReviewCommentEdited(ctx)
+		return prReviewCommentEdited(ctx, event)
 	case "deleted":
-		return prReviewCommentDeleted(ctx)
+		return prReviewCommentDeleted(ctx, event)
 	default:
 		logger.From(ctx).Error("unrecognized GitHub PR review comment event action", slog.String("action", event.Action))
 		return errors.New("unrecognized GitHub PR review comment event action: " + event.Action)
@@ -27,19 +33,92 @@ func PullRequestReviewCommentWorkflow(ctx workflow.Context, event github.PullReq
 }
 
 // A comment on a pull request diff was created.
-func prReviewCommentCreated(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review comment created event - not implemented yet")
-	return nil
+func (c Config) prReviewCommentCreated(ctx workflow.Context, event github.PullRequestReviewCommentEvent) error {
+	// If we're not tracking this PR, there's no need/way to mirror this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	// Don't abort if this fails - it's more important to post the comment.
+	prURL := event.PullRequest.HTMLURL
+	_ = data.SwitchTurn(ctx, c.TemporalOpts, prURL, users.GitHubIDToEmail(ctx, event.Comment.User.Login), false)
+
+	// If the comment was created by RevChat, i.e. mirrored from Slack, don't repost it.
+	if strings.HasSuffix(event.Comment.Body, "\n\n[This comment was created by RevChat]: #") {
+		logger.From(ctx).Debug("ignoring self-triggered GitHub event")
+		return nil
+	}
+
+	comment := event.Comment
+	msg := markdown.GitHubToSlack(ctx, comment.Body, prURL)
+	if comment.InReplyTo == nil {
+		msg, diff := github.BeautifyInlineComment(ctx, comment, msg)
+		return github.ImpersonateUserInMsg(ctx, comment.HTMLURL, channelID, comment.User, msg, diff)
+	}
+
+	parentURL := fmt.Sprintf("%s#discussion_r%d", prURL, *comment.InReplyTo)
+	return github.ImpersonateUserInReply(ctx, comment.HTMLURL, parentURL, comment.User, msg, nil)
 }
 
 // The content of a comment on a pull request diff was changed.
-func prReviewCommentEdited(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review comment edited event - not implemented yet")
-	return nil
+func prReviewCommentEdited(ctx workflow.Context, event github.PullRequestReviewCommentEvent) error {
+	// If we're not tracking this PR, there's no need/way to mirror this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	// If the comment was edited in Slack, don't try to update it there again.
+	if strings.HasSuffix(event.Comment.Body, "\n\n[This comment was updated by RevChat]: #") {
+		logger.From(ctx).Debug("ignoring self-triggered GitHub event")
+		return nil
+	}
+
+	// If the comment previously had an attached diff file, delete it - it's obsolete now.
+	// Also forget it explicitly, even if the deletion failed, so future edits won't retry.
+	comment := event.Comment
+	if fileID, _ := data.SwitchURLAndID(ctx, comment.HTMLURL+"/slack_file_id"); fileID != "" {
+		activities.DeleteFile(ctx, fileID)
+		data.DeleteURLAndIDMapping(ctx, comment.HTMLURL+"/slack_file_id")
+	}
+
+	msg := markdown.GitHubToSlack(ctx, comment.Body, event.PullRequest.HTMLURL)
+	if comment.InReplyTo == nil {
+		var diff []byte
+		msg, diff = github.BeautifyInlineComment(ctx, comment, msg)
+
+		// We can't upload a file to an existing impersonated message - that would disable future updates/deletion
+		// of that message. We also can't replace an existing file attachment with a new upload in a seamless way.
+		// So we keep the diff block in the message, and mention the user instead of impersonating them.
+		if diff != nil {
+			// Don't use fmt.Sprintf() here to avoid issues with % signs in the diff.
+			msg = strings.Replace(github.ImpersonationToMention(msg), "%s", github.SlackDisplayName(ctx, comment.User), 1)
+		}
+	}
+
+	return github.EditSlackMsg(ctx, comment.HTMLURL, msg)
 }
 
 // A comment on a pull request diff was deleted.
-func prReviewCommentDeleted(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review comment deleted event - not implemented yet")
-	return nil
+func prReviewCommentDeleted(ctx workflow.Context, event github.PullRequestReviewCommentEvent) error {
+	// If we're not tracking this PR, there's no need/way to mirror this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	commentURL := event.Comment.HTMLURL
+	if fileID, _ := data.SwitchURLAndID(ctx, commentURL+"/slack_file_id"); fileID != "" {
+		activities.DeleteFile(ctx, fileID)
+		data.DeleteURLAndIDMapping(ctx, commentURL+"/slack_file_id")
+	}
+
+	return github.DeleteSlackMsg(ctx, commentURL)
 }
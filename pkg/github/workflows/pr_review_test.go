@@ -0,0 +1,129 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/github"
+)
+
+const testPRURL = "https://github.com/org/repo/pull/1"
+
+// dismissedReviewEvent is a trimmed-down "pull_request_review" webhook payload: GitHub reports the
+// state of dismissed reviews as "dismissed", regardless of whether they were approvals or not.
+const dismissedReviewEvent = `{
+	"action": "dismissed",
+	"review": {
+		"id": 2,
+		"user": {"login": "bob", "id": 2, "type": "User"},
+		"body": "",
+		"state": "dismissed",
+		"html_url": "https://github.com/org/repo/pull/1#pullrequestreview-2",
+		"submitted_at": "2026-01-01T00:00:00Z"
+	},
+	"pull_request": {
+		"html_url": "https://github.com/org/repo/pull/1",
+		"number": 1,
+		"state": "open",
+		"title": "Title",
+		"user": {"login": "alice", "id": 1, "type": "User"}
+	},
+	"sender": {"login": "alice", "id": 1, "type": "User"}
+}`
+
+// newReviewTestEnv returns a test environment for [Config.PullRequestReviewWorkflow], with
+// a tracked PR, authored by "alice", in which "bob" is an opted-in reviewer. The Slack stubs
+// don't do anything, because the test cases check only the PR's attention state.
+func newReviewTestEnv(t *testing.T) *testsuite.TestWorkflowEnvironment {
+	t.Helper()
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if err := data.UpsertUser(nil, "alice@example.com", "Alice", "", "alice", "U1", "link"); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if err := data.UpsertUser(nil, "bob@example.com", "Bob", "", "bob", "U2", "link"); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if err := data.MapURLAndID(nil, testPRURL, "C1"); err != nil {
+		t.Fatalf("MapURLAndID() error = %v", err)
+	}
+	data.InitTurns(nil, testPRURL, "alice@example.com")
+	if _, _, err := data.SetReviewerTurn(nil, client.Options{}, testPRURL, "bob@example.com", false); err != nil {
+		t.Fatalf("SetReviewerTurn() error = %v", err)
+	}
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(Config{}.PullRequestReviewWorkflow, workflow.RegisterOptions{Name: "github.pull_request_review"})
+
+	stub := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	}
+	for _, name := range []string{"slack.bookmarks.list", "slack.chat.postMessage", "slack.auth.test"} {
+		env.RegisterActivityWithOptions(stub, activity.RegisterOptions{Name: name})
+	}
+	usersInfo := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+		return map[string]any{"ok": true, "user": map[string]any{"profile": map[string]any{"display_name": "Bob"}}}, nil
+	}
+	env.RegisterActivityWithOptions(usersInfo, activity.RegisterOptions{Name: "slack.users.info"})
+
+	return env
+}
+
+func TestPRReviewDismissed(t *testing.T) {
+	tests := []struct {
+		name     string
+		approved bool
+		want     []string
+	}{
+		{
+			name:     "dismissed_approval",
+			approved: true,
+			want:     []string{"bob@example.com"},
+		},
+		{
+			name: "dismissed_changes_request",
+			want: []string{"bob@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newReviewTestEnv(t)
+			if tt.approved {
+				if err := data.RemoveReviewerFromTurns(nil, client.Options{}, testPRURL, "bob@example.com", true); err != nil {
+					t.Fatalf("RemoveReviewerFromTurns() error = %v", err)
+				}
+			}
+
+			var event github.PullRequestReviewEvent
+			if err := json.Unmarshal([]byte(dismissedReviewEvent), &event); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			env.ExecuteWorkflow("github.pull_request_review", event)
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("PullRequestReviewWorkflow() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("PullRequestReviewWorkflow() error = %v", err)
+			}
+
+			got, err := data.LoadCurrentTurnEmails(nil, client.Options{}, testPRURL)
+			if err != nil {
+				t.Fatalf("LoadCurrentTurnEmails() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LoadCurrentTurnEmails() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,17 +7,20 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/github"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/users"
 )
 
 // PullRequestReviewThreadWorkflow is an entrypoint to mirror all GitHub pull request review thread (i.e. comment resolution)
 // events in the PR's Slack channel: https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request_review_thread
-func PullRequestReviewThreadWorkflow(ctx workflow.Context, event github.PullRequestReviewThreadEvent) error {
+func (c Config) PullRequestReviewThreadWorkflow(ctx workflow.Context, event github.PullRequestReviewThreadEvent) error {
 	switch event.Action {
 	case "resolved":
-		return reviewThreadResolved(ctx)
+		return c.reviewThreadResolved(ctx, event)
 	case "unresolved":
-		return reviewThreadUnresolved(ctx)
+		return c.reviewThreadUnresolved(ctx, event)
 	default:
 		logger.From(ctx).Error("unrecognized GitHub PR review thread event action", slog.String("action", event.Action))
 		return errors.New("unrecognized GitHub PR review thread event action: " + event.Action)
@@ -25,13 +28,54 @@ func PullRequestReviewThreadWorkflow(ctx workflow.Context, event github.PullRequ
 }
 
 // A comment thread on a pull request was marked as resolved.
-func reviewThreadResolved(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review thread resolved - event handler not implemented yet")
-	return nil
+func (c Config) reviewThreadResolved(ctx workflow.Context, event github.PullRequestReviewThreadEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	url := threadURL(event)
+	if url == "" {
+		logger.From(ctx).Warn("GitHub PR review thread without comments", slog.String("node_id", event.Thread.NodeID))
+		return nil
+	}
+
+	data.UpdateActivityTime(ctx, c.TemporalOpts, event.PullRequest.HTMLURL, users.GitHubIDToEmail(ctx, event.Sender.Login))
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	activities.AddOKReaction(ctx, url) // The mention below is more important than this reaction.
+
+	return github.MentionUserInReply(ctx, url, event.Sender, "%s resolved this comment. :ok:")
 }
 
 // A previously resolved comment thread on a pull request was marked as unresolved.
-func reviewThreadUnresolved(ctx workflow.Context) error {
-	logger.From(ctx).Warn("GitHub PR review thread unresolved - event handler not implemented yet")
-	return nil
+func (c Config) reviewThreadUnresolved(ctx workflow.Context, event github.PullRequestReviewThreadEvent) error {
+	// If we're not tracking this PR, there's no need/way to announce this event.
+	channelID, found := lookupChannel(ctx, event.PullRequest)
+	if !found {
+		return nil
+	}
+
+	url := threadURL(event)
+	if url == "" {
+		logger.From(ctx).Warn("GitHub PR review thread without comments", slog.String("node_id", event.Thread.NodeID))
+		return nil
+	}
+
+	data.UpdateActivityTime(ctx, c.TemporalOpts, event.PullRequest.HTMLURL, users.GitHubIDToEmail(ctx, event.Sender.Login))
+	defer github.UpdateChannelBookmarks(ctx, &event.PullRequest, nil, channelID)
+
+	activities.RemoveOKReaction(ctx, url) // The mention below is more important than this reaction.
+
+	return github.MentionUserInReply(ctx, url, event.Sender, "%s reopened this comment. :no_good:")
+}
+
+// threadURL returns the URL of the first comment in a PR review thread, which
+// is the one that's mapped to the root message of the thread in Slack.
+func threadURL(event github.PullRequestReviewThreadEvent) string {
+	if len(event.Thread.Comments) == 0 {
+		return ""
+	}
+	return event.Thread.Comments[0].HTMLURL
 }
@@ -0,0 +1,149 @@
+package workflows
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/github"
+)
+
+const testThreadURL = testPRURL + "#discussion_r1"
+
+func TestThreadURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		thread github.Thread
+		want   string
+	}{
+		{
+			name: "no_comments",
+		},
+		{
+			name: "single_comment",
+			thread: github.Thread{Comments: []github.PullComment{
+				{HTMLURL: testThreadURL},
+			}},
+			want: testThreadURL,
+		},
+		{
+			name: "multiple_comments",
+			thread: github.Thread{Comments: []github.PullComment{
+				{HTMLURL: testThreadURL},
+				{HTMLURL: testPRURL + "#discussion_r2"},
+			}},
+			want: testThreadURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := threadURL(github.PullRequestReviewThreadEvent{Thread: tt.thread}); got != tt.want {
+				t.Errorf("threadURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequestReviewThreadWorkflow(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    string
+		state     string
+		comments  []github.PullComment
+		wantCalls []string
+	}{
+		{
+			name:      "resolved",
+			action:    "resolved",
+			state:     "open",
+			comments:  []github.PullComment{{HTMLURL: testThreadURL}},
+			wantCalls: []string{"slack.reactions.add", "slack.chat.postMessage C1/T1: <https://github.com/bob?preview=no|@Bob> resolved this comment. :ok:"},
+		},
+		{
+			name:      "unresolved",
+			action:    "unresolved",
+			state:     "open",
+			comments:  []github.PullComment{{HTMLURL: testThreadURL}},
+			wantCalls: []string{"slack.reactions.remove", "slack.chat.postMessage C1/T1: <https://github.com/bob?preview=no|@Bob> reopened this comment. :no_good:"},
+		},
+		{
+			name:     "thread_without_comments",
+			action:   "resolved",
+			state:    "open",
+			comments: []github.PullComment{},
+		},
+		{
+			name:     "closed_pr",
+			action:   "resolved",
+			state:    "closed",
+			comments: []github.PullComment{{HTMLURL: testThreadURL}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			if err := data.UpsertUser(nil, "bob@example.com", "Bob", "", "bob", "U2", ""); err != nil {
+				t.Fatalf("UpsertUser() error = %v", err)
+			}
+			if err := data.MapURLAndID(nil, testPRURL, "C1"); err != nil {
+				t.Fatalf("MapURLAndID() error = %v", err)
+			}
+			if err := data.MapURLAndID(nil, testThreadURL, "C1/T1"); err != nil {
+				t.Fatalf("MapURLAndID() error = %v", err)
+			}
+			data.InitTurns(nil, testPRURL, "alice@example.com")
+
+			s := testsuite.WorkflowTestSuite{}
+			env := s.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(Config{}.PullRequestReviewThreadWorkflow,
+				workflow.RegisterOptions{Name: "github.pull_request_review_thread"})
+
+			calls := []string{}
+			for _, name := range []string{"slack.reactions.add", "slack.reactions.remove"} {
+				env.RegisterActivityWithOptions(func(_ context.Context, _ map[string]any) (map[string]any, error) {
+					calls = append(calls, name)
+					return map[string]any{"ok": true}, nil
+				}, activity.RegisterOptions{Name: name})
+			}
+			postMessage := func(_ context.Context, req map[string]any) (map[string]any, error) {
+				calls = append(calls, "slack.chat.postMessage "+req["channel"].(string)+"/"+req["thread_ts"].(string)+": "+req["text"].(string))
+				return map[string]any{"ok": true, "channel": "C1", "ts": "T2"}, nil
+			}
+			env.RegisterActivityWithOptions(postMessage, activity.RegisterOptions{Name: "slack.chat.postMessage"})
+			stub := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+				return map[string]any{"ok": true}, nil
+			}
+			for _, name := range []string{"slack.bookmarks.list", "slack.auth.test"} {
+				env.RegisterActivityWithOptions(stub, activity.RegisterOptions{Name: name})
+			}
+			usersInfo := func(_ context.Context, _ map[string]any) (map[string]any, error) {
+				return map[string]any{"ok": true, "user": map[string]any{"profile": map[string]any{"display_name": "Bob"}}}, nil
+			}
+			env.RegisterActivityWithOptions(usersInfo, activity.RegisterOptions{Name: "slack.users.info"})
+
+			env.ExecuteWorkflow("github.pull_request_review_thread", github.PullRequestReviewThreadEvent{
+				Action:      tt.action,
+				Thread:      github.Thread{NodeID: "PRRT_1", Comments: tt.comments},
+				PullRequest: github.PullRequest{HTMLURL: testPRURL, State: tt.state},
+				Sender:      github.User{Login: "bob", HTMLURL: "https://github.com/bob"},
+			})
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("PullRequestReviewThreadWorkflow() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("PullRequestReviewThreadWorkflow() error = %v", err)
+			}
+
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("PullRequestReviewThreadWorkflow() calls = %q, want %q", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package workflows
+
+import (
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/github"
+	ghactivities "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// autoAddReviewers requests reviews from a minimal set of available code owners in a new PR, on behalf of its
+// author, if this is enabled for the PR's repository (see the "reviewers-auto-add-repos" flag). The resulting
+// "review_requested" events announce the new reviewers and add them to the PR's Slack channel, as usual.
+func (c Config) autoAddReviewers(ctx workflow.Context, channelID string, pr github.PullRequest) {
+	owner, repo, found := strings.Cut(pr.Base.Repo.FullName, "/")
+	if !found || !c.AutoAddReviewersRepos[strings.ToLower(pr.Base.Repo.FullName)] {
+		return
+	}
+
+	suggestions, err := slack.SuggestReviewers(ctx, c.TemporalOpts, pr.HTMLURL, c.ReviewersMaxLoad)
+	if err != nil {
+		return
+	}
+
+	var logins []string
+	for _, name := range suggestions.Owners {
+		if slices.Contains(suggestions.Reviewers, name) {
+			continue
+		}
+		// Skip users without a known GitHub login: code owners are identified by their real names
+		// (see [slack.ReviewerSuggestions]), which aren't necessarily valid logins, even if they
+		// look like ones (i.e. GitHub logins of unknown users in the "CODEOWNERS" file).
+		if login := data.SelectUserByRealName(ctx, name).GitHubID; login != "" {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return
+	}
+
+	if !c.ReviewerRequests {
+		_ = activities.PostMessage(ctx, channelID, ":information_source: Code owners were not added automatically "+
+			"as reviewers, because this is not enabled in GitHub PRs yet. Use the `suggest` slash command to list them.")
+		return
+	}
+
+	thrippyID := data.SelectUserByGitHubID(ctx, pr.User.Login).ThrippyLink
+	if thrippyID == "" {
+		_ = activities.PostMessage(ctx, channelID, ":information_source: Code owners were not added automatically "+
+			"as reviewers, because the PR author is not opted-in. Use the `suggest` slash command to list them.")
+		return
+	}
+
+	if err := ghactivities.RequestReviewers(ctx, thrippyID, owner, repo, pr.Number, logins); err != nil {
+		_ = activities.PostMessage(ctx, channelID, ":warning: Failed to add code owners automatically as reviewers.")
+	}
+}
@@ -26,7 +26,14 @@ type Config struct {
 	SlackChannelNameMaxLength int
 	SlackChannelsArePrivate   bool
 
-	LinkifyMap map[string]string
+	AutoAddReviewersRepos map[string]bool
+	ReviewersMaxLoad      int
+	MinApprovals          int
+
+	LinkifyMap       map[string]string
+	LabelRules       map[string]string
+	ListReviews      bool
+	ReviewerRequests bool
 
 	TemporalOpts client.Options
 }
@@ -38,7 +45,14 @@ func newConfig(cmd *cli.Command, temporalOpts client.Options) *Config {
 		SlackChannelNameMaxLength: cmd.Int("slack-channel-name-max-length"),
 		SlackChannelsArePrivate:   cmd.Bool("slack-private-channels"),
 
-		LinkifyMap: config.KVSliceToMap(cmd.StringSlice("linkification-map")),
+		AutoAddReviewersRepos: config.RepoSet(cmd.StringSlice("reviewers-auto-add-repos")),
+		ReviewersMaxLoad:      cmd.Int("reviewers-max-load"),
+		MinApprovals:          cmd.Int("reviewers-min-approvals"),
+
+		LinkifyMap:       config.KVSliceToMap(cmd.StringSlice("linkification-map")),
+		LabelRules:       config.LabelRules(cmd.StringSlice("github-label-rules")),
+		ListReviews:      cmd.Bool("github-list-reviews"),
+		ReviewerRequests: cmd.Bool("github-reviewer-requests"),
 
 		TemporalOpts: temporalOpts,
 	}
@@ -58,16 +72,24 @@ var Signals = []string{
 	"github.events.pull_request_review_thread",
 
 	"github.events.issue_comment",
+
+	"github.events.check_run",
+	"github.events.check_suite",
+	"github.events.status",
 }
 
 // RegisterWorkflows maps event-handling workflow functions to [Signals].
 func RegisterWorkflows(cmd *cli.Command, temporalOpts client.Options, w worker.Worker) {
 	c := newConfig(cmd, temporalOpts)
 	w.RegisterWorkflowWithOptions(c.PullRequestWorkflow, workflow.RegisterOptions{Name: Signals[0]})
-	w.RegisterWorkflowWithOptions(PullRequestReviewWorkflow, workflow.RegisterOptions{Name: Signals[1]})
-	w.RegisterWorkflowWithOptions(PullRequestReviewCommentWorkflow, workflow.RegisterOptions{Name: Signals[2]})
-	w.RegisterWorkflowWithOptions(PullRequestReviewThreadWorkflow, workflow.RegisterOptions{Name: Signals[3]})
+	w.RegisterWorkflowWithOptions(c.PullRequestReviewWorkflow, workflow.RegisterOptions{Name: Signals[1]})
+	w.RegisterWorkflowWithOptions(c.PullRequestReviewCommentWorkflow, workflow.RegisterOptions{Name: Signals[2]})
+	w.RegisterWorkflowWithOptions(c.PullRequestReviewThreadWorkflow, workflow.RegisterOptions{Name: Signals[3]})
 	w.RegisterWorkflowWithOptions(c.IssueCommentWorkflow, workflow.RegisterOptions{Name: Signals[4]})
+
+	w.RegisterWorkflowWithOptions(c.CheckRunWorkflow, workflow.RegisterOptions{Name: Signals[5]})
+	w.RegisterWorkflowWithOptions(c.CheckSuiteWorkflow, workflow.RegisterOptions{Name: Signals[6]})
+	w.RegisterWorkflowWithOptions(c.StatusWorkflow, workflow.RegisterOptions{Name: Signals[7]})
 }
 
 // RegisterSignals routes [Signals] to their registered workflows.
@@ -102,6 +124,10 @@ func RegisterSignals(ctx workflow.Context, sel workflow.Selector) {
 	addReceive[github.PullRequestReviewCommentEvent](ctx, sel, Signals[2])
 	addReceive[github.PullRequestReviewThreadEvent](ctx, sel, Signals[3])
 	addReceive[github.IssueCommentEvent](ctx, sel, Signals[4])
+
+	addReceive[github.CheckRunEvent](ctx, sel, Signals[5])
+	addReceive[github.CheckSuiteEvent](ctx, sel, Signals[6])
+	addReceive[github.StatusEvent](ctx, sel, Signals[7])
 }
 
 func addReceive[T any](ctx workflow.Context, sel workflow.Selector, signalName string) {
@@ -129,6 +155,10 @@ func DrainSignals(ctx workflow.Context) bool {
 	totalEvents += receiveAsync[github.PullRequestReviewCommentEvent](ctx, Signals[2])
 	totalEvents += receiveAsync[github.PullRequestReviewThreadEvent](ctx, Signals[3])
 	totalEvents += receiveAsync[github.IssueCommentEvent](ctx, Signals[4])
+
+	totalEvents += receiveAsync[github.CheckRunEvent](ctx, Signals[5])
+	totalEvents += receiveAsync[github.CheckSuiteEvent](ctx, Signals[6])
+	totalEvents += receiveAsync[github.StatusEvent](ctx, Signals[7])
 	return totalEvents > 0
 }
 
@@ -174,12 +204,29 @@ func childWorkflowID[T any](ctx workflow.Context, signal string, payload *T) str
 		}
 	case Signals[3]:
 		if event, ok := any(payload).(*github.PullRequestReviewThreadEvent); ok {
-			id = fmt.Sprintf("%s_%s", trimURLPrefix("TODO"), event.Action)
+			url := threadURL(*event)
+			if url == "" {
+				url = event.PullRequest.HTMLURL
+			}
+			id = fmt.Sprintf("%s_%s", trimURLPrefix(url), event.Action)
 		}
 	case Signals[4]:
 		if event, ok := any(payload).(*github.IssueCommentEvent); ok {
 			id = fmt.Sprintf("%s_%s", trimURLPrefix(event.Comment.HTMLURL), event.Action)
 		}
+
+	case Signals[5]:
+		if event, ok := any(payload).(*github.CheckRunEvent); ok {
+			id = fmt.Sprintf("%s_%s", trimURLPrefix(event.CheckRun.HTMLURL), event.Action)
+		}
+	case Signals[6]:
+		if event, ok := any(payload).(*github.CheckSuiteEvent); ok {
+			id = fmt.Sprintf("check_suite_%d_%s", event.CheckSuite.ID, event.Action)
+		}
+	case Signals[7]:
+		if event, ok := any(payload).(*github.StatusEvent); ok {
+			id = fmt.Sprintf("%s/commit/%s_%s", event.Name, event.SHA, event.State)
+		}
 	}
 
 	if id == "" {
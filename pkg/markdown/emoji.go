@@ -68,6 +68,25 @@ var (
 		":thanks:": ":pray:", // Unofficial but common alias in Slack.
 	}
 
+	// GitHubReactions maps GitHub emoji names to the limited set of GitHub reaction types:
+	// https://docs.github.com/en/rest/reactions/reactions?apiVersion=2022-11-28#about-reactions
+	GitHubReactions = map[string]string{
+		":+1:":       "+1",
+		":thumbsup:": "+1",
+
+		":-1:":         "-1",
+		":thumbsdown:": "-1",
+
+		":laughing:": "laugh",
+		":smile:":    "laugh",
+
+		":confused:": "confused",
+		":heart:":    "heart",
+		":tada:":     "hooray",
+		":rocket:":   "rocket",
+		":eyes:":     "eyes",
+	}
+
 	SlackSkinTonePattern = regexp.MustCompile(`:skin-tone-\d:`)
 )
 
@@ -127,6 +146,12 @@ func SlackToGitHubEmoji(text string) string {
 	return trimSlackEmojiSkinTones(text)
 }
 
+// SlackToGitHubReaction converts the name of a Slack reaction (without colons, possibly with a skin tone
+// suffix) to a GitHub reaction type. It returns an empty string if there isn't a corresponding GitHub reaction.
+func SlackToGitHubReaction(name string) string {
+	return GitHubReactions[SlackToGitHubEmoji(":"+name+":")]
+}
+
 // trimSlackEmojiSkinTones removes skin tone suffixes from emoji names,
 // which are supported in Slack but not in Bitbucket or GitHub.
 func trimSlackEmojiSkinTones(text string) string {
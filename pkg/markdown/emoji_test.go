@@ -101,3 +101,27 @@ func TestSkinTones(t *testing.T) {
 		t.Fatalf("SlackToGitHubEmoji(%q) = %q, want %q", text, got, want)
 	}
 }
+
+func TestSlackToGitHubReaction(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "+1", want: "+1"},
+		{name: "+1::skin-tone-2", want: "+1"},
+		{name: "thumbsdown", want: "-1"},
+		{name: "laughing", want: "laugh"},
+		{name: "tada", want: "hooray"},
+		{name: "eyes", want: "eyes"},
+		{name: "robot_face"},
+		{name: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.SlackToGitHubReaction(tt.name); got != tt.want {
+				t.Errorf("SlackToGitHubReaction(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -77,3 +77,9 @@ func ShortenSlackURLs(commentURL, text string) string {
 
 	return text
 }
+
+// EscapeSlack escapes the control characters of Slack in plain text (e.g. names), so it can be embedded in messages:
+// https://docs.slack.dev/messaging/formatting-message-text/#escaping-text
+func EscapeSlack(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
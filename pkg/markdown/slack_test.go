@@ -45,3 +45,30 @@ func TestShortenSlackURLs(t *testing.T) {
 		})
 	}
 }
+
+func TestEscapeSlack(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "plain_text",
+			text: "bug fix",
+			want: "bug fix",
+		},
+		{
+			name: "control_characters",
+			text: "<!here> R&D -> QA",
+			want: "&lt;!here&gt; R&amp;D -&gt; QA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdown.EscapeSlack(tt.text); got != tt.want {
+				t.Errorf("EscapeSlack() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
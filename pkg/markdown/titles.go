@@ -16,6 +16,9 @@ var (
 	linkifyPattern = regexp.MustCompile(`([A-Z]{2,})-\d+`)
 	baseURLPattern = regexp.MustCompile(`^https://([^/]+)/([^/]+)/([^/]+)/(pull|pull-requests)/`)
 	prIDPattern    = regexp.MustCompile(`(([\w-]+/)?([\w-]+))?#(\d+)`)
+
+	prURLPattern    = regexp.MustCompile(`https://[^/\s]+/[^/\s]+/[^/\s]+/(pull|pull-requests)/\d+`)
+	issueURLPattern = regexp.MustCompile(`https://(bitbucket\.org|github\.com)/[^/\s]+/([^/\s]+)/issues/(\d+)`)
 )
 
 // LinkifyTitle finds IDs in the given title of a pull/merge request and tries to replace them with
@@ -74,15 +77,78 @@ func buildLink(ctx workflow.Context, base, id string) string {
 // linkifyPR recognizes "[[org/]repo]#123" as PR references in/near the current repo.
 // Note that in GitHub's case this works for repo issues as well as PRs.
 func linkifyPR(baseURL, id []string) string {
-	if id[2] == "" {
-		id[2] = baseURL[2]
-	} else {
-		id[2], _ = strings.CutSuffix(id[2], "/")
+	return fmt.Sprintf("<%s|%s>", prLink(baseURL, id), id[0])
+}
+
+// prLink returns the URL of a "[[org/]repo]#123" PR reference in/near the current repo.
+func prLink(baseURL, id []string) string {
+	org, repo := baseURL[2], baseURL[3]
+	if id[2] != "" {
+		org, _ = strings.CutSuffix(id[2], "/")
+	}
+	if id[3] != "" {
+		repo = id[3]
+	}
+
+	return fmt.Sprintf("https://%s/%s/%s/%s/%s", baseURL[1], org, repo, baseURL[4], id[4])
+}
+
+// PullRequestURLs finds PR references in the given text: "[[org/]repo]#123" (relative to the given PR
+// URL, or just the repository's PRs base URL, e.g. "https://bitbucket.org/workspace/repo/pull-requests/"),
+// and full PR URLs in the same host.
+// It returns their URLs in order of appearance, without duplicates, or nil if none are found.
+func PullRequestURLs(prURL, text string) []string {
+	baseURL := baseURLPattern.FindStringSubmatch(prURL)
+	if len(baseURL) < 5 {
+		return nil
+	}
+
+	var urls []string
+	done := map[string]bool{}
+	for _, id := range prIDPattern.FindAllStringSubmatch(text, -1) {
+		if url := prLink(baseURL, id); !done[url] {
+			urls = append(urls, url)
+			done[url] = true
+		}
 	}
 
-	if id[3] == "" {
-		id[3] = baseURL[3]
+	for _, url := range prURLPattern.FindAllString(text, -1) {
+		if strings.HasPrefix(url, "https://"+baseURL[1]+"/") && !done[url] {
+			urls = append(urls, url)
+			done[url] = true
+		}
+	}
+
+	return urls
+}
+
+// IssueLink is an issue reference which was found by [IssueLinks].
+type IssueLink struct {
+	ID  string
+	URL string
+}
+
+// IssueLinks finds issue references in the given title or description of a pull/merge request:
+// IDs which are recognized by RevChat's configuration of issue trackers (like [LinkifyTitle]),
+// and full URLs of Bitbucket and GitHub issues. It returns them in order of appearance, without
+// duplicates, or nil if none are found.
+func IssueLinks(ctx workflow.Context, cfg map[string]string, text string) []IssueLink {
+	var links []IssueLink
+	done := map[string]bool{}
+
+	for _, id := range linkifyPattern.FindAllStringSubmatch(text, -1) {
+		if link := linkifyID(ctx, cfg, id); link != "" && !done[link] {
+			links = append(links, IssueLink{ID: id[0], URL: link})
+			done[link] = true
+		}
+	}
+
+	for _, url := range issueURLPattern.FindAllStringSubmatch(text, -1) {
+		if !done[url[0]] {
+			links = append(links, IssueLink{ID: fmt.Sprintf("%s#%s", url[2], url[3]), URL: url[0]})
+			done[url[0]] = true
+		}
 	}
 
-	return fmt.Sprintf("<https://%s/%s/%s/%s/%s|%s>", baseURL[1], id[2], id[3], baseURL[4], id[4], id[0])
+	return links
 }
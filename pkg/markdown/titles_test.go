@@ -1,6 +1,7 @@
 package markdown
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -110,3 +111,90 @@ func TestLinkifyTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequestURLs(t *testing.T) {
+	tests := []struct {
+		name  string
+		prURL string
+		text  string
+		want  []string
+	}{
+		{
+			name:  "no_refs",
+			prURL: "https://bitbucket.org/workspace/repo/pull-requests/",
+			text:  "This is an issue",
+		},
+		{
+			name:  "invalid_base_url",
+			prURL: "https://bitbucket.org/workspace/repo",
+			text:  "#123",
+		},
+		{
+			name:  "ids_in_same_and_other_repos",
+			prURL: "https://bitbucket.org/workspace/repo/pull-requests/",
+			text:  "See #12, other#34, ws/third#56, and #12 again",
+			want: []string{
+				"https://bitbucket.org/workspace/repo/pull-requests/12",
+				"https://bitbucket.org/workspace/other/pull-requests/34",
+				"https://bitbucket.org/ws/third/pull-requests/56",
+			},
+		},
+		{
+			name:  "urls",
+			prURL: "https://bitbucket.org/workspace/repo/pull-requests/1",
+			text:  "https://bitbucket.org/workspace/repo/pull-requests/7 and https://github.com/org/repo/pull/8",
+			want:  []string{"https://bitbucket.org/workspace/repo/pull-requests/7"},
+		},
+		{
+			name:  "id_and_url_of_same_pr",
+			prURL: "https://github.com/org/repo/pull/1",
+			text:  "#8 = https://github.com/org/repo/pull/8",
+			want:  []string{"https://github.com/org/repo/pull/8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PullRequestURLs(tt.prURL, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PullRequestURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]string
+		text string
+		want []IssueLink
+	}{
+		{
+			name: "no_refs",
+			cfg:  map[string]string{"default": "https://domain.atlassian.net/browse/"},
+			text: "This is a PR title",
+		},
+		{
+			name: "unrecognized_id",
+			cfg:  map[string]string{"FOO": "https://qwe.atlassian.net/browse/"},
+			text: "BAR-12",
+		},
+		{
+			name: "ids_and_urls",
+			cfg:  map[string]string{"default": "https://domain.atlassian.net/browse/"},
+			text: "PROJ-1: fix https://bitbucket.org/workspace/repo/issues/2 (and PROJ-1)",
+			want: []IssueLink{
+				{ID: "PROJ-1", URL: "https://domain.atlassian.net/browse/PROJ-1"},
+				{ID: "repo#2", URL: "https://bitbucket.org/workspace/repo/issues/2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IssueLinks(nil, tt.cfg, tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IssueLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package activities
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/timpani-api/pkg/slack"
+)
+
+const (
+	buildsBookmarkIndex    = 6
+	maxBuildsBookmarkTitle = 200
+)
+
+// FlakyBuildNote is appended to build status messages of likely flaky builds (see [data.RecordBuildTransition]).
+const FlakyBuildNote = "\n:game_die: This build previously failed in the same commit, so it's likely flaky."
+
+// BuildStateEmoji returns the emoji of a normalized build state (see [data.CommitStatus]).
+func BuildStateEmoji(state string) string {
+	switch state {
+	case "INPROGRESS":
+		return ":hourglass_flowing_sand:"
+	case "SUCCESSFUL":
+		return ":large_green_circle:"
+	default: // "FAILED", "STOPPED".
+		return ":red_circle:"
+	}
+}
+
+// UpdateBuildsBookmark updates the builds bookmark in the PR's Slack channel, based on the latest
+// build results of the PR. The bookmark's title starts with the given label, and each build is
+// described by the given function. This is a deferred call that doesn't return an error, because
+// handling the event itself is more important.
+func UpdateBuildsBookmark(ctx workflow.Context, channelID, prURL, label string, desc func(data.CommitStatus) string) {
+	bookmarks, err := slack.BookmarksList(ctx, channelID)
+	if err != nil {
+		logger.From(ctx).Error("failed to list Slack channel bookmarks", slog.Any("error", err))
+		return
+	}
+	if len(bookmarks) <= buildsBookmarkIndex {
+		return
+	}
+
+	prStatus := data.ReadBuilds(ctx, prURL)
+
+	sb := new(strings.Builder)
+	sb.WriteString(label + ": ")
+	if len(prStatus.Builds) == 0 {
+		sb.WriteString("no results")
+	}
+
+	keys := slices.Sorted(maps.Keys(prStatus.Builds)) //workflowcheck:ignore // Sorted for deterministic order.
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+
+		b := prStatus.Builds[k]
+		fmt.Fprintf(sb, "%s %s", buildState(b.State), desc(b)) //workflowcheck:ignore // Deterministic output, not a file.
+	}
+
+	title := sb.String()
+	if len(title) > maxBuildsBookmarkTitle {
+		title = title[:maxBuildsBookmarkTitle]
+	}
+
+	bookmark := bookmarks[buildsBookmarkIndex]
+	if title == bookmark.Title {
+		return
+	}
+
+	if err := slack.BookmarksEditTitle(ctx, channelID, bookmark.ID, title); err != nil {
+		logger.From(ctx).Error("failed to update Slack channel's builds bookmark", slog.Any("error", err))
+	}
+}
+
+func buildState(state string) string {
+	switch state {
+	case "INPROGRESS":
+		return "[?]"
+	case "SUCCESSFUL":
+		return "[V]"
+	default: // "FAILED", "STOPPED".
+		return "[X]"
+	}
+}
@@ -135,24 +135,37 @@ func KickUsersFromChannel(ctx workflow.Context, opts client.Options, channelID,
 			continue
 		}
 
+		// Stop tracking the user before kicking them, so the resulting "member left"
+		// event won't treat them as a reviewer who left the channel by themselves.
+		email := users.SlackIDToEmail(ctx, id)
+		_, isReviewer := data.LoadParticipantRoles(ctx, opts, prURL, email)
+		if err := data.RemoveReviewerFromTurns(ctx, opts, prURL, email, false); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
 		err := slack.ConversationsKick(ctx, channelID, id)
-		if err != nil {
-			msg := "failed to remove user from Slack channel"
-
-			if strings.Contains(err.Error(), "not_in_channel") {
-				msg += " - not in channel" // This is not a problem.
-				logger.From(ctx).Debug(msg, slog.Any("error", err),
-					slog.String("channel_id", channelID), slog.String("user_id", id))
-			} else {
-				logger.From(ctx).Error(msg, slog.Any("error", err),
-					slog.String("channel_id", channelID), slog.String("user_id", id))
-				errs = append(errs, err)
-				continue
-			}
+		if err == nil {
+			continue
 		}
 
-		if err := data.RemoveReviewerFromTurns(ctx, opts, prURL, users.SlackIDToEmail(ctx, id), false); err != nil {
-			errs = append(errs, err)
+		msg := "failed to remove user from Slack channel"
+		if strings.Contains(err.Error(), "not_in_channel") {
+			msg += " - not in channel" // This is not a problem.
+			logger.From(ctx).Debug(msg, slog.Any("error", err),
+				slog.String("channel_id", channelID), slog.String("user_id", id))
+			continue
+		}
+
+		logger.From(ctx).Error(msg, slog.Any("error", err),
+			slog.String("channel_id", channelID), slog.String("user_id", id))
+		errs = append(errs, err)
+
+		// Keep tracking users who are still in the channel.
+		if isReviewer {
+			if _, _, err := data.SetReviewerTurn(ctx, opts, prURL, email, false); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
 
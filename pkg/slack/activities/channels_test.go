@@ -0,0 +1,64 @@
+package activities
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+func TestKickUsersFromChannel(t *testing.T) {
+	const prURL = "https://github.com/owner/repo/pull/1"
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	for _, u := range [][2]string{{"U1", "alice"}, {"U2", "bob"}, {"U3", "carol"}} {
+		if err := data.UpsertUser(nil, u[1]+"@example.com", u[1], "", u[1], u[0], ""); err != nil {
+			t.Fatalf("UpsertUser() error = %v", err)
+		}
+	}
+	data.InitTurns(nil, prURL, "dave@example.com")
+	for _, email := range []string{"alice@example.com", "bob@example.com", "carol@example.com"} {
+		if _, _, err := data.SetReviewerTurn(nil, client.Options{}, prURL, email, false); err != nil {
+			t.Fatalf("SetReviewerTurn() error = %v", err)
+		}
+	}
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(KickUsersFromChannel, workflow.RegisterOptions{Name: "kick"})
+
+	// Alice is kicked successfully, Bob already left the channel, and Carol can't be kicked.
+	env.RegisterActivityWithOptions(func(_ context.Context, req map[string]any) (map[string]any, error) {
+		switch req["user"] {
+		case "U2":
+			return nil, temporal.NewNonRetryableApplicationError("not_in_channel", "SlackError", nil)
+		case "U3":
+			return nil, temporal.NewNonRetryableApplicationError("cant_kick_self", "SlackError", nil)
+		default:
+			return map[string]any{"ok": true}, nil
+		}
+	}, activity.RegisterOptions{Name: "slack.conversations.kick"})
+
+	env.ExecuteWorkflow("kick", client.Options{}, "C1", prURL, []string{"U1", "", "U2", "U3"})
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("KickUsersFromChannel() didn't complete")
+	}
+	if err := env.GetWorkflowError(); err == nil {
+		t.Error("KickUsersFromChannel() error = nil, want an error about U3")
+	}
+
+	got, err := data.LoadCurrentTurnEmails(nil, client.Options{}, prURL)
+	if err != nil {
+		t.Fatalf("LoadCurrentTurnEmails() error = %v", err)
+	}
+	if want := []string{"carol@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("LoadCurrentTurnEmails() = %v, want %v", got, want)
+	}
+}
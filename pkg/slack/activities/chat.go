@@ -11,6 +11,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/users"
 	"github.com/tzrikka/timpani-api/pkg/slack"
@@ -174,6 +175,29 @@ func PostDMWithImage(ctx workflow.Context, senderID, recipientID, msg, imageURL,
 	return nil
 }
 
+// PostMessageWithBlocks posts a Block Kit message. The text is used
+// as a fallback in notifications, and in clients that can't render blocks.
+func PostMessageWithBlocks(ctx workflow.Context, channelID, text string, blocks []map[string]any) error {
+	_, err := slack.ChatPostMessage(ctx, slack.ChatPostMessageRequest{Channel: channelID, Text: text, Blocks: blocks})
+	if err != nil {
+		logger.From(ctx).Error("failed to post Slack message with blocks", slog.Any("error", err),
+			slog.String("channel_id", channelID), slog.Int("block_count", len(blocks)))
+		return err
+	}
+	return nil
+}
+
+// UpdateMessageBlocks replaces the text and blocks of an existing Block Kit message.
+func UpdateMessageBlocks(ctx workflow.Context, channelID, timestamp, text string, blocks []map[string]any) error {
+	req := slack.ChatUpdateRequest{Channel: channelID, TS: timestamp, Text: text, Blocks: blocks}
+	if err := slack.ChatUpdate(ctx, req); err != nil {
+		logger.From(ctx).Error("failed to update Slack message blocks", slog.Any("error", err),
+			slog.String("channel_id", channelID), slog.String("msg_ts", timestamp))
+		return err
+	}
+	return nil
+}
+
 func UpdateMessage(ctx workflow.Context, channelID, timestamp, msg string) error {
 	req := slack.ChatUpdateRequest{Channel: channelID, TS: timestamp, Text: msg}
 	if err := slack.ChatUpdate(ctx, req); err != nil {
@@ -183,3 +207,25 @@ func UpdateMessage(ctx workflow.Context, channelID, timestamp, msg string) error
 	}
 	return nil
 }
+
+// ThreadTS returns the timestamp of the thread that contains the given Slack message,
+// or an empty string if it's a top-level message (including the root of a thread):
+// https://docs.slack.dev/reference/methods/conversations.replies
+func ThreadTS(ctx workflow.Context, channelID, timestamp string) (string, error) {
+	req := slack.ConversationsRepliesRequest{Channel: channelID, TS: timestamp, Limit: 1}
+	resp, err := timpani.ExecuteActivity[slack.ConversationsRepliesResponse](ctx, slack.ConversationsRepliesActivityName, req)
+	if err != nil {
+		logger.From(ctx).Error("failed to get Slack message's thread", slog.Any("error", err),
+			slog.String("channel_id", channelID), slog.String("msg_ts", timestamp))
+		return "", err
+	}
+
+	if len(resp.Messages) == 0 {
+		return "", nil
+	}
+	threadTS, _ := resp.Messages[0]["thread_ts"].(string)
+	if threadTS == timestamp {
+		return "", nil
+	}
+	return threadTS, nil
+}
@@ -0,0 +1,29 @@
+package activities
+
+import (
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/internal/timpani"
+)
+
+// pinsRequest is based on:
+// https://docs.slack.dev/reference/methods/pins.add
+type pinsRequest struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+}
+
+// PinMessage pins a Slack message to its channel:
+// https://docs.slack.dev/reference/methods/pins.add
+func PinMessage(ctx workflow.Context, channelID, timestamp string) error {
+	req := pinsRequest{Channel: channelID, Timestamp: timestamp}
+	if _, err := timpani.ExecuteActivity[map[string]any](ctx, "slack.pins.add", req); err != nil {
+		logger.From(ctx).Error("failed to pin Slack message", slog.Any("error", err),
+			slog.String("channel_id", channelID), slog.String("msg_ts", timestamp))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package slack
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+// awayEventPattern matches the summaries of calendar events which indicate that the user is away.
+var awayEventPattern = regexp.MustCompile(`(?i)\b(vacation(ing)?|holiday|out of (the )?office|ooo|pto|out sick|(sick|parental|maternity|paternity) leave|on leave)\b`)
+
+// icsEvent contains the subset of iCalendar event properties that [AwayPeriodsFromICS] needs.
+type icsEvent struct {
+	start, end  time.Time
+	allDay      bool
+	summary     string
+	outOfOffice bool
+	skip        bool
+}
+
+// AwayPeriodsFromICS extracts out-of-office periods from the content of an iCalendar file (RFC 5545), e.g.
+// exported from Google Calendar or Outlook. Events count as out-of-office if Outlook marks them as such, or
+// if their summary indicates that the user is away (see [awayEventPattern]). Cancelled and recurring events are
+// ignored, as well as events which already ended. Floating times, and times in unrecognized timezones
+// (e.g. Windows timezone names), are interpreted in the given location.
+func AwayPeriodsFromICS(ics string, loc *time.Location, now time.Time) ([]data.AwayPeriod, error) {
+	// Slack escapes "&", "<", and ">" in messages, and users may paste the content as a code block.
+	ics = strings.Trim(html.UnescapeString(strings.TrimSpace(ics)), "`")
+	if !strings.Contains(ics, "BEGIN:VCALENDAR") {
+		return nil, errors.New("this doesn't look like an iCalendar file")
+	}
+
+	var periods []data.AwayPeriod
+	var event *icsEvent
+	depth := 0 // Nested components inside an event (e.g. alarms).
+
+	for _, line := range unfoldICSLines(ics) {
+		name, params, value := parseICSLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icsEvent{}
+			continue
+		case event == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+			continue
+		case name == "END" && depth > 0:
+			depth--
+			continue
+		case depth > 0:
+			continue
+		}
+
+		switch name {
+		case "DTSTART":
+			event.start, event.allDay = parseICSTime(params, value, loc)
+			event.skip = event.skip || event.start.IsZero()
+		case "DTEND":
+			event.end, _ = parseICSTime(params, value, loc)
+			event.skip = event.skip || event.end.IsZero()
+		case "SUMMARY":
+			event.summary = value
+		case "X-MICROSOFT-CDO-BUSYSTATUS":
+			event.outOfOffice = strings.EqualFold(value, "OOF")
+		case "STATUS":
+			event.skip = event.skip || strings.EqualFold(value, "CANCELLED")
+		case "RRULE", "RDATE", "RECURRENCE-ID":
+			event.skip = true
+		case "END":
+			if value == "VEVENT" {
+				if p, ok := event.awayPeriod(now); ok {
+					periods = append(periods, p)
+				}
+				event = nil
+			}
+		}
+	}
+
+	return periods, nil
+}
+
+// awayPeriod returns the event as an out-of-office period, if it is one, and it didn't end yet.
+func (e *icsEvent) awayPeriod(now time.Time) (data.AwayPeriod, bool) {
+	if e.skip || e.start.IsZero() || (!e.outOfOffice && !awayEventPattern.MatchString(e.summary)) {
+		return data.AwayPeriod{}, false
+	}
+
+	// All-day events without an end time last a single day.
+	if e.end.IsZero() && e.allDay {
+		e.end = e.start.AddDate(0, 0, 1)
+	}
+	if !e.end.After(e.start) || !e.end.After(now) {
+		return data.AwayPeriod{}, false
+	}
+
+	return data.AwayPeriod{Start: e.start.UTC(), End: e.end.UTC()}, true
+}
+
+// unfoldICSLines splits iCalendar content into lines, and joins long
+// lines which were folded into multiple ones (RFC 5545 section 3.1).
+func unfoldICSLines(ics string) []string {
+	var lines []string
+	for line := range strings.Lines(ics) {
+		line = strings.TrimRight(line, "\r\n")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICSLine splits a content line into its uppercased name, its parameters, and its
+// (unescaped) value. Parameter values may be quoted, and contain colons and semicolons.
+func parseICSLine(line string) (name string, params map[string]string, value string) {
+	i, quoted := 0, false
+	for ; i < len(line); i++ {
+		if line[i] == '"' {
+			quoted = !quoted
+		}
+		if line[i] == ':' && !quoted {
+			break
+		}
+	}
+	if i == len(line) {
+		return "", nil, ""
+	}
+
+	value = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(line[i+1:])
+	parts := strings.Split(line[:i], ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return name, params, value
+}
+
+// parseICSTime parses the value of a DTSTART or DTEND property, which is either a date (i.e. an all-day event),
+// a UTC date-time, a date-time in a specific timezone, or a floating date-time (RFC 5545 sections 3.3.4-5).
+// It returns a zero value if the value is invalid.
+func parseICSTime(params map[string]string, value string, loc *time.Location) (time.Time, bool) {
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("20060102T150405", strings.TrimSuffix(value, "Z"), loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, false
+}
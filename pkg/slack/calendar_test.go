@@ -0,0 +1,101 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+func TestAwayPeriodsFromICS(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ics     string
+		want    []data.AwayPeriod
+		wantErr bool
+	}{
+		{
+			name:    "not_ics",
+			ics:     "Hello world",
+			wantErr: true,
+		},
+		{
+			name: "all_day_vacation",
+			ics: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260120\r\n" +
+				"DTEND;VALUE=DATE:20260123\r\nSUMMARY:Vacation\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			want: []data.AwayPeriod{{
+				Start: time.Date(2026, 1, 20, 5, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 1, 23, 5, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "all_day_without_end",
+			ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20260120\n" +
+				"SUMMARY:PTO\nEND:VEVENT\nEND:VCALENDAR\n",
+			want: []data.AwayPeriod{{
+				Start: time.Date(2026, 1, 20, 5, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 1, 21, 5, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "outlook_oof_with_tzid",
+			ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;TZID=Europe/London:20260201T090000\n" +
+				"DTEND;TZID=Europe/London:20260201T170000\nSUMMARY:Offsite\n" +
+				"X-MICROSOFT-CDO-BUSYSTATUS:OOF\nEND:VEVENT\nEND:VCALENDAR\n",
+			want: []data.AwayPeriod{{
+				Start: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 2, 1, 17, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "utc_folded_summary_and_alarm",
+			ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260114T000000Z\nDTEND:20260116T000000Z\n" +
+				"SUMMARY:Out of\n  office\nBEGIN:VALARM\nSUMMARY:Meeting\nEND:VALARM\nEND:VEVENT\nEND:VCALENDAR\n",
+			want: []data.AwayPeriod{{
+				Start: time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "ignored_events",
+			ics: "BEGIN:VCALENDAR\n" +
+				"BEGIN:VEVENT\nDTSTART:20260120T150000Z\nDTEND:20260120T160000Z\nSUMMARY:Team sync\nEND:VEVENT\n" +
+				"BEGIN:VEVENT\nDTSTART:20260101T000000Z\nDTEND:20260105T000000Z\nSUMMARY:Holiday\nEND:VEVENT\n" +
+				"BEGIN:VEVENT\nDTSTART:20260120T000000Z\nDTEND:20260121T000000Z\nSUMMARY:OOO\nSTATUS:CANCELLED\nEND:VEVENT\n" +
+				"BEGIN:VEVENT\nDTSTART:20260120T000000Z\nDTEND:20260121T000000Z\nSUMMARY:OOO\nRRULE:FREQ=WEEKLY\nEND:VEVENT\n" +
+				"END:VCALENDAR\n",
+		},
+		{
+			name: "slack_escaped_code_block",
+			ics: "```BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260120T100000\nDTEND:20260120T180000\n" +
+				"SUMMARY:Sick leave &amp; recovery\nEND:VEVENT\nEND:VCALENDAR```",
+			want: []data.AwayPeriod{{
+				Start: time.Date(2026, 1, 20, 15, 0, 0, 0, time.UTC),
+				End:   time.Date(2026, 1, 20, 23, 0, 0, 0, time.UTC),
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AwayPeriodsFromICS(tt.ics, loc, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AwayPeriodsFromICS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("AwayPeriodsFromICS() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("AwayPeriodsFromICS()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
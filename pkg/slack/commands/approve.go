@@ -2,11 +2,18 @@ package commands
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
@@ -21,27 +28,38 @@ func Approve(ctx workflow.Context, event SlashCommandEvent) error {
 		return err
 	}
 
-	if url[1] == "bitbucket.org" {
+	if err := ApprovePR(ctx, user, url); err != nil {
+		PostEphemeralError(ctx, event, "failed to approve "+url[0])
+		return err
+	}
+
+	// No need to post a confirmation message or update its bookmarks,
+	// the resulting Bitbucket/GitHub event will trigger that.
+	return nil
+}
+
+// ApprovePR approves a PR on behalf of the given user. The URL parts are based on [PullRequestURLPattern].
+func ApprovePR(ctx workflow.Context, user data.User, url []string) error {
+	var err error
+	switch {
+	case url[1] == "bitbucket.org":
 		err = bitbucket.PullRequestsApprove(ctx, user.ThrippyLink, url[2], url[3], url[5])
-	} else {
-		logger.From(ctx).Error("GitHub is not supported yet")
-		PostEphemeralError(ctx, event, "internal configuration error.")
-		return errors.New("GitHub is not supported yet")
+	case datacenter.IsURL(url[0]):
+		err = bbactivities.ApproveDataCenterPullRequest(ctx, user.ThrippyLink, url[0])
+	default:
+		err = approveGitHubPR(ctx, user, url)
 	}
 
 	if err != nil {
 		logger.From(ctx).Error("failed to approve PR", slog.Any("error", err), slog.String("pr_url", url[0]),
-			slog.String("slack_user_id", event.UserID), slog.String("thrippy_id", user.ThrippyLink))
-		PostEphemeralError(ctx, event, "failed to approve "+url[0])
+			slog.String("email", user.Email), slog.String("thrippy_id", user.ThrippyLink))
 		return err
 	}
 
-	// No need to post a confirmation message or update its bookmarks,
-	// the resulting Bitbucket/GitHub event will trigger that.
 	return nil
 }
 
-func Unapprove(ctx workflow.Context, event SlashCommandEvent) (err error) {
+func Unapprove(ctx workflow.Context, event SlashCommandEvent, listReviews bool) (err error) {
 	url, err := prDetailsFromChannel(ctx, event)
 	if url == nil {
 		return err // May or may not be nil.
@@ -52,12 +70,17 @@ func Unapprove(ctx workflow.Context, event SlashCommandEvent) (err error) {
 		return err
 	}
 
-	if url[1] == "bitbucket.org" {
+	switch {
+	case url[1] == "bitbucket.org":
 		err = bitbucket.PullRequestsUnapprove(ctx, user.ThrippyLink, url[2], url[3], url[5])
-	} else {
-		logger.From(ctx).Error("GitHub is not supported yet")
-		PostEphemeralError(ctx, event, "internal configuration error.")
-		return errors.New("GitHub is not supported yet")
+	case datacenter.IsURL(url[0]):
+		err = bbactivities.UnapproveDataCenterPullRequest(ctx, user.ThrippyLink, url[0])
+	case !listReviews:
+		// Unapproving requires finding the user's latest approving review.
+		PostEphemeralError(ctx, event, "unapproving GitHub PRs is not enabled in RevChat yet.")
+		return nil
+	default:
+		err = unapproveGitHubPR(ctx, user, url)
 	}
 
 	if err != nil {
@@ -71,3 +94,36 @@ func Unapprove(ctx workflow.Context, event SlashCommandEvent) (err error) {
 	// the resulting Bitbucket/GitHub event will trigger that.
 	return nil
 }
+
+// approveGitHubPR submits an approving review, on behalf of the given user.
+func approveGitHubPR(ctx workflow.Context, user data.User, url []string) error {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	return github.ApprovePullRequest(ctx, user.ThrippyLink, url[2], url[3], prID)
+}
+
+// unapproveGitHubPR dismisses the latest approving review of the given user. GitHub doesn't have a
+// concept of unapproving, and submitted reviews can't be deleted, so dismissal is the closest thing.
+func unapproveGitHubPR(ctx workflow.Context, user data.User, url []string) error {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	reviews, err := github.ListPullRequestReviews(ctx, user.ThrippyLink, url[2], url[3], prID)
+	if err != nil {
+		return err
+	}
+
+	for i := len(reviews) - 1; i >= 0; i-- {
+		r := reviews[i]
+		if strings.EqualFold(r.User.Login, user.GitHubID) && strings.EqualFold(r.State, "approved") {
+			return github.DismissReview(ctx, user.ThrippyLink, url[2], url[3], prID, r.ID, "Unapproved via RevChat.")
+		}
+	}
+
+	return errors.New("approving GitHub review not found")
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+// newApproveTestEnv returns a test environment for [Approve] and [Unapprove], in which the Slack channel "C1" is
+// mapped to the given PR, and a pointer to a summary of all the activity calls. When listing GitHub PR reviews,
+// the user "bob" (U2) has an old approval (ID 1), a newer approval (ID 3), and a comment after that (ID 4),
+// while the user "carol" (U3) has no reviews at all.
+func newApproveTestEnv(t *testing.T, prURL string) (*testsuite.TestWorkflowEnvironment, *[]string) {
+	t.Helper()
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if err := data.UpsertUser(nil, "bob@example.com", "Bob", "", "bob", "U2", "link"); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if err := data.UpsertUser(nil, "carol@example.com", "Carol", "", "carol", "U3", "link"); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if err := data.MapURLAndID(nil, prURL, "C1"); err != nil {
+		t.Fatalf("MapURLAndID() error = %v", err)
+	}
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(Approve, workflow.RegisterOptions{Name: "approve"})
+	env.RegisterWorkflowWithOptions(Unapprove, workflow.RegisterOptions{Name: "unapprove"})
+
+	calls := []string{}
+	record := func(name string, details func(map[string]any) string, resp any) {
+		env.RegisterActivityWithOptions(func(_ context.Context, req map[string]any) (any, error) {
+			calls = append(calls, name+" "+details(req))
+			return resp, nil
+		}, activity.RegisterOptions{Name: name})
+	}
+	field := func(key string) func(map[string]any) string {
+		return func(req map[string]any) string {
+			return fmt.Sprint(req[key])
+		}
+	}
+
+	record("bitbucket.pullrequests.approve", field("pull_request_id"), map[string]any{})
+	record("bitbucket.pullrequests.unapprove", field("pull_request_id"), map[string]any{})
+	record("github.pulls.reviews.create", field("event"), map[string]any{"id": 5})
+	record("github.pulls.reviews.dismiss", field("review_id"), map[string]any{"id": 3})
+	record("github.pulls.reviews.list", field("pull_number"), []map[string]any{
+		{"id": 1, "user": map[string]any{"login": "bob"}, "state": "APPROVED"},
+		{"id": 2, "user": map[string]any{"login": "alice"}, "state": "APPROVED"},
+		{"id": 3, "user": map[string]any{"login": "bob"}, "state": "APPROVED"},
+		{"id": 4, "user": map[string]any{"login": "bob"}, "state": "COMMENTED"},
+	})
+	record("slack.chat.postEphemeral", field("text"), map[string]any{"ok": true})
+
+	return env, &calls
+}
+
+func TestApprove(t *testing.T) {
+	tests := []struct {
+		name  string
+		prURL string
+		want  []string
+	}{
+		{
+			name:  "bitbucket",
+			prURL: "https://bitbucket.org/workspace/repo/pull-requests/1",
+			want:  []string{"bitbucket.pullrequests.approve 1"},
+		},
+		{
+			name:  "github",
+			prURL: "https://github.com/owner/repo/pull/1",
+			want:  []string{"github.pulls.reviews.create APPROVE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, calls := newApproveTestEnv(t, tt.prURL)
+			env.ExecuteWorkflow("approve", SlashCommandEvent{ChannelID: "C1", UserID: "U2"})
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("Approve() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("Approve() error = %v", err)
+			}
+			if !slices.Equal(*calls, tt.want) {
+				t.Errorf("Approve() calls = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnapprove(t *testing.T) {
+	tests := []struct {
+		name        string
+		prURL       string
+		userID      string
+		listReviews bool
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:   "bitbucket",
+			prURL:  "https://bitbucket.org/workspace/repo/pull-requests/1",
+			userID: "U2",
+			want:   []string{"bitbucket.pullrequests.unapprove 1"},
+		},
+		{
+			name:        "github_latest_approval",
+			prURL:       "https://github.com/owner/repo/pull/1",
+			userID:      "U2",
+			listReviews: true,
+			want:        []string{"github.pulls.reviews.list 1", "github.pulls.reviews.dismiss 3"},
+		},
+		{
+			name:   "github_without_listing_reviews",
+			prURL:  "https://github.com/owner/repo/pull/1",
+			userID: "U2",
+			want:   []string{"slack.chat.postEphemeral :warning: Error: unapproving GitHub PRs is not enabled in RevChat yet."},
+		},
+		{
+			name:        "github_no_approval",
+			prURL:       "https://github.com/owner/repo/pull/1",
+			userID:      "U3",
+			listReviews: true,
+			want: []string{
+				"github.pulls.reviews.list 1",
+				"slack.chat.postEphemeral :warning: Error: failed to unapprove https://github.com/owner/repo/pull/1",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, calls := newApproveTestEnv(t, tt.prURL)
+			env.ExecuteWorkflow("unapprove", SlashCommandEvent{ChannelID: "C1", UserID: tt.userID}, tt.listReviews)
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("Unapprove() didn't complete")
+			}
+			if err := env.GetWorkflowError(); (err != nil) != tt.wantErr {
+				t.Fatalf("Unapprove() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(*calls, tt.want) {
+				t.Errorf("Unapprove() calls = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/users"
+)
+
+// maxAwayDuration is longer than [maxSnoozeDuration], to allow for long leaves.
+const maxAwayDuration = 365 * 24 * time.Hour
+
+var (
+	awayDelegatePattern = regexp.MustCompile(`(?i)(^|\s+)(delegate\s+(to\s+)?)?<@(\w+)(\|[^>]*)?>$`)
+	awayDurationPattern = regexp.MustCompile(`^(\d+)\s*(d|days?|w|weeks?)$`)
+
+	awaySyntax = untilSyntax{
+		durationPattern: awayDurationPattern,
+		wholeDays:       true,
+		maxDuration:     maxAwayDuration,
+		usage:           "specify a date (e.g. `2026-01-31`), `tomorrow`, a weekday, or a number of days or weeks (e.g. `3d`, `2w`)",
+		noun:            "away time",
+		tooLong:         "you can be away for up to %d days",
+	}
+)
+
+// Away sets an out-of-office period for the calling user, from now until the given time (see [awayUntil]),
+// optionally with a delegate who receives the user's turns. Without a time, it sets the delegate of all the
+// user's current and future away periods, including ones imported from calendars. Without any arguments, it
+// lists the user's current and future away periods. "clear" removes all of them.
+func Away(ctx workflow.Context, opts client.Options, event SlashCommandEvent, text string) error {
+	user, optedIn, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+	if !optedIn {
+		PostEphemeralError(ctx, event, "you need to opt-in first.")
+		return nil
+	}
+
+	text = strings.TrimSpace(text)
+	switch strings.ToLower(text) {
+	case "":
+		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, awayPeriodsText(user, workflow.Now(ctx)))
+	case "clear":
+		return endAwayPeriods(ctx, event, user, true)
+	}
+
+	delegateID := ""
+	if match := awayDelegatePattern.FindStringSubmatch(text); match != nil {
+		delegateID = strings.ToUpper(match[4])
+		text = strings.TrimSuffix(text, match[0])
+		if !checkDelegate(ctx, event, delegateID) {
+			return nil
+		}
+		if text == "" {
+			return setAwayDelegate(ctx, opts, event, user, delegateID)
+		}
+	}
+
+	now := workflow.Now(ctx)
+	until, err := awayUntil(now, userLocation(ctx, event.UserID), text)
+	if err != nil {
+		PostEphemeralError(ctx, event, err.Error()+".")
+		return nil // Not a server error as far as we're concerned.
+	}
+
+	period := data.AwayPeriod{Start: now.UTC(), End: until, Delegate: delegateID}
+	periods := slices.DeleteFunc(slices.Clone(user.Away), func(p data.AwayPeriod) bool {
+		return p.Start.Before(period.End) && p.End.After(period.Start) // Overlapping periods.
+	})
+	user.Away = append(periods, period)
+	if err := data.SetAwayPeriods(ctx, event.UserID, user.Away); err != nil {
+		PostEphemeralError(ctx, event, "failed to write internal data about you.")
+		return err
+	}
+
+	msg := fmt.Sprintf(":palm_tree: You're away until %s.\n\n", SnoozeTimeText(until))
+	msg += "Your reminders are paused, and nudges will tell others that you're away."
+	if delegateID != "" {
+		count := HandOverAwayTurns(ctx, opts, user)
+		msg += fmt.Sprintf("\n\n<@%s> is your delegate, and received your turn in %d PRs.", delegateID, count)
+		msg += " They will also receive turns that are assigned to you while you're away."
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+}
+
+// setAwayDelegate sets the delegate of all the user's current and future away periods. If the user is
+// away right now, their turns are handed over to the new delegate immediately (see [HandOverAwayTurns]).
+func setAwayDelegate(ctx workflow.Context, opts client.Options, event SlashCommandEvent, user data.User, delegateID string) error {
+	now := workflow.Now(ctx)
+	periods := slices.DeleteFunc(slices.Clone(user.Away), func(p data.AwayPeriod) bool {
+		return !p.End.After(now)
+	})
+	if len(periods) == 0 {
+		PostEphemeralError(ctx, event, "you don't have any away periods, specify until when you're away.")
+		return nil
+	}
+
+	for i := range periods {
+		periods[i].Delegate = delegateID
+		periods[i].HandedOver = false
+	}
+
+	user.Away = periods
+	if err := data.SetAwayPeriods(ctx, event.UserID, user.Away); err != nil {
+		PostEphemeralError(ctx, event, "failed to write internal data about you.")
+		return err
+	}
+
+	msg := fmt.Sprintf(":bust_in_silhouette: <@%s> is now your delegate in all your away periods.", delegateID)
+	if _, away := user.AwayAt(now); away {
+		msg += fmt.Sprintf(" They received your turn in %d PRs.", HandOverAwayTurns(ctx, opts, user))
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg+"\n\n"+awayPeriodsText(user, now))
+}
+
+// Back ends the calling user's current out-of-office period, if there is one.
+// Future periods (e.g. imported from calendars) are not affected.
+func Back(ctx workflow.Context, event SlashCommandEvent) error {
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	return endAwayPeriods(ctx, event, user, false)
+}
+
+func endAwayPeriods(ctx workflow.Context, event SlashCommandEvent, user data.User, all bool) error {
+	now := workflow.Now(ctx)
+	if _, away := user.AwayAt(now); !away && (!all || len(user.Away) == 0) {
+		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, ":joy: You're not away anyway!")
+	}
+
+	var periods []data.AwayPeriod
+	if !all {
+		periods = slices.DeleteFunc(slices.Clone(user.Away), func(p data.AwayPeriod) bool {
+			return !now.Before(p.Start) && now.Before(p.End)
+		})
+	}
+
+	if err := data.SetAwayPeriods(ctx, event.UserID, periods); err != nil {
+		PostEphemeralError(ctx, event, "failed to write internal data about you.")
+		return err
+	}
+
+	msg := ":wave: Welcome back! Your reminders are resumed."
+	if all {
+		msg = ":broom: All your away periods were removed, and your reminders are resumed."
+	}
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+}
+
+// ImportCalendar adds out-of-office periods from an iCalendar file (see [slack.AwayPeriodsFromICS]), which
+// the user sent to RevChat in a DM, to their existing periods. Existing periods which overlap are replaced.
+func ImportCalendar(ctx workflow.Context, channelID, userID, ics string) error {
+	user, optedIn, err := data.SelectUserBySlackID(ctx, userID)
+	if err != nil {
+		_ = activities.PostMessage(ctx, channelID, ":warning: Error: failed to read internal data about you.")
+		return err
+	}
+	if !optedIn {
+		return activities.PostMessage(ctx, channelID, ":warning: Error: you need to opt-in first.")
+	}
+
+	imported, err := slack.AwayPeriodsFromICS(ics, userLocation(ctx, userID), workflow.Now(ctx))
+	if err != nil {
+		return activities.PostMessage(ctx, channelID, fmt.Sprintf(":warning: Error: %s.", err))
+	}
+	if len(imported) == 0 {
+		return activities.PostMessage(ctx, channelID, ":shrug: This calendar doesn't have any current or future out-of-office events.")
+	}
+
+	periods := slices.DeleteFunc(slices.Clone(user.Away), func(p data.AwayPeriod) bool {
+		return slices.ContainsFunc(imported, func(i data.AwayPeriod) bool {
+			return p.Start.Before(i.End) && p.End.After(i.Start) // Overlapping periods.
+		})
+	})
+	user.Away = append(periods, imported...)
+
+	if err := data.SetAwayPeriods(ctx, userID, user.Away); err != nil {
+		_ = activities.PostMessage(ctx, channelID, ":warning: Error: failed to write internal data about you.")
+		return err
+	}
+
+	slices.SortFunc(user.Away, func(a, b data.AwayPeriod) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	msg := fmt.Sprintf(":calendar: Imported %d away periods.\n\n", len(imported))
+	return activities.PostMessage(ctx, channelID, msg+awayPeriodsText(user, workflow.Now(ctx)))
+}
+
+// AwayText returns an explanation why the given user can't be nudged right now, or an empty string if they're not away.
+func AwayText(ctx workflow.Context, user data.User) string {
+	p, away := user.AwayAt(workflow.Now(ctx))
+	if !away {
+		return ""
+	}
+
+	msg := fmt.Sprintf(":palm_tree: <@%s> is away until %s", user.SlackID, SnoozeTimeText(p.End))
+	if p.Delegate != "" {
+		msg += fmt.Sprintf(", you may want to reach out to their delegate <@%s> instead", p.Delegate)
+	}
+	return msg + "."
+}
+
+func awayPeriodsText(user data.User, now time.Time) string {
+	var msg strings.Builder
+	for _, p := range user.Away {
+		if !p.End.After(now) {
+			continue
+		}
+		if msg.Len() == 0 {
+			msg.WriteString(":palm_tree: Your away periods:\n")
+		}
+
+		fmt.Fprintf(&msg, "\n  •   %s - %s", SnoozeTimeText(p.Start), SnoozeTimeText(p.End)) //workflowcheck:ignore // Deterministic output, not a file.
+		if p.Delegate != "" {
+			fmt.Fprintf(&msg, " (delegate: <@%s>)", p.Delegate) //workflowcheck:ignore // Same as above.
+		}
+		if !p.Start.After(now) {
+			msg.WriteString(" - *now*")
+		}
+	}
+
+	if msg.Len() == 0 {
+		return ":sunny: You don't have any away periods."
+	}
+	return msg.String()
+}
+
+// checkDelegate ensures that the delegate isn't the calling user, and is opted-in.
+// If not, it also posts an explanation to the calling user.
+func checkDelegate(ctx workflow.Context, event SlashCommandEvent, delegateID string) bool {
+	if delegateID == event.UserID {
+		PostEphemeralError(ctx, event, "you can't be your own delegate.")
+		return false
+	}
+
+	_, optedIn, err := UserDetails(ctx, event, delegateID)
+	if err != nil {
+		return false
+	}
+	if !optedIn {
+		msg := fmt.Sprintf(":no_bell: <@%s> isn't opted-in to use RevChat, so they can't be your delegate.", delegateID)
+		_ = activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+		return false
+	}
+
+	return true
+}
+
+// HandOverAwayTurns hands over the user's current turns as a reviewer to the delegate of their current away
+// period, and notifies the delegate, unless this was already done in this period. It returns the number of PRs
+// that were handed over. It's called when the user sets an away period or a delegate, and periodically by
+// the scheduled reminders workflow, to handle periods which start later (e.g. imported from calendars).
+// Turns that are assigned to the user while they're away are handed over by the data layer.
+func HandOverAwayTurns(ctx workflow.Context, opts client.Options, user data.User) int {
+	p, away := user.AwayAt(workflow.Now(ctx))
+	if !away || p.Delegate == "" || p.HandedOver {
+		return 0
+	}
+
+	// Record the handover first, to avoid notifying the delegate repeatedly if the rest fails.
+	periods := slices.Clone(user.Away)
+	for i := range periods {
+		if periods[i].Start.Equal(p.Start) && periods[i].End.Equal(p.End) {
+			periods[i].HandedOver = true
+		}
+	}
+	if err := data.SetAwayPeriods(ctx, user.SlackID, periods); err != nil {
+		return 0 // Try again next time.
+	}
+
+	prs := handOverTurns(ctx, opts, user, p.Delegate)
+	if len(prs) > 0 {
+		notifyDelegate(ctx, user.SlackID, p.Delegate, p.End, prs)
+	}
+	return len(prs)
+}
+
+// handOverTurns switches the user's current turns as a reviewer to the delegate, in PRs
+// which the delegate didn't create or approve already. It returns the URLs of these PRs.
+func handOverTurns(ctx workflow.Context, opts client.Options, user data.User, delegateID string) []string {
+	userPRs, _ := data.ListPRsPerSlackUser(ctx, opts, true, false, true, []string{user.SlackID})
+	email := users.SlackIDToEmail(ctx, delegateID)
+
+	var prs []string
+	for _, prURL := range userPRs[user.SlackID] {
+		if _, isReviewer := data.LoadParticipantRoles(ctx, opts, prURL, user.Email); !isReviewer {
+			continue
+		}
+		if isAuthor, _ := data.LoadParticipantRoles(ctx, opts, prURL, email); isAuthor {
+			continue
+		}
+
+		ok, approved, err := data.SetReviewerTurn(ctx, opts, prURL, email, true)
+		if err != nil || approved {
+			continue
+		}
+		if !ok {
+			if _, _, err := data.SetReviewerTurn(ctx, opts, prURL, email, false); err != nil {
+				continue
+			}
+		}
+
+		if err := data.SwitchTurn(ctx, opts, prURL, user.Email, true); err != nil {
+			continue
+		}
+		prs = append(prs, prURL)
+	}
+
+	slices.Sort(prs)
+	return prs
+}
+
+// awayUntil parses the time argument of the "away" slash command, relative to the given time and timezone.
+// Unlike [snoozeUntil], it accepts only whole days, so away periods end at midnight in the user's timezone
+// (i.e. the user is back at the beginning of that day), and they may be as long as [maxAwayDuration].
+func awayUntil(now time.Time, loc *time.Location, text string) (time.Time, error) {
+	return parseUntil(now, loc, text, awaySyntax)
+}
+
+func notifyDelegate(ctx workflow.Context, userID, delegateID string, until time.Time, prs []string) {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, ":palm_tree: <@%s> is away until %s, and chose you as their delegate.", userID, SnoozeTimeText(until)) //workflowcheck:ignore // Deterministic output, not a file.
+	msg.WriteString(" It's now your turn to review these PRs:\n")
+
+	for _, url := range prs {
+		fmt.Fprintf(&msg, "\n  •   %s", url) //workflowcheck:ignore // Same as above.
+		if channelID, _ := data.SwitchURLAndID(ctx, url); channelID != "" {
+			fmt.Fprintf(&msg, " (<#%s>)", channelID) //workflowcheck:ignore // Same as above.
+		}
+	}
+
+	// Best effort: the delegate will see these PRs in their reminders and status reports anyway.
+	_ = activities.PostMessage(ctx, delegateID, msg.String())
+}
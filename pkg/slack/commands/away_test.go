@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAwayUntil(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 14, 15, 30, 0, 0, loc) // Wednesday.
+
+	tests := []struct {
+		name    string
+		text    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "days",
+			text: "for 3 days",
+			want: time.Date(2026, 1, 17, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "weeks",
+			text: "2W",
+			want: time.Date(2026, 1, 28, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "tomorrow",
+			text: "tomorrow",
+			want: time.Date(2026, 1, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "date_beyond_snooze_limit",
+			text: "until 2026-09-01",
+			want: time.Date(2026, 9, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "weekday",
+			text: "mon",
+			want: time.Date(2026, 1, 19, 0, 0, 0, 0, loc),
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+		{
+			name:    "hours",
+			text:    "2h",
+			wantErr: true,
+		},
+		{
+			name:    "today",
+			text:    "0d",
+			wantErr: true,
+		},
+		{
+			name:    "too_long",
+			text:    "53w",
+			wantErr: true,
+		},
+		{
+			name:    "huge_number",
+			text:    "9223372036854775807d",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := awayUntil(now, loc, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("awayUntil() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("awayUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
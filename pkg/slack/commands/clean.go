@@ -1,27 +1,42 @@
 package commands
 
 import (
+	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/files"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
 	"github.com/tzrikka/revchat/pkg/slack"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 )
 
-func Clean(ctx workflow.Context, event SlashCommandEvent) error {
+func Clean(ctx workflow.Context, event SlashCommandEvent, reviewerRequests bool) error {
 	url, paths, pr, err := reviewerData(ctx, event)
 	if err != nil || len(url) < 6 || len(paths) == 0 {
 		return err
 	}
-
 	workspace, repo, branch, commit := slack.PRIdentifiers(ctx, url[0], pr)
-	owners, _ := files.OwnersPerPath(ctx, workspace, repo, branch, commit, paths, true)
+	owners, _ := files.OwnersPerPath(ctx, files.NewSourceFetcher(url[0]), workspace, repo, branch, commit, paths, true)
 	reviewers := requiredReviewers(paths, owners)
+	switch {
+	case datacenter.IsURL(url[0]):
+		return cleanDataCenterPR(ctx, event, url, pr, reviewers)
+	case url[1] != "bitbucket.org":
+		if !reviewerRequests {
+			PostEphemeralError(ctx, event, "removing GitHub PR reviewers is not enabled in RevChat yet.")
+			return nil
+		}
+		return cleanGitHubPR(ctx, event, url, reviewers)
+	}
+
 	for i, fullName := range reviewers {
 		if user := data.SelectUserByRealName(ctx, fullName); user.BitbucketID != "" {
 			reviewers[i] = user.BitbucketID
@@ -66,6 +81,87 @@ func Clean(ctx workflow.Context, event SlashCommandEvent) error {
 	return nil
 }
 
+// cleanGitHubPR removes review requests from users who do not own any files in the
+// PR. Approvers don't need to be considered: GitHub removes their review requests.
+func cleanGitHubPR(ctx workflow.Context, event SlashCommandEvent, url, required []string) error {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	isRequired := make(map[string]bool, len(required))
+	for _, fullName := range required {
+		if user := data.SelectUserByRealName(ctx, fullName); user.GitHubID != "" {
+			isRequired[user.GitHubID] = true
+		} else {
+			isRequired[fullName] = true // Unknown GitHub users are identified by their logins.
+		}
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the latest PR metadata from GitHub, just in case our stored snapshot is outdated.
+	pr, err := github.GetPullRequest(ctx, user.ThrippyLink, url[0])
+	if err != nil {
+		PostEphemeralError(ctx, event, "failed to get current PR details from GitHub.")
+		return err
+	}
+
+	var remove []string
+	for _, r := range pr.RequestedReviewers {
+		if !isRequired[r.Login] {
+			remove = append(remove, r.Login)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+
+	if err := github.RemoveRequestedReviewers(ctx, user.ThrippyLink, url[2], url[3], prID, remove); err != nil {
+		PostEphemeralError(ctx, event, "failed to update PR reviewers in GitHub.")
+		return err
+	}
+
+	return nil
+}
+
+// cleanDataCenterPR removes reviewers who do not own any files in the PR, and didn't approve it.
+// Unlike Bitbucket Cloud, Data Center users are identified by their email addresses.
+func cleanDataCenterPR(ctx workflow.Context, event SlashCommandEvent, url []string, pr map[string]any, required []string) error {
+	var keep []string
+	for _, fullName := range required {
+		if id := datacenter.AccountID(data.SelectUserByRealName(ctx, fullName).Email); id != "" {
+			keep = append(keep, id)
+		}
+	}
+	keep = filterReviewers(pr, append(keep, approversForClean(pr)...))
+
+	var remove []string
+	for _, r := range allReviewers(pr) {
+		if !slices.Contains(keep, r) {
+			remove = append(remove, r)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := bbactivities.RemoveDataCenterReviewers(ctx, user.ThrippyLink, url[0], remove); err != nil {
+		PostEphemeralError(ctx, event, "failed to update PR reviewers in Bitbucket.")
+		return err
+	}
+
+	return nil
+}
+
 func requiredReviewers(paths []string, owners map[string][]string) []string {
 	var required []string
 
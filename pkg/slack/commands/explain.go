@@ -4,27 +4,45 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strconv"
 	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/files"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
 	"github.com/tzrikka/revchat/pkg/slack"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
 	"github.com/tzrikka/revchat/pkg/users"
 )
 
-func Explain(ctx workflow.Context, event SlashCommandEvent) error {
+func Explain(ctx workflow.Context, event SlashCommandEvent, listReviews bool) error {
 	url, paths, pr, err := reviewerData(ctx, event)
 	if err != nil || url == nil || len(paths) == 0 {
 		return err
 	}
 
+	src := files.NewSourceFetcher(url[0])
 	workspace, repo, branch, commit := slack.PRIdentifiers(ctx, url[0], pr)
-	owners, groups := files.OwnersPerPath(ctx, workspace, repo, branch, commit, paths, false)
+	owners, groups := files.OwnersPerPath(ctx, src, workspace, repo, branch, commit, paths, false)
+
+	isGitHub := !isBitbucketPR(url[0])
+	approvers, names := approversForExplain(ctx, pr), bitbucketApprovers(ctx, pr)
+	gotApprovals := files.GotAllRequiredApprovals
+	if isGitHub {
+		approvers, names = nil, nil
+		if listReviews {
+			approvers, names = gitHubApproversForExplain(ctx, event, url)
+		}
+		gotApprovals = files.GotAnyOwnerApprovals
+	}
+
+	msg := explainCodeOwners(ctx, paths, owners, groups, approvers)
+	if owners != nil && (!isGitHub || listReviews) {
+		msg += requiredApprovalsSummary(isGitHub, gotApprovals(ctx, src, workspace, repo, branch, commit, paths, names))
+	}
 
-	msg := explainCodeOwners(ctx, paths, owners, groups, approversForExplain(ctx, pr))
 	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 }
 
@@ -60,6 +78,55 @@ func approversForExplain(ctx workflow.Context, pr map[string]any) map[string]boo
 	return mentions
 }
 
+// gitHubApproversForExplain retrieves the current approvers of a GitHub PR, because unlike Bitbucket PR
+// snapshots, GitHub PR snapshots don't contain this information. It returns them both as Slack mentions,
+// and as real names (or GitHub logins, if unknown) which are comparable to those in "CODEOWNERS" files.
+func gitHubApproversForExplain(ctx workflow.Context, event SlashCommandEvent, url []string) (map[string]bool, []string) {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return nil, nil
+	}
+
+	user, _, err := data.SelectUserBySlackID(ctx, event.UserID)
+	if err != nil {
+		return nil, nil
+	}
+
+	reviews, err := github.ListPullRequestReviews(ctx, user.ThrippyLink, url[2], url[3], prID)
+	if err != nil {
+		return nil, nil
+	}
+
+	mentions := map[string]bool{}
+	var names []string
+	for login, r := range github.LatestReviews(reviews) {
+		if !strings.EqualFold(r.State, "approved") {
+			continue
+		}
+		mentions[users.GitHubIDToSlackRef(ctx, login, r.User.HTMLURL, r.User.Type)] = false
+		if name := data.SelectUserByGitHubID(ctx, login).RealName; name != "" {
+			names = append(names, name)
+		} else {
+			names = append(names, login)
+		}
+	} //workflowcheck:ignore
+
+	return mentions, names
+}
+
+// requiredApprovalsSummary explains whether a PR has all the approvals that its code owners are required
+// to provide: in Bitbucket each file needs approvals from all of its code owners, but in GitHub only one.
+func requiredApprovalsSummary(isGitHub, approved bool) string {
+	switch {
+	case approved:
+		return "\n\n:white_check_mark: All the required approvals of code owners are present."
+	case isGitHub:
+		return "\n\n:hourglass: Missing required approvals: each file needs an approval from at least one of its code owners."
+	default:
+		return "\n\n:hourglass: Missing required approvals: each file needs approvals from all of its code owners (in groups: at least one member)."
+	}
+}
+
 func explainCodeOwners(ctx workflow.Context, paths []string, owners, groups map[string][]string, approvers map[string]bool) string {
 	var msg strings.Builder
 	msg.WriteString(":mag_right: Code owners per file in this PR:")
@@ -49,3 +49,41 @@ func TestOwnerMention(t *testing.T) {
 		})
 	}
 }
+
+func TestRequiredApprovalsSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		isGitHub bool
+		approved bool
+		want     string
+	}{
+		{
+			name:     "bitbucket_approved",
+			approved: true,
+			want:     "\n\n:white_check_mark: All the required approvals of code owners are present.",
+		},
+		{
+			name: "bitbucket_missing",
+			want: "\n\n:hourglass: Missing required approvals: each file needs approvals from all of its code owners (in groups: at least one member).",
+		},
+		{
+			name:     "github_approved",
+			isGitHub: true,
+			approved: true,
+			want:     "\n\n:white_check_mark: All the required approvals of code owners are present.",
+		},
+		{
+			name:     "github_missing",
+			isGitHub: true,
+			want:     "\n\n:hourglass: Missing required approvals: each file needs an approval from at least one of its code owners.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiredApprovalsSummary(tt.isGitHub, tt.approved); got != tt.want {
+				t.Errorf("requiredApprovalsSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
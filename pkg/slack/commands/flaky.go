@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// defaultFlakyBuildsDays is the default time window of the "flaky" slash command.
+const defaultFlakyBuildsDays = 30
+
+// defaultRepoHosts are checked in order when the "flaky" slash command
+// receives a repository name without a host, outside of a PR channel.
+var defaultRepoHosts = []string{"bitbucket.org", "github.com"}
+
+// Flaky lists the build keys which were rerun successfully after a failure in the same commit in the
+// last N days (by default [defaultFlakyBuildsDays]), from the most to the least flaky. The repository
+// is either specified by the user, or the one of the PR channel.
+func Flaky(ctx workflow.Context, event SlashCommandEvent, args string) error {
+	repo, days, ok := parseFlakyArgs(args)
+	if !ok {
+		PostEphemeralError(ctx, event, fmt.Sprintf("the number of days must be between 1 and %d.", data.BuildHistoryRetentionDays))
+		return nil
+	}
+
+	repoURLs, err := flakyRepoURLs(ctx, event, repo)
+	if repoURLs == nil {
+		return err // May or may not be nil.
+	}
+
+	for _, repoURL := range repoURLs {
+		builds, err := data.ListFlakyBuilds(ctx, repoURL, days)
+		if err != nil {
+			PostEphemeralError(ctx, event, "failed to read the build history of this repository.")
+			return err
+		}
+		if len(builds) > 0 {
+			return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, flakyBuildsText(repoURL, builds, days))
+		}
+	}
+
+	msg := fmt.Sprintf(":sparkles: No flaky builds in the last %d days.", days)
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+}
+
+// parseFlakyArgs splits the arguments of the "flaky" slash command into an optional repository
+// and an optional number of days. It returns false if the number of days is out of range.
+func parseFlakyArgs(args string) (repo string, days int, ok bool) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return "", defaultFlakyBuildsDays, true
+	}
+
+	n, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return strings.Join(fields, " "), defaultFlakyBuildsDays, true
+	}
+
+	repo = strings.Join(fields[:len(fields)-1], " ")
+	return repo, n, n > 0 && n <= data.BuildHistoryRetentionDays
+}
+
+// flakyRepoURLs returns 1 or more candidate URLs of the repository that the user is interested in.
+func flakyRepoURLs(ctx workflow.Context, event SlashCommandEvent, repo string) ([]string, error) {
+	// Slack may format URLs as "<https://...>" or "<https://...|text>".
+	repo = strings.TrimPrefix(repo, "<")
+	repo, _, _ = strings.Cut(repo, "|")
+	repo = strings.TrimSuffix(strings.TrimSuffix(repo, ">"), "/")
+
+	switch {
+	case strings.HasPrefix(repo, "https://"):
+		if repoURL := data.RepoURL(repo); repoURL != "" {
+			return []string{repoURL}, nil
+		}
+		return []string{repo}, nil
+
+	case repo == "":
+		url, err := prDetailsFromChannel(ctx, event)
+		if url == nil {
+			return nil, err // May or may not be nil.
+		}
+		return []string{data.RepoURL(url[0])}, nil
+
+	case strings.Count(repo, "/") != 1:
+		PostEphemeralError(ctx, event, "specify a repository as `owner/repo` or as a URL.")
+		return nil, nil
+	}
+
+	// Prefer the host of the current PR channel, if there is one.
+	if prURL, _ := data.SwitchURLAndID(ctx, event.ChannelID); prURL != "" {
+		if parts := PullRequestURLPattern.FindStringSubmatch(prURL); len(parts) > 1 {
+			return []string{fmt.Sprintf("https://%s/%s", parts[1], repo)}, nil
+		}
+	}
+
+	urls := make([]string, 0, len(defaultRepoHosts))
+	for _, host := range defaultRepoHosts {
+		urls = append(urls, fmt.Sprintf("https://%s/%s", host, repo))
+	}
+	return urls, nil
+}
+
+func flakyBuildsText(repoURL string, builds []data.FlakyBuild, days int) string {
+	var msg strings.Builder
+	repo := strings.TrimPrefix(repoURL, "https://")
+	fmt.Fprintf(&msg, ":game_die: Flakiest builds in <%s|%s> in the last %d days:\n", repoURL, repo, days) //workflowcheck:ignore // Deterministic output, not a file.
+
+	for _, b := range builds {
+		fmt.Fprintf(&msg, "\n  •   %q - failed and then passed %d times, in %d commits", b.Name, b.Flips, b.Commits) //workflowcheck:ignore // Same as above.
+		if b.Name != b.Key {
+			fmt.Fprintf(&msg, " (key: `%s`)", b.Key) //workflowcheck:ignore // Same as above.
+		}
+	}
+
+	return msg.String()
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestParseFlakyArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		wantRepo string
+		wantDays int
+		wantOK   bool
+	}{
+		{
+			name:     "empty",
+			wantDays: defaultFlakyBuildsDays,
+			wantOK:   true,
+		},
+		{
+			name:     "repo",
+			args:     "owner/repo",
+			wantRepo: "owner/repo",
+			wantDays: defaultFlakyBuildsDays,
+			wantOK:   true,
+		},
+		{
+			name:     "days",
+			args:     " 7 ",
+			wantDays: 7,
+			wantOK:   true,
+		},
+		{
+			name:     "repo_and_days",
+			args:     "<https://github.com/owner/repo> 14",
+			wantRepo: "<https://github.com/owner/repo>",
+			wantDays: 14,
+			wantOK:   true,
+		},
+		{
+			name:     "zero_days",
+			args:     "owner/repo 0",
+			wantRepo: "owner/repo",
+		},
+		{
+			name:     "too_many_days",
+			args:     "91",
+			wantDays: 91,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, days, ok := parseFlakyArgs(tt.args)
+			if repo != tt.wantRepo || days != tt.wantDays || ok != tt.wantOK {
+				t.Errorf("parseFlakyArgs() = (%q, %d, %v), want (%q, %d, %v)", repo, days, ok, tt.wantRepo, tt.wantDays, tt.wantOK)
+			}
+		})
+	}
+}
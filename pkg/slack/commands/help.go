@@ -18,13 +18,22 @@ func Help(ctx workflow.Context, event SlashCommandEvent) error {
 	cmds.WriteString("\n  •   `%s follow <1 or more @users or @groups>` - auto add yourself to PRs they create")
 	cmds.WriteString("\n  •   `%s unfollow <1 or more @users or @groups>` - stop following their PR channels")
 	cmds.WriteString("\n  •   `%s status` - all the PRs you need to look at, as an author or a reviewer")
+	cmds.WriteString("\n  •   `%s flaky [owner/repo or URL] [days]` - builds that often pass only after a rerun")
+	cmds.WriteString("\n  •   `%s snoozed` - PRs that you snoozed, and until when")
+	cmds.WriteString("\n  •   `%s away until <date or duration> [delegate @user]` / `away delegate @user` / `back` - out-of-office mode")
 	cmds.WriteString("\n\nMore commands inside PR channels:\n")
 	cmds.WriteString("\n  •   `%s who` / `whose turn` / `my turn` / `not my turn` / `[un]freeze [turns]`")
+	cmds.WriteString("\n  •   `%s snooze <duration or date>` / `unsnooze` - hide the PR from your reminders")
 	cmds.WriteString("\n  •   `%s nudge <1 or more @users or @groups>` / `ping <...>` / `poke <...>`")
 	cmds.WriteString("\n  •   `%s explain` - who needs to approve each file, and have they?")
+	cmds.WriteString("\n  •   `%s suggest` - fewest available code owners who can review all the files")
+	cmds.WriteString("\n  •   `%s reviewers add <1 or more @users or @groups>` / `reviewers remove <...>`")
 	cmds.WriteString("\n  •   `%s clean` - remove unnecessary reviewers from the PR")
 	cmds.WriteString("\n  •   `%s approve` or `lgtm` or `+1`")
 	cmds.WriteString("\n  •   `%s unapprove` or `-1`")
+	cmds.WriteString("\n  •   `%s task <text>` - create a task in a Bitbucket PR")
+	cmds.WriteString("\n  •   `%s merge [merge|squash|fast-forward] [close-branch]` - if builds and approvals allow it")
+	cmds.WriteString("\n  •   `%s decline [reason]` - decline/close the PR, optionally with a comment")
 
 	msg := strings.ReplaceAll(cmds.String(), "%s", event.Command)
 	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
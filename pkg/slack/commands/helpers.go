@@ -17,14 +17,14 @@ import (
 
 var (
 	// PullRequestURLPattern is a regular expression that supports PR and comment URLs in Bitbucket and GitHub:
-	//  1. Hostname (e.g., "bitbucket.org", "github.com")
-	//  2. Bitbucket workspace / GitHub owner
+	//  1. Hostname (e.g., "bitbucket.org", "github.com", or a Bitbucket Data Center host)
+	//  2. Bitbucket workspace / Bitbucket Data Center project key / GitHub owner
 	//  3. Repository
 	//  4. Partial PR path ("" in GitHub / "-requests" in Bitbucket)
 	//  5. PR number
 	//  6. Optional suffix for comments
 	//  7. Numeric comment ID (within 6, if it's not empty)
-	PullRequestURLPattern = regexp.MustCompile(`https://([^/]+)/([^/]+)/([^/]+)/pull(-requests)?/(\d+)([^\s\d]+(\d+))?`)
+	PullRequestURLPattern = regexp.MustCompile(`https?://([^/]+)/(?:projects/)?([^/]+)/(?:repos/)?([^/]+)/pull(-requests)?/(\d+)([^\s\d]+(\d+))?`)
 
 	userOrGroupIDPattern = regexp.MustCompile(`<(@|!subteam\^)(\w+)(\|[^>]*)?>`)
 )
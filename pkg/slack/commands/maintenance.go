@@ -8,6 +8,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	github "github.com/tzrikka/revchat/pkg/github/activities"
 	slack "github.com/tzrikka/revchat/pkg/slack/activities"
@@ -221,10 +222,18 @@ func determineURL(ctx workflow.Context, alertsChannel, userID, channelID string,
 }
 
 func isBitbucketPR(url string) bool {
-	return strings.HasPrefix(url, "https://bitbucket.org/")
+	return strings.HasPrefix(url, "https://bitbucket.org/") || datacenter.IsURL(url)
 }
 
 func isPROpen(ctx workflow.Context, url string) (bool, error) {
+	if datacenter.IsURL(url) {
+		pr, err := bitbucket.GetDataCenterPullRequest(ctx, "", url)
+		if err != nil {
+			return false, err
+		}
+		return pr.State == "OPEN", nil
+	}
+
 	if isBitbucketPR(url) {
 		pr, err := bitbucket.GetPullRequest(ctx, "", url)
 		if err != nil {
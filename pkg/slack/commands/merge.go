@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/files"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/timpani-api/pkg/bitbucket"
+)
+
+// mergeOptions are the optional arguments of the "merge" slash command.
+type mergeOptions struct {
+	strategy    string // "merge", "squash", "fast-forward", or empty for the repository's default.
+	closeBranch bool
+}
+
+// bitbucketMergeStrategies maps "merge" command arguments to Bitbucket merge strategies.
+var bitbucketMergeStrategies = map[string]string{
+	"merge":        "merge_commit",
+	"squash":       "squash",
+	"fast-forward": "fast_forward",
+}
+
+// dataCenterMergeStrategies maps "merge" command arguments to Bitbucket Data Center merge strategy IDs.
+var dataCenterMergeStrategies = map[string]string{
+	"merge":        "no-ff",
+	"squash":       "squash",
+	"fast-forward": "ff-only",
+}
+
+// githubMergeMethods maps "merge" command arguments to GitHub merge methods.
+// GitHub doesn't support fast-forward merges, rebasing is the closest thing.
+var githubMergeMethods = map[string]string{
+	"merge":        "merge",
+	"squash":       "squash",
+	"fast-forward": "rebase",
+}
+
+// Merge merges the current channel's PR on behalf of the user, unless some of its builds
+// failed or are outdated, or it's missing required code-owner approvals.
+func Merge(ctx workflow.Context, event SlashCommandEvent, args string, listReviews, deleteBranches bool) error {
+	opts, err := parseMergeArgs(args)
+	if err != nil {
+		PostEphemeralError(ctx, event, fmt.Sprintf("%s - try `%s merge [merge|squash|fast-forward] [close-branch]`", err, event.Command))
+		return nil
+	}
+
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+	// Unlike Bitbucket Cloud, Bitbucket Data Center's merge API can't delete the source branch.
+	if opts.closeBranch && datacenter.IsURL(url[0]) {
+		PostEphemeralError(ctx, event, "`close-branch` is not supported in Bitbucket Data Center PRs yet.")
+		return nil
+	}
+	// Unlike Bitbucket Cloud, GitHub's merge API can't delete the source branch either.
+	if opts.closeBranch && url[1] != "bitbucket.org" && !datacenter.IsURL(url[0]) && !deleteBranches {
+		PostEphemeralError(ctx, event, "`close-branch` in GitHub PRs is not enabled in RevChat yet.")
+		return nil
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	pr, err := data.LoadPRSnapshot(ctx, url[0])
+	if err != nil {
+		PostEphemeralError(ctx, event, "failed to load PR snapshot.")
+		return err
+	}
+
+	if reasons := mergeBlockers(ctx, event, user, url, pr, listReviews); len(reasons) > 0 {
+		msg := ":no_entry: This PR can't be merged yet:\n\n  •   " + strings.Join(reasons, "\n  •   ")
+		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+	}
+
+	switch {
+	case datacenter.IsURL(url[0]):
+		err = bbactivities.MergeDataCenterPullRequest(ctx, user.ThrippyLink, url[0], dataCenterMergeStrategies[opts.strategy])
+	case url[1] == "bitbucket.org":
+		err = bitbucket.PullRequestsMerge(ctx, bitbucket.PullRequestsMergeRequest{
+			PullRequestsRequest: bitbucket.PullRequestsRequest{
+				ThrippyLinkID: user.ThrippyLink, Workspace: url[2], RepoSlug: url[3], PullRequestID: url[5],
+			},
+			MergeStrategy:     bitbucketMergeStrategies[opts.strategy],
+			CloseSourceBranch: opts.closeBranch,
+		})
+	default:
+		err = mergeGitHubPR(ctx, user, url, pr, opts)
+	}
+
+	if err != nil {
+		logger.From(ctx).Error("failed to merge PR", slog.Any("error", err), slog.String("pr_url", url[0]),
+			slog.String("slack_user_id", event.UserID), slog.String("thrippy_id", user.ThrippyLink))
+		PostEphemeralError(ctx, event, "failed to merge "+url[0])
+		return err
+	}
+
+	// No need to post a confirmation message, the resulting Bitbucket/GitHub event will trigger that.
+	return nil
+}
+
+// Decline declines (in GitHub: closes without merging) the current channel's PR on behalf
+// of the user. If a reason is specified, it's posted as a PR comment before declining.
+func Decline(ctx workflow.Context, event SlashCommandEvent, reason string) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case datacenter.IsURL(url[0]):
+		err = declineDataCenterPR(ctx, user, url, reason)
+	case url[1] == "bitbucket.org":
+		err = declineBitbucketPR(ctx, user, url, reason)
+	default:
+		err = declineGitHubPR(ctx, user, url, reason)
+	}
+
+	if err != nil {
+		logger.From(ctx).Error("failed to decline PR", slog.Any("error", err), slog.String("pr_url", url[0]),
+			slog.String("slack_user_id", event.UserID), slog.String("thrippy_id", user.ThrippyLink))
+		PostEphemeralError(ctx, event, "failed to decline "+url[0])
+		return err
+	}
+
+	// No need to post a confirmation message, the resulting Bitbucket/GitHub event will trigger that.
+	return nil
+}
+
+func parseMergeArgs(args string) (mergeOptions, error) {
+	opts := mergeOptions{}
+	for arg := range strings.FieldsSeq(strings.ToLower(args)) {
+		switch {
+		case arg == "close-branch":
+			opts.closeBranch = true
+		case bitbucketMergeStrategies[arg] == "":
+			return opts, fmt.Errorf("unrecognized argument `%s`", arg)
+		case opts.strategy != "":
+			return opts, errors.New("more than one merge strategy")
+		default:
+			opts.strategy = arg
+		}
+	}
+	return opts, nil
+}
+
+// mergeBlockers returns human-readable reasons why the given PR must not be merged yet:
+// failed or stopped builds, builds of an older commit, and missing approvals of required code
+// owners (if the destination branch has a "CODEOWNERS" file). An empty list means that it's OK to merge.
+func mergeBlockers(ctx workflow.Context, event SlashCommandEvent, user data.User, url []string, pr map[string]any, listReviews bool) []string {
+	var reasons []string
+	status := data.ReadBuilds(ctx, url[0])
+	if outdatedBuilds(status, data.PRCommitHash(pr)) {
+		reasons = append(reasons, "Builds of the latest commit haven't been reported yet")
+	} else {
+		for _, name := range failedBuilds(status) {
+			reasons = append(reasons, fmt.Sprintf("Build %q didn't succeed", name))
+		}
+	}
+
+	workspace, repo, branch, commit := slack.PRIdentifiers(ctx, url[0], pr)
+	src := files.NewSourceFetcher(url[0])
+	owners, err := src.CodeOwners(ctx, workspace, repo, branch, commit, false)
+	if err != nil {
+		// Don't fail open: a transient error shouldn't let a PR skip its required approvals.
+		return append(reasons, "Failed to read the `CODEOWNERS` file, so required approvals can't be checked")
+	}
+	if owners == nil {
+		return reasons // No code owners, so there are no required approvals.
+	}
+
+	// Bitbucket requires approvals from all the code owners of each file, GitHub from at least one of them.
+	approvers, gotApprovals := bitbucketApprovers(ctx, pr), files.GotAllRequiredApprovals
+	if url[1] != "bitbucket.org" && !datacenter.IsURL(url[0]) {
+		if !listReviews {
+			return append(reasons, "GitHub reviews can't be looked up, so required approvals can't be checked")
+		}
+		approvers, gotApprovals = gitHubApprovers(ctx, user, url), files.GotAnyOwnerApprovals
+	}
+
+	paths := data.LoadDiffstatPaths(ctx, url[0])
+	if len(paths) > 0 && !gotApprovals(ctx, src, workspace, repo, branch, commit, paths, approvers) {
+		reasons = append(reasons, fmt.Sprintf("Missing required approvals of code owners - see `%s explain` for details", event.Command))
+	}
+
+	return reasons
+}
+
+// outdatedBuilds checks whether the given builds belong to an older commit than the PR's latest one
+// (the hash in the PR snapshot), i.e. the builds of its latest commit haven't been reported yet.
+func outdatedBuilds(status data.PRStatus, headHash string) bool {
+	// The hash from build events is always the full commit hash, but the one in the snapshot may be truncated.
+	return status.CommitHash != "" && headHash != "" && !strings.HasPrefix(status.CommitHash, headHash)
+}
+
+// failedBuilds returns the sorted names of builds whose state is "FAILED" or "STOPPED".
+func failedBuilds(status data.PRStatus) []string {
+	var names []string
+	for _, b := range status.Builds { //workflowcheck:ignore
+		if b.State == "FAILED" || b.State == "STOPPED" {
+			names = append(names, b.Name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+// bitbucketApprovers returns the real names of a Bitbucket PR's approvers, based on its snapshot.
+// These are the same names as in "CODEOWNERS" files, for [files.GotAllRequiredApprovals].
+func bitbucketApprovers(ctx workflow.Context, pr map[string]any) []string {
+	participants, ok := pr["participants"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for _, p := range participants {
+		participant, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if approved, _ := participant["approved"].(bool); !approved {
+			continue
+		}
+
+		u, ok := participant["user"].(map[string]any)
+		if !ok {
+			continue
+		}
+		accountID, _ := u["account_id"].(string)
+		name := data.SelectUserByBitbucketID(ctx, accountID).RealName
+		if name == "" {
+			name, _ = u["display_name"].(string)
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// gitHubApprovers retrieves the real names of a GitHub PR's current approvers, because
+// unlike Bitbucket PR snapshots, GitHub PR snapshots don't contain this information.
+func gitHubApprovers(ctx workflow.Context, user data.User, url []string) []string {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return nil
+	}
+
+	reviews, err := github.ListPullRequestReviews(ctx, user.ThrippyLink, url[2], url[3], prID)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for login, r := range github.LatestReviews(reviews) {
+		if !strings.EqualFold(r.State, "approved") {
+			continue
+		}
+		if name := data.SelectUserByGitHubID(ctx, login).RealName; name != "" {
+			names = append(names, name)
+		} else {
+			names = append(names, login)
+		}
+	} //workflowcheck:ignore
+
+	return names
+}
+
+// mergeGitHubPR merges a GitHub PR, and optionally deletes its source branch afterwards (GitHub's merge
+// API doesn't support this directly). Branches in forked repositories are not deleted, by design.
+func mergeGitHubPR(ctx workflow.Context, user data.User, url []string, pr map[string]any, opts mergeOptions) error {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	if err := github.MergePullRequest(ctx, user.ThrippyLink, url[2], url[3], prID, githubMergeMethods[opts.strategy]); err != nil {
+		return err
+	}
+	if !opts.closeBranch {
+		return nil
+	}
+
+	head, _ := pr["head"].(map[string]any)
+	ref, _ := head["ref"].(string)
+	headRepo, _ := head["repo"].(map[string]any)
+	if fullName, _ := headRepo["full_name"].(string); ref == "" || !strings.EqualFold(fullName, url[2]+"/"+url[3]) {
+		return nil
+	}
+
+	// The PR is already merged, so this is not a critical error.
+	_ = github.DeleteBranch(ctx, user.ThrippyLink, url[2], url[3], ref)
+	return nil
+}
+
+func declineDataCenterPR(ctx workflow.Context, user data.User, url []string, reason string) error {
+	if reason != "" {
+		if _, err := bbactivities.CreateDataCenterComment(ctx, user.ThrippyLink, url[0], reason); err != nil {
+			return err
+		}
+	}
+
+	return bbactivities.DeclineDataCenterPullRequest(ctx, user.ThrippyLink, url[0])
+}
+
+func declineBitbucketPR(ctx workflow.Context, user data.User, url []string, reason string) error {
+	if reason != "" {
+		if _, err := bbactivities.CreatePullRequestComment(ctx, user.ThrippyLink, url[2], url[3], url[5], "", reason); err != nil {
+			return err
+		}
+	}
+
+	return bitbucket.PullRequestsDecline(ctx, user.ThrippyLink, url[2], url[3], url[5])
+}
+
+func declineGitHubPR(ctx workflow.Context, user data.User, url []string, reason string) error {
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	if reason != "" {
+		if _, err := github.CreateIssueComment(ctx, user.ThrippyLink, url[2], url[3], prID, reason); err != nil {
+			return err
+		}
+	}
+
+	return github.ClosePullRequest(ctx, user.ThrippyLink, url[2], url[3], prID)
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+func TestParseMergeArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    mergeOptions
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "strategy",
+			args: "squash",
+			want: mergeOptions{strategy: "squash"},
+		},
+		{
+			name: "close_branch",
+			args: " Close-Branch ",
+			want: mergeOptions{closeBranch: true},
+		},
+		{
+			name: "both",
+			args: "fast-forward close-branch",
+			want: mergeOptions{strategy: "fast-forward", closeBranch: true},
+		},
+		{
+			name:    "unrecognized",
+			args:    "rebase",
+			wantErr: true,
+		},
+		{
+			name:    "multiple_strategies",
+			args:    "merge squash",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMergeArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMergeArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseMergeArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailedBuilds(t *testing.T) {
+	tests := []struct {
+		name   string
+		status data.PRStatus
+		want   []string
+	}{
+		{
+			name: "no_builds",
+		},
+		{
+			name: "all_successful",
+			status: data.PRStatus{Builds: map[string]data.CommitStatus{
+				"a": {Name: "a", State: "SUCCESSFUL"},
+				"b": {Name: "b", State: "INPROGRESS"},
+			}},
+		},
+		{
+			name: "failed_and_stopped",
+			status: data.PRStatus{Builds: map[string]data.CommitStatus{
+				"c": {Name: "c", State: "STOPPED"},
+				"b": {Name: "b", State: "SUCCESSFUL"},
+				"a": {Name: "a", State: "FAILED"},
+			}},
+			want: []string{"a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedBuilds(tt.status); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("failedBuilds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutdatedBuilds(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   data.PRStatus
+		headHash string
+		want     bool
+	}{
+		{
+			name:     "no_builds",
+			headHash: "abc123",
+		},
+		{
+			name:   "no_head_hash",
+			status: data.PRStatus{CommitHash: "abc123def456"},
+		},
+		{
+			name:     "same_commit",
+			status:   data.PRStatus{CommitHash: "abc123def456"},
+			headHash: "abc123def456",
+		},
+		{
+			name:     "same_commit_truncated",
+			status:   data.PRStatus{CommitHash: "abc123def456"},
+			headHash: "abc123",
+		},
+		{
+			name:     "older_commit",
+			status:   data.PRStatus{CommitHash: "abc123def456"},
+			headHash: "789abc",
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outdatedBuilds(tt.status, tt.headHash); got != tt.want {
+				t.Errorf("outdatedBuilds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
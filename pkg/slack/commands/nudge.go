@@ -96,7 +96,7 @@ func authorSlackID(ctx workflow.Context, prURL string) string {
 	return users.BitbucketIDToSlackID(ctx, accountID, true)
 }
 
-// checkAndNudgeUser ensures that the user exists, is opted-in, and is a reviewer of the PR.
+// checkAndNudgeUser ensures that the user exists, is opted-in, isn't away, and is a reviewer of the PR.
 // It returns true if the attention state was updated. If not, it also posts an explanation,
 // with the exception of self-nudges which are silently ignored (the user is nudging a group
 // that they are also part of, when the user nudges only themselves this function isn't called).
@@ -116,6 +116,10 @@ func checkAndNudgeUser(ctx workflow.Context, opts client.Options, event SlashCom
 		_ = activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 		return false
 	}
+	if msg := AwayText(ctx, user); msg != "" {
+		_ = activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+		return false
+	}
 
 	// Update the PR's attention state.
 	ok, approved, err := data.SetReviewerTurn(ctx, opts, url, user.Email, true)
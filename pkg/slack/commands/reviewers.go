@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// AddReviewers adds the mentioned users (and the members of mentioned user groups)
+// as reviewers of the channel's PR, on behalf of the user who ran the slash command.
+func AddReviewers(ctx workflow.Context, event SlashCommandEvent, reviewerRequests bool) error {
+	return updateReviewers(ctx, event, true, reviewerRequests)
+}
+
+// RemoveReviewers removes the mentioned users (and the members of mentioned user groups)
+// from the reviewers of the channel's PR, on behalf of the user who ran the slash command.
+func RemoveReviewers(ctx workflow.Context, event SlashCommandEvent, reviewerRequests bool) error {
+	return updateReviewers(ctx, event, false, reviewerRequests)
+}
+
+func updateReviewers(ctx workflow.Context, event SlashCommandEvent, add, reviewerRequests bool) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+	if url[1] != "bitbucket.org" && !datacenter.IsURL(url[0]) && !reviewerRequests {
+		PostEphemeralError(ctx, event, "updating GitHub PR reviewers is not enabled in RevChat yet.")
+		return nil
+	}
+
+	userIDs := extractAtLeastOneUserID(ctx, event)
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	user, optedIn, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+	if !optedIn {
+		PostEphemeralError(ctx, event, "you need to opt-in first.")
+		return nil
+	}
+
+	done, skipped, err := slack.UpdateReviewers(ctx, user.ThrippyLink, url[0], userIDs, add)
+	if err != nil {
+		PostEphemeralError(ctx, event, "failed to update the PR's reviewers.")
+		return err
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, updateReviewersText(done, skipped, add))
+}
+
+func updateReviewersText(done, skipped []string, add bool) string {
+	var msg strings.Builder
+	if len(done) > 0 {
+		action := "Added reviewers"
+		if !add {
+			action = "Removed reviewers"
+		}
+		fmt.Fprintf(&msg, ":white_check_mark: %s: <@%s>.", action, strings.Join(done, ">, <@")) //workflowcheck:ignore // Deterministic output, not a file.
+	}
+
+	if len(skipped) > 0 {
+		if msg.Len() > 0 {
+			msg.WriteString("\n\n")
+		}
+		//workflowcheck:ignore // Same as above.
+		fmt.Fprintf(&msg, ":warning: Skipped users who aren't linked to a PR account (or are the PR's author): <@%s>.", strings.Join(skipped, ">, <@"))
+	}
+
+	return msg.String()
+}
@@ -0,0 +1,50 @@
+package commands
+
+import "testing"
+
+func TestUpdateReviewersText(t *testing.T) {
+	tests := []struct {
+		name    string
+		done    []string
+		skipped []string
+		add     bool
+		want    string
+	}{
+		{
+			name: "nothing",
+		},
+		{
+			name: "added",
+			done: []string{"U1", "U2"},
+			add:  true,
+			want: ":white_check_mark: Added reviewers: <@U1>, <@U2>.",
+		},
+		{
+			name: "removed",
+			done: []string{"U1"},
+			want: ":white_check_mark: Removed reviewers: <@U1>.",
+		},
+		{
+			name:    "skipped",
+			skipped: []string{"U3"},
+			add:     true,
+			want:    ":warning: Skipped users who aren't linked to a PR account (or are the PR's author): <@U3>.",
+		},
+		{
+			name:    "added_and_skipped",
+			done:    []string{"U1"},
+			skipped: []string{"U3", "U4"},
+			add:     true,
+			want: ":white_check_mark: Added reviewers: <@U1>.\n\n" +
+				":warning: Skipped users who aren't linked to a PR account (or are the PR's author): <@U3>, <@U4>.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updateReviewersText(tt.done, tt.skipped, tt.add); got != tt.want {
+				t.Errorf("updateReviewersText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/users"
+	tslack "github.com/tzrikka/timpani-api/pkg/slack"
+)
+
+// maxSnoozeDuration prevents users from snoozing PRs and forgetting about them.
+const maxSnoozeDuration = 90 * 24 * time.Hour
+
+var snoozeDurationPattern = regexp.MustCompile(`^(\d+)\s*(m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?)$`)
+
+// Snooze hides the channel's PR from the calling user's reminders and status reports until the given time,
+// without changing anyone's turn. The time is either a duration (e.g. "2h", "3d", "1w"), a date (e.g.
+// "2026-01-31"), "tomorrow", or a weekday (e.g. "monday") - the last 3 are midnight in the user's timezone.
+func Snooze(ctx workflow.Context, opts client.Options, event SlashCommandEvent, text string) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+
+	user, optedIn, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+	if !optedIn {
+		PostEphemeralError(ctx, event, "you need to opt-in first.")
+		return nil
+	}
+
+	until, err := snoozeUntil(workflow.Now(ctx), userLocation(ctx, event.UserID), text)
+	if err != nil {
+		PostEphemeralError(ctx, event, err.Error()+".")
+		return nil // Not a server error as far as we're concerned.
+	}
+
+	if err := data.SnoozePR(ctx, opts, url[0], user.Email, until); err != nil {
+		PostEphemeralError(ctx, event, "failed to write internal data about this PR.")
+		return err
+	}
+
+	msg := fmt.Sprintf(":zzz: This PR is snoozed for you until %s.\n\n", SnoozeTimeText(until))
+	msg += "It's hidden from your reminders and status reports, but nobody's turn has changed."
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+}
+
+// Unsnooze cancels the calling user's snooze of the channel's PR, if there is one.
+func Unsnooze(ctx workflow.Context, opts client.Options, event SlashCommandEvent) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	if data.GetSnoozeTime(ctx, opts, url[0], user.Email).IsZero() {
+		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, ":joy: This PR isn't snoozed anyway!")
+	}
+
+	if err := data.SnoozePR(ctx, opts, url[0], user.Email, time.Time{}); err != nil {
+		PostEphemeralError(ctx, event, "failed to write internal data about this PR.")
+		return err
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, ":alarm_clock: This PR is no longer snoozed for you.")
+}
+
+// Snoozed lists the PRs that the calling user snoozed, and when each snooze expires.
+func Snoozed(ctx workflow.Context, opts client.Options, event SlashCommandEvent) error {
+	prs := data.ListSnoozedPRs(ctx, opts, users.SlackIDToEmail(ctx, event.UserID))
+	if len(prs) == 0 {
+		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, ":sunny: You haven't snoozed any PRs.")
+	}
+
+	var msg strings.Builder
+	msg.WriteString(":zzz: PRs that you snoozed:\n")
+
+	urls := slices.Sorted(maps.Keys(prs)) //workflowcheck:ignore // Sorted for deterministic order.
+	for _, url := range urls {
+		fmt.Fprintf(&msg, "\n  •   %s - until %s", url, SnoozeTimeText(prs[url])) //workflowcheck:ignore // Deterministic output, not a file.
+		if channelID, _ := data.SwitchURLAndID(ctx, url); channelID != "" {
+			fmt.Fprintf(&msg, " (<#%s>)", channelID) //workflowcheck:ignore // Same as above.
+		}
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg.String())
+}
+
+// WithoutSnoozedPRs removes from the given list of PR URLs the PRs that the given user snoozed.
+// It returns the remaining PRs, and the number of removed ones. The input slice is modified.
+func WithoutSnoozedPRs(ctx workflow.Context, opts client.Options, userID string, prs []string) ([]string, int) {
+	email := users.SlackIDToEmail(ctx, userID)
+	count := len(prs)
+	prs = slices.DeleteFunc(prs, func(url string) bool {
+		return !data.GetSnoozeTime(ctx, opts, url, email).IsZero()
+	})
+	return prs, count - len(prs)
+}
+
+// SnoozeTimeText formats the expiry time of a snooze in the reader's timezone.
+func SnoozeTimeText(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", t.Unix(), t.UTC().Format(time.RFC1123))
+}
+
+// userLocation returns the timezone of the given Slack user, or UTC if it's unknown.
+func userLocation(ctx workflow.Context, userID string) *time.Location {
+	user, err := tslack.UsersInfo(ctx, userID)
+	if err != nil || user.TZ == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(user.TZ)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// snoozeUntil parses the argument of the "snooze" slash command, relative to the given time and timezone.
+func snoozeUntil(now time.Time, loc *time.Location, text string) (time.Time, error) {
+	return parseUntil(now, loc, text, snoozeSyntax)
+}
+
+// untilSyntax describes the time argument of a slash command, for [parseUntil].
+type untilSyntax struct {
+	durationPattern *regexp.Regexp // A number and a unit (see [durationUnits]).
+	wholeDays       bool           // Durations end at midnight in the user's timezone.
+	maxDuration     time.Duration
+
+	usage   string // Error message if the argument is empty.
+	noun    string // E.g. "snooze time", in error messages.
+	tooLong string // Error message format, with the maximum duration in days.
+}
+
+var (
+	durationUnits = map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+
+	snoozeSyntax = untilSyntax{
+		durationPattern: snoozeDurationPattern,
+		maxDuration:     maxSnoozeDuration,
+		usage:           "specify a duration (e.g. `2h`, `3d`, `1w`), a date (e.g. `2026-01-31`), `tomorrow`, or a weekday",
+		noun:            "snooze time",
+		tooLong:         "you can snooze a PR for up to %d days",
+	}
+)
+
+// parseUntil parses a time argument of a slash command, relative to the given time and timezone: a duration,
+// a date, "tomorrow", or a weekday (the last 3 are midnight in the given timezone). It may be prefixed with
+// "until" or "for", and the result must be in the future, within the maximum duration of the given syntax.
+func parseUntil(now time.Time, loc *time.Location, text string, s untilSyntax) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(text, "until "), "for "))
+	if text == "" {
+		return time.Time{}, errors.New(s.usage)
+	}
+
+	now = now.In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	duration := s.durationPattern.FindStringSubmatch(text)
+	date, dateErr := time.ParseInLocation(time.DateOnly, text, loc)
+	weekday, isWeekday := parseWeekday(text)
+	tooLong := fmt.Errorf(s.tooLong, int(s.maxDuration.Hours()/24))
+
+	var until time.Time
+	switch {
+	case duration != nil:
+		n, err := strconv.Atoi(duration[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid number: `%s`", duration[1])
+		}
+		unit := durationUnits[duration[2][0]]
+		if n > int(s.maxDuration/unit) {
+			return time.Time{}, tooLong // Before the multiplication below, which might overflow.
+		}
+		if s.wholeDays {
+			until = midnight.AddDate(0, 0, n*int(unit/(24*time.Hour)))
+		} else {
+			until = now.Add(time.Duration(n) * unit)
+		}
+	case text == "tomorrow":
+		until = midnight.AddDate(0, 0, 1)
+	case dateErr == nil:
+		until = date
+	case isWeekday:
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		until = midnight.AddDate(0, 0, days)
+	default:
+		return time.Time{}, fmt.Errorf("unrecognized %s: `%s`", s.noun, text)
+	}
+
+	switch {
+	case !until.After(now):
+		return time.Time{}, fmt.Errorf("the %s must be in the future", s.noun)
+	case until.Sub(now) > s.maxDuration:
+		return time.Time{}, tooLong
+	}
+
+	return until.UTC(), nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return time.Sunday, false
+}
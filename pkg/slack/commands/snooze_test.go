@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeUntil(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 1, 14, 15, 30, 0, 0, loc) // Wednesday.
+
+	tests := []struct {
+		name    string
+		text    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "minutes",
+			text: "30m",
+			want: now.Add(30 * time.Minute),
+		},
+		{
+			name: "hours_with_prefix",
+			text: "for 2 hours",
+			want: now.Add(2 * time.Hour),
+		},
+		{
+			name: "days",
+			text: "3d",
+			want: now.AddDate(0, 0, 3),
+		},
+		{
+			name: "weeks",
+			text: "1W",
+			want: now.AddDate(0, 0, 7),
+		},
+		{
+			name: "tomorrow",
+			text: "tomorrow",
+			want: time.Date(2026, 1, 15, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "date",
+			text: "until 2026-01-20",
+			want: time.Date(2026, 1, 20, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "weekday",
+			text: "monday",
+			want: time.Date(2026, 1, 19, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "same_weekday",
+			text: "wed",
+			want: time.Date(2026, 1, 21, 0, 0, 0, 0, loc),
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+		{
+			name:    "past_date",
+			text:    "2026-01-01",
+			wantErr: true,
+		},
+		{
+			name:    "too_long",
+			text:    "20w",
+			wantErr: true,
+		},
+		{
+			name:    "overflow",
+			text:    "2061647829418496h", // Wraps around to about 2 hours if multiplied as-is.
+			wantErr: true,
+		},
+		{
+			name:    "unrecognized",
+			text:    "later",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := snoozeUntil(now, loc, tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("snoozeUntil() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("snoozeUntil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -25,10 +25,10 @@ func SelfStatus(ctx workflow.Context, opts client.Options, event SlashCommandEve
 		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID,
 			":joy: No PRs require your attention at this time!")
 	}
-	prs := userPRs[event.UserID]
+	prs, snoozed := WithoutSnoozedPRs(ctx, opts, event.UserID, userPRs[event.UserID])
 	if len(prs) == 0 {
 		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID,
-			":joy: No PRs require your attention at this time!")
+			":joy: No PRs require your attention at this time!"+snoozedPRsNote(snoozed))
 	}
 
 	var list strings.Builder
@@ -58,7 +58,18 @@ func SelfStatus(ctx workflow.Context, opts client.Options, event SlashCommandEve
 		msg = "\n:joy: No PRs require your attention at this time!"
 	}
 
-	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg+snoozedPRsNote(snoozed))
+}
+
+func snoozedPRsNote(snoozed int) string {
+	switch snoozed {
+	case 0:
+		return ""
+	case 1:
+		return "\n\n:zzz: 1 snoozed PR is hidden, see `/revchat snoozed`."
+	default:
+		return fmt.Sprintf("\n\n:zzz: %d snoozed PRs are hidden, see `/revchat snoozed`.", snoozed)
+	}
 }
 
 // StatusOfOthers is similar to [SelfStatus] but lists all the PRs associated with the given users
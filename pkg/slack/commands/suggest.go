@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+)
+
+// Suggest lists the minimal set of available code owners who can review all the files in the PR.
+func Suggest(ctx workflow.Context, opts client.Options, event SlashCommandEvent, maxLoad int) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+
+	suggestions, err := slack.SuggestReviewers(ctx, opts, url[0], maxLoad)
+	if err != nil {
+		PostEphemeralError(ctx, event, "failed to analyze the code owners of this PR.")
+		return err
+	}
+
+	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, suggestionsText(ctx, suggestions))
+}
+
+func suggestionsText(ctx workflow.Context, s *slack.ReviewerSuggestions) string {
+	if len(s.Owners) == 0 && len(s.Uncovered) == 0 {
+		return ":shrug: No code owners found for the files in this PR."
+	}
+
+	var msg strings.Builder
+	if len(s.Owners) > 0 {
+		msg.WriteString(":bulb: Suggested reviewers, who together own all the files in this PR:\n")
+	}
+	for _, name := range s.Owners {
+		msg.WriteString("\n  •   " + realNameMention(ctx, name))
+		if slices.Contains(s.Reviewers, name) {
+			msg.WriteString(" (already a reviewer)")
+		}
+	}
+
+	if len(s.Uncovered) > 0 {
+		msg.WriteString("\n\n:warning: No available code owners for these files:\n")
+		for _, p := range s.Uncovered {
+			fmt.Fprintf(&msg, "\n  •   `%s`", p) //workflowcheck:ignore // Deterministic output, not a file.
+		}
+	}
+
+	if len(s.Skipped) > 0 {
+		msg.WriteString("\n\nSkipped code owners:\n")
+		for _, name := range slices.Sorted(maps.Keys(s.Skipped)) { //workflowcheck:ignore // Sorted for deterministic order.
+			fmt.Fprintf(&msg, "\n  •   %s - %s", realNameMention(ctx, name), s.Skipped[name]) //workflowcheck:ignore // Same as above.
+		}
+	}
+
+	return strings.TrimPrefix(msg.String(), "\n\n")
+}
+
+// realNameMention returns a Slack mention of a user based on their real name, if they are known to RevChat.
+func realNameMention(ctx workflow.Context, name string) string {
+	if id := data.SelectUserByRealName(ctx, name).SlackID; id != "" {
+		return fmt.Sprintf("<@%s>", id)
+	}
+	return name
+}
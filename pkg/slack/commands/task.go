@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket"
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+)
+
+// Task creates a new task in the current channel's Bitbucket PR, on behalf of the user.
+// In Bitbucket Cloud, this is supported only if createTasks is true.
+func Task(ctx workflow.Context, event SlashCommandEvent, text string, createTasks, pinChecklist bool) error {
+	url, err := prDetailsFromChannel(ctx, event)
+	if url == nil {
+		return err // May or may not be nil.
+	}
+	switch {
+	case url[1] != "bitbucket.org" && !datacenter.IsURL(url[0]):
+		PostEphemeralError(ctx, event, "this command is supported only in Bitbucket PRs.")
+		return nil
+	case url[1] == "bitbucket.org" && !createTasks:
+		PostEphemeralError(ctx, event, "creating Bitbucket tasks is not enabled in RevChat yet.")
+		return nil
+	}
+	if text == "" {
+		PostEphemeralError(ctx, event, fmt.Sprintf("missing task text - try `%s task <text>`", event.Command))
+		return nil
+	}
+
+	user, _, err := UserDetails(ctx, event, event.UserID)
+	if err != nil {
+		return err
+	}
+
+	if datacenter.IsURL(url[0]) {
+		err = bbactivities.CreateDataCenterTask(ctx, user.ThrippyLink, url[0], text)
+	} else {
+		_, err = bbactivities.CreatePullRequestTask(ctx, user.ThrippyLink, url[2], url[3], url[5], text)
+	}
+	if err != nil {
+		logger.From(ctx).Error("failed to create Bitbucket PR task", slog.Any("error", err), slog.String("pr_url", url[0]),
+			slog.String("slack_user_id", event.UserID), slog.String("thrippy_id", user.ThrippyLink))
+		PostEphemeralError(ctx, event, "failed to create a task in "+url[0])
+		return err
+	}
+
+	// Unlike other actions, Bitbucket doesn't send a webhook event for this,
+	// so we announce it and update the channel's checklist right away.
+	if bitbucket.SyncTasks(ctx, event.ChannelID, url[0], pinChecklist) {
+		bitbucket.UpdateChannelTasksBookmark(ctx, event.ChannelID, url[0])
+	}
+	return nil
+}
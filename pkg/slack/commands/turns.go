@@ -46,7 +46,7 @@ func MyTurn(ctx workflow.Context, opts client.Options, event SlashCommandEvent)
 
 	// If this is a no-op, inform the user.
 	if slices.Contains(emails, user.Email) {
-		msg := whoseTurnText(ctx, emails, user, " already")
+		msg := whoseTurnText(ctx, emails, user, " already", workflow.Now(ctx))
 		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 	}
 
@@ -69,7 +69,7 @@ func MyTurn(ctx workflow.Context, opts client.Options, event SlashCommandEvent)
 		return err
 	}
 
-	msg += whoseTurnText(ctx, emails, user, " now")
+	msg += whoseTurnText(ctx, emails, user, " now", workflow.Now(ctx))
 	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 }
 
@@ -84,7 +84,7 @@ func NotMyTurn(ctx workflow.Context, opts client.Options, event SlashCommandEven
 
 	// If this is a no-op, inform the user.
 	if !slices.Contains(currentTurn, user.Email) {
-		msg := ":joy: I didn't think it's your turn anyway!\n\n" + whoseTurnText(ctx, currentTurn, user, "")
+		msg := ":joy: I didn't think it's your turn anyway!\n\n" + whoseTurnText(ctx, currentTurn, user, "", workflow.Now(ctx))
 		return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 	}
 
@@ -99,7 +99,7 @@ func NotMyTurn(ctx workflow.Context, opts client.Options, event SlashCommandEven
 		return err
 	}
 
-	msg := "Thanks for letting me know!\n\n" + whoseTurnText(ctx, newTurn, user, " now")
+	msg := "Thanks for letting me know!\n\n" + whoseTurnText(ctx, newTurn, user, " now", workflow.Now(ctx))
 	return activities.PostEphemeralMessage(ctx, event.ChannelID, event.UserID, msg)
 }
 
@@ -153,7 +153,7 @@ func WhoseTurn(ctx workflow.Context, opts client.Options, event SlashCommandEven
 		return nil // Not a server error as far as we're concerned.
 	}
 
-	msg := whoseTurnText(ctx, emails, user, "")
+	msg := whoseTurnText(ctx, emails, user, "", workflow.Now(ctx))
 
 	if at, by := data.IsFrozen(ctx, opts, url); !at.IsZero() {
 		id := fmt.Sprintf("<@%s>", users.EmailToSlackID(ctx, by))
@@ -170,7 +170,8 @@ func WhoseTurn(ctx workflow.Context, opts client.Options, event SlashCommandEven
 
 // whoseTurnText builds a "whose turn is it" summary message, reused by multiple slash commands.
 // The emails slice must be deduped, but may contain invalid/bot email addresses (which are removed).
-func whoseTurnText(ctx workflow.Context, emails []string, user data.User, tweak string) string {
+// Other users who are away at the given time (see the "away" slash command) are marked as such.
+func whoseTurnText(ctx workflow.Context, emails []string, user data.User, tweak string, now time.Time) string {
 	// Ignore invalid/bot email addresses.
 	if i := slices.Index(emails, ""); i > -1 {
 		emails = slices.Delete(emails, i, i+1)
@@ -208,14 +209,18 @@ func whoseTurnText(ctx workflow.Context, emails []string, user data.User, tweak
 		if j > 0 {
 			msg.WriteString(", ")
 		}
-		switch user := data.SelectUserByEmail(ctx, email); {
-		case user.SlackID != "":
-			msg.WriteString("<@" + user.SlackID + ">")
-		case user.RealName != "":
-			msg.WriteString(user.RealName)
+		other := data.SelectUserByEmail(ctx, email)
+		switch {
+		case other.SlackID != "":
+			msg.WriteString("<@" + other.SlackID + ">")
+		case other.RealName != "":
+			msg.WriteString(other.RealName)
 		default:
 			msg.WriteString(email)
 		}
+		if _, away := other.AwayAt(now); away {
+			msg.WriteString(" (:palm_tree: away)")
+		}
 	}
 
 	if withOthers {
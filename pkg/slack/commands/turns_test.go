@@ -2,6 +2,7 @@ package commands
 
 import (
 	"testing"
+	"time"
 
 	"github.com/tzrikka/revchat/pkg/data"
 )
@@ -10,6 +11,14 @@ func TestWhoseTurnText(t *testing.T) {
 	d := t.TempDir()
 	t.Setenv("XDG_DATA_HOME", d)
 
+	now := time.Now().UTC()
+	if err := data.UpsertUser(nil, "away@example.com", "", "", "", "U123", ""); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if err := data.SetAwayPeriods(nil, "U123", []data.AwayPeriod{{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}}); err != nil {
+		t.Fatalf("SetAwayPeriods() error = %v", err)
+	}
+
 	tests := []struct {
 		name   string
 		emails []string
@@ -54,11 +63,17 @@ func TestWhoseTurnText(t *testing.T) {
 			user:   data.User{Email: "author@example.com"},
 			want:   "I think it's the turn of reviewer1@example.com, reviewer2@example.com to review this PR.",
 		},
+		{
+			name:   "away_reviewer",
+			emails: []string{"away@example.com", "reviewer@example.com"},
+			user:   data.User{Email: "author@example.com"},
+			want:   "I think it's the turn of <@U123> (:palm_tree: away), reviewer@example.com to review this PR.",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := whoseTurnText(nil, tt.emails, tt.user, tt.tweak); got != tt.want {
+			if got := whoseTurnText(nil, tt.emails, tt.user, tt.tweak, now); got != tt.want {
 				t.Errorf("whoseTurnText() = %q, want %q", got, tt.want)
 			}
 		})
@@ -13,6 +13,8 @@ import (
 
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/config"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/files"
 	"github.com/tzrikka/revchat/pkg/users"
@@ -76,6 +78,7 @@ func PRDetails(
 	paths := data.LoadDiffstatPaths(ctx, url)
 	if len(paths) > 0 {
 		owner, repo, branch, commit := PRIdentifiers(ctx, url, pr)
+		src := files.NewSourceFetcher(url)
 
 		fullNames := make([]string, 0, len(userIDs))
 		for _, userID := range userIDs {
@@ -84,14 +87,14 @@ func PRDetails(
 			}
 		}
 
-		if owned := files.CountOwnedFiles(ctx, owner, repo, branch, commit, fullNames, paths); owned > 0 {
+		if owned := files.CountOwnedFiles(ctx, src, owner, repo, branch, commit, fullNames, paths); owned > 0 {
 			fmt.Fprintf(summary, ", code owners: *%d* file", owned) //workflowcheck:ignore // Deterministic output, not a file.
 			if owned > 1 {
 				summary.WriteString("s")
 			}
 		}
 
-		if highRisk := files.CountHighRiskFiles(ctx, owner, repo, branch, commit, paths); highRisk > 0 {
+		if highRisk := files.CountHighRiskFiles(ctx, src, owner, repo, branch, commit, paths); highRisk > 0 {
 			fmt.Fprintf(summary, ", high risk: *%d* file", highRisk) //workflowcheck:ignore // Deterministic output, not a file.
 			if highRisk > 1 {
 				summary.WriteString("s")
@@ -102,6 +105,10 @@ func PRDetails(
 	if s := states(ctx, url); s != "" {
 		summary.WriteString(s)
 	}
+	summary.WriteString(mergeStatus(pr))
+	if ls := PRLabels(pr); len(ls) > 0 {
+		fmt.Fprintf(summary, "\n>Labels: `%s`", strings.Join(ls, "`, `")) //workflowcheck:ignore // Deterministic output, not a file.
+	}
 
 	// Review details.
 	tasks := prTasks(ctx, showTasks, thrippyID, url, pr)
@@ -186,7 +193,7 @@ func PRIdentifiers(ctx workflow.Context, url string, pr map[string]any) (owner,
 }
 
 func isBitbucketPR(url string) bool {
-	return strings.HasPrefix(url, "https://bitbucket.org/")
+	return strings.HasPrefix(url, "https://bitbucket.org/") || datacenter.IsURL(url)
 }
 
 func author(ctx workflow.Context, url string, pr map[string]any) string {
@@ -318,29 +325,103 @@ const (
 )
 
 func states(ctx workflow.Context, url string) string {
-	if isBitbucketPR(url) {
-		prStatus := data.ReadBitbucketBuilds(ctx, url)
-		keys := slices.Sorted(maps.Keys(prStatus.Builds)) //workflowcheck:ignore // Sorted for deterministic order.
-		var summary []string
-		for _, k := range keys {
-			switch s := prStatus.Builds[k].State; s {
-			case "SUCCESSFUL":
-				summary = append(summary, buildSuccessful)
-			case "INPROGRESS":
-				summary = append(summary, buildInProgress)
-			default: // "FAILED", "STOPPED".
-				summary = append(summary, buildFailed)
-			}
+	prStatus := data.ReadBuilds(ctx, url)
+	keys := slices.Sorted(maps.Keys(prStatus.Builds)) //workflowcheck:ignore // Sorted for deterministic order.
+	var summary []string
+	for _, k := range keys {
+		switch s := prStatus.Builds[k].State; s {
+		case "SUCCESSFUL":
+			summary = append(summary, buildSuccessful)
+		case "INPROGRESS":
+			summary = append(summary, buildInProgress)
+		default: // "FAILED", "STOPPED".
+			summary = append(summary, buildFailed)
 		}
+	}
 
-		if len(summary) > 0 {
-			return fmt.Sprintf(", builds: :%s:", strings.Join(summary, ": :"))
-		}
+	if len(summary) > 0 {
+		return fmt.Sprintf(", builds: :%s:", strings.Join(summary, ": :"))
+	}
+	return ""
+}
+
+// mergeStatus returns the merge-queue or auto-merge status of a GitHub PR. The merge-queue state
+// is stored in GitHub PR snapshots by RevChat. Bitbucket PR snapshots don't contain these details.
+func mergeStatus(pr map[string]any) string {
+	if state, ok := pr["merge_queue_state"].(string); ok && state != "" {
+		return fmt.Sprintf(", merge queue: *%s*", state)
+	}
+
+	autoMerge, ok := pr["auto_merge"].(map[string]any)
+	if !ok {
 		return ""
 	}
+	if method, ok := autoMerge["merge_method"].(string); ok && method != "" {
+		return fmt.Sprintf(", auto-merge: *%s*", method)
+	}
+	return ", auto-merge: *enabled*"
+}
 
-	// GitHub.
-	return ""
+// PRLabels returns the label names in a GitHub PR snapshot. Bitbucket PRs don't have labels.
+func PRLabels(pr map[string]any) []string {
+	labels, ok := pr["labels"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for _, l := range labels {
+		label, ok := l.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, ok := label["name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// UrgentPRs returns the subset of the given PR URLs with labels which are mapped to the "urgent"
+// rule (see the "github-label-rules" flag). Each PR snapshot is loaded at most once, even if it's
+// listed multiple times, and none are loaded if there is no such rule. Only GitHub PRs have labels.
+func UrgentPRs(ctx workflow.Context, urls []string, labelRules map[string]string) map[string]bool {
+	urgent := map[string]bool{}
+	if !slices.Contains(slices.Collect(maps.Values(labelRules)), config.LabelRuleUrgent) { //workflowcheck:ignore
+		return urgent
+	}
+
+	checked := map[string]bool{}
+	for _, url := range urls {
+		if checked[url] || isBitbucketPR(url) {
+			continue
+		}
+		checked[url] = true
+
+		pr, err := data.LoadPRSnapshot(ctx, url)
+		if err == nil && config.HasLabelRule(labelRules, PRLabels(pr), config.LabelRuleUrgent) {
+			urgent[url] = true
+		}
+	}
+
+	return urgent
+}
+
+// UrgentPRsFirst reorders the given PR URLs so that urgent PRs (see [UrgentPRs]) come before
+// all the others. The relative order within each of these 2 subsets remains unchanged.
+func UrgentPRsFirst(urls []string, urgent map[string]bool) []string {
+	first := make([]string, 0, len(urls))
+	var others []string
+	for _, url := range urls {
+		if urgent[url] {
+			first = append(first, url)
+		} else {
+			others = append(others, url)
+		}
+	}
+
+	return append(first, others...)
 }
 
 func times(now time.Time, url string, pr map[string]any) (created, updated string) {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/tzrikka/revchat/pkg/config"
+	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/xdg"
 )
 
@@ -488,6 +489,17 @@ func TestStates(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// Test data for GitHub happy path.
+	path = filepath.Join(d, config.DirName, "github.com", "owner", "repo", "pull", "2_builds.json")
+	err = os.MkdirAll(filepath.Dir(path), xdg.NewDirectoryPermissions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	// Test cases.
 	tests := []struct {
 		name string
@@ -503,6 +515,15 @@ func TestStates(t *testing.T) {
 			url:  "https://bitbucket.org/workspace/repo/pull-requests/67890",
 			want: ", builds: :large_green_circle: :red_circle: :large_yellow_circle:",
 		},
+		{
+			name: "github_no_builds",
+			url:  "https://github.com/owner/repo/pull/1",
+		},
+		{
+			name: "github_builds",
+			url:  "https://github.com/owner/repo/pull/2",
+			want: ", builds: :large_green_circle: :red_circle: :large_yellow_circle:",
+		},
 	}
 
 	for _, tt := range tests {
@@ -515,6 +536,115 @@ func TestStates(t *testing.T) {
 	}
 }
 
+func TestMergeStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   map[string]any
+		want string
+	}{
+		{
+			name: "bitbucket_pr",
+			pr:   map[string]any{"title": "Add new feature"},
+		},
+		{
+			name: "github_manual_merge",
+			pr:   map[string]any{"title": "Add new feature", "auto_merge": nil},
+		},
+		{
+			name: "github_auto_merge",
+			pr:   map[string]any{"auto_merge": map[string]any{"merge_method": "squash"}},
+			want: ", auto-merge: *squash*",
+		},
+		{
+			name: "github_auto_merge_without_method",
+			pr:   map[string]any{"auto_merge": map[string]any{}},
+			want: ", auto-merge: *enabled*",
+		},
+		{
+			name: "github_merge_queue",
+			pr: map[string]any{
+				"auto_merge":        map[string]any{"merge_method": "merge"},
+				"merge_queue_state": "queued",
+			},
+			want: ", merge queue: *queued*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeStatus(tt.pr); got != tt.want {
+				t.Errorf("mergeStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		pr   map[string]any
+		want []string
+	}{
+		{
+			name: "bitbucket_pr",
+			pr:   map[string]any{"title": "Add new feature"},
+		},
+		{
+			name: "github_no_labels",
+			pr:   map[string]any{"labels": []any{}},
+		},
+		{
+			name: "github_labels",
+			pr: map[string]any{"labels": []any{
+				map[string]any{"name": "bug", "color": "d73a4a"},
+				map[string]any{"color": "ffffff"},
+				map[string]any{"name": "urgent"},
+			}},
+			want: []string{"bug", "urgent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PRLabels(tt.pr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PRLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUrgentPRsFirst(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	urgent := map[string]any{"labels": []any{map[string]any{"name": "Urgent"}}}
+	other := map[string]any{"labels": []any{map[string]any{"name": "bug"}}}
+	data.StorePRSnapshot(nil, "https://github.com/owner/repo/pull/2", urgent)
+	data.StorePRSnapshot(nil, "https://github.com/owner/repo/pull/3", other)
+	data.StorePRSnapshot(nil, "https://github.com/owner/repo/pull/4", urgent)
+
+	urls := []string{
+		"https://bitbucket.org/workspace/repo/pull-requests/1",
+		"https://github.com/owner/repo/pull/2",
+		"https://github.com/owner/repo/pull/3",
+		"https://github.com/owner/repo/pull/4",
+	}
+	want := []string{
+		"https://github.com/owner/repo/pull/2",
+		"https://github.com/owner/repo/pull/4",
+		"https://bitbucket.org/workspace/repo/pull-requests/1",
+		"https://github.com/owner/repo/pull/3",
+	}
+
+	got := UrgentPRsFirst(urls, UrgentPRs(nil, urls, config.LabelRules(config.DefaultLabelRules)))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UrgentPRsFirst() = %v, want %v", got, want)
+	}
+
+	if got := UrgentPRs(nil, urls, map[string]string{"urgent": config.LabelRuleNoMerge}); len(got) > 0 {
+		t.Errorf("UrgentPRs() without urgent rule = %v, want none", got)
+	}
+}
+
 func TestTimes(t *testing.T) {
 	tests := []struct {
 		name        string
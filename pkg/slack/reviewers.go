@@ -0,0 +1,271 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	bbactivities "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/files"
+	ghactivities "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/users"
+)
+
+// ReviewerSuggestions is the result of [SuggestReviewers]. Users are
+// identified by their real names, just like in the [files] package.
+type ReviewerSuggestions struct {
+	Owners    []string          // A minimal set of available code owners who cover all the PR's files.
+	Reviewers []string          // The subset of [ReviewerSuggestions.Owners] who are already reviewers.
+	Skipped   map[string]string // Unavailable code owners, and the reason why.
+	Uncovered []string          // File paths whose code owners are all unavailable.
+}
+
+var gitHubPRURLPattern = regexp.MustCompile(`^https://[^/]+/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// SuggestReviewers finds the smallest set of code owners (according to the "CODEOWNERS" file in
+// the PR's destination branch) who can review all the files in a PR, preferring existing reviewers.
+// It skips the PR's author, as well as code owners who are away (see [data.User.AwayAt]) or
+// overloaded: the number of PRs waiting for them is at least maxLoad (if it's positive).
+func SuggestReviewers(ctx workflow.Context, opts client.Options, prURL string, maxLoad int) (*ReviewerSuggestions, error) {
+	pr, err := data.LoadPRSnapshot(ctx, prURL)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, errors.New("PR snapshot not found: " + prURL)
+	}
+
+	suggestions := &ReviewerSuggestions{Skipped: map[string]string{}}
+	paths := data.LoadDiffstatPaths(ctx, prURL)
+	if len(paths) == 0 {
+		return suggestions, nil
+	}
+
+	workspace, repo, branch, commit := PRIdentifiers(ctx, prURL, pr)
+	owners, _ := files.OwnersPerPath(ctx, files.NewSourceFetcher(prURL), workspace, repo, branch, commit, paths, true)
+	if owners == nil {
+		return suggestions, nil // No "CODEOWNERS" file.
+	}
+
+	authorName, reviewerNames := participantNames(ctx, prURL, pr)
+	excluded := map[string]bool{authorName: true}
+
+	var candidates []string
+	for _, p := range paths {
+		candidates = append(candidates, owners[p]...)
+	}
+	slices.Sort(candidates)
+	candidates = slices.DeleteFunc(slices.Compact(candidates), func(name string) bool { return name == authorName })
+
+	for name, reason := range unavailableUsers(ctx, opts, candidates, maxLoad) { //workflowcheck:ignore
+		suggestions.Skipped[name] = reason
+		excluded[name] = true
+	}
+
+	suggestions.Owners, suggestions.Uncovered = files.MinimalOwnersCover(paths, owners, reviewerNames, excluded)
+	for _, name := range suggestions.Owners {
+		if slices.Contains(reviewerNames, name) {
+			suggestions.Reviewers = append(suggestions.Reviewers, name)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// participantNames returns the real names of a PR's author and current reviewers, in the same
+// naming scheme as "CODEOWNERS" files: known users are identified by their real names, and
+// unknown users by their Bitbucket display names or GitHub logins (in GitHub PRs).
+func participantNames(ctx workflow.Context, url string, pr map[string]any) (author string, reviewers []string) {
+	if isBitbucketPR(url) {
+		author = bitbucketUserName(ctx, pr["author"])
+		if list, ok := pr["reviewers"].([]any); ok {
+			for _, r := range list {
+				if name := bitbucketUserName(ctx, r); name != "" {
+					reviewers = append(reviewers, name)
+				}
+			}
+		}
+		return author, reviewers
+	}
+
+	// GitHub.
+	author = gitHubUserName(ctx, pr["user"])
+	if list, ok := pr["requested_reviewers"].([]any); ok {
+		for _, r := range list {
+			if name := gitHubUserName(ctx, r); name != "" {
+				reviewers = append(reviewers, name)
+			}
+		}
+	}
+	return author, reviewers
+}
+
+func bitbucketUserName(ctx workflow.Context, account any) string {
+	m, ok := account.(map[string]any)
+	if !ok {
+		return ""
+	}
+
+	if id, ok := m["account_id"].(string); ok {
+		if name := data.SelectUserByBitbucketID(ctx, id).RealName; name != "" {
+			return name
+		}
+	}
+
+	name, _ := m["display_name"].(string)
+	return name
+}
+
+func gitHubUserName(ctx workflow.Context, user any) string {
+	m, ok := user.(map[string]any)
+	if !ok {
+		return ""
+	}
+
+	login, _ := m["login"].(string)
+	if login == "" {
+		return ""
+	}
+
+	if name := data.SelectUserByGitHubID(ctx, login).RealName; name != "" {
+		return name
+	}
+	return login
+}
+
+// unavailableUsers checks which of the given users (identified by their real names) are away or overloaded.
+// Users without a known Slack ID are considered available, because there's no way to check them.
+func unavailableUsers(ctx workflow.Context, opts client.Options, realNames []string, maxLoad int) map[string]string {
+	known := map[string]data.User{}
+	var filter []string
+	for _, name := range realNames {
+		if user := data.SelectUserByRealName(ctx, name); user.SlackID != "" {
+			known[name] = user
+			filter = append(filter, user.SlackID)
+		}
+	}
+
+	var userPRs map[string][]string
+	if maxLoad > 0 && len(filter) > 0 {
+		userPRs, _ = data.ListPRsPerSlackUser(ctx, opts, true, false, true, filter)
+	}
+
+	unavailable := map[string]string{}
+	for _, name := range realNames {
+		user, found := known[name]
+		if !found {
+			continue
+		}
+
+		if _, away := user.AwayAt(workflow.Now(ctx)); away {
+			unavailable[name] = "away"
+			continue
+		}
+		if n := len(userPRs[user.SlackID]); maxLoad > 0 && n >= maxLoad {
+			unavailable[name] = fmt.Sprintf("already has %d PRs waiting for them", n)
+		}
+	}
+
+	return unavailable
+}
+
+// UpdateReviewers adds or removes reviewers (identified by their Slack IDs) to/from a PR, on behalf of the user
+// who is identified by the given Thrippy link ID. It returns the Slack IDs of the users who were updated, and of
+// those who were skipped because their Bitbucket/GitHub accounts are unknown, or because they are the PR's author.
+// The resulting PR update event announces the change, and updates the PR's Slack channel members, as usual.
+func UpdateReviewers(ctx workflow.Context, thrippyID, prURL string, slackIDs []string, add bool) (done, skipped []string, err error) {
+	pr, err := data.LoadPRSnapshot(ctx, prURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ids []string
+	for _, slackID := range slackIDs {
+		id := reviewerID(ctx, prURL, slackID)
+		if id == "" || (add && id == authorID(prURL, pr)) {
+			skipped = append(skipped, slackID)
+			continue
+		}
+		ids = append(ids, id)
+		done = append(done, slackID)
+	}
+	if len(ids) == 0 {
+		return nil, skipped, nil
+	}
+
+	if datacenter.IsURL(prURL) {
+		if add {
+			err = bbactivities.AddDataCenterReviewers(ctx, thrippyID, prURL, ids)
+		} else {
+			err = bbactivities.RemoveDataCenterReviewers(ctx, thrippyID, prURL, ids)
+		}
+		return done, skipped, err
+	}
+
+	if isBitbucketPR(prURL) {
+		if add {
+			err = bbactivities.AddPullRequestReviewers(ctx, thrippyID, prURL, ids)
+		} else {
+			err = bbactivities.RemovePullRequestReviewers(ctx, thrippyID, prURL, ids)
+		}
+		return done, skipped, err
+	}
+
+	// GitHub.
+	url := gitHubPRURLPattern.FindStringSubmatch(prURL)
+	if url == nil {
+		return nil, nil, errors.New("invalid PR URL: " + prURL)
+	}
+	prID, err := strconv.Atoi(url[3])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse PR number %q: %w", url[3], err)
+	}
+
+	if add {
+		err = ghactivities.RequestReviewers(ctx, thrippyID, url[1], url[2], prID, ids)
+	} else {
+		err = ghactivities.RemoveRequestedReviewers(ctx, thrippyID, url[1], url[2], prID, ids)
+	}
+	return done, skipped, err
+}
+
+// reviewerID converts a Slack user ID into a Bitbucket account ID or a GitHub login, depending on the PR's URL.
+func reviewerID(ctx workflow.Context, prURL, slackID string) string {
+	user, _, err := data.SelectUserBySlackID(ctx, slackID)
+	if err != nil {
+		return ""
+	}
+
+	if !isBitbucketPR(prURL) {
+		return user.GitHubID
+	}
+	if datacenter.IsURL(prURL) {
+		return datacenter.AccountID(users.SlackIDToEmail(ctx, slackID))
+	}
+	if user.BitbucketID != "" {
+		return user.BitbucketID
+	}
+	return users.EmailToBitbucketID(ctx, users.SlackIDToEmail(ctx, slackID))
+}
+
+// authorID returns the Bitbucket account ID or GitHub login of a PR's author.
+func authorID(prURL string, pr map[string]any) string {
+	field, key := "user", "login" // GitHub.
+	if isBitbucketPR(prURL) {
+		field, key = "author", "account_id"
+	}
+
+	m, ok := pr[field].(map[string]any)
+	if !ok {
+		return ""
+	}
+	id, _ := m[key].(string)
+	return id
+}
@@ -0,0 +1,136 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/data"
+)
+
+func TestUpdateReviewers(t *testing.T) {
+	tests := []struct {
+		name        string
+		prURL       string
+		pr          map[string]any
+		slackIDs    []string
+		add         bool
+		wantDone    []string
+		wantSkipped []string
+		wantCalls   []string
+	}{
+		{
+			name:        "github_add",
+			prURL:       "https://github.com/owner/repo/pull/1",
+			pr:          map[string]any{"user": map[string]any{"login": "alice"}},
+			slackIDs:    []string{"U1", "U2", "U4"},
+			add:         true,
+			wantDone:    []string{"U2"},
+			wantSkipped: []string{"U1", "U4"}, // Author, and unknown user.
+			wantCalls:   []string{"github.pulls.requestReviewers [bob]"},
+		},
+		{
+			name:      "github_remove",
+			prURL:     "https://github.com/owner/repo/pull/1",
+			pr:        map[string]any{"user": map[string]any{"login": "alice"}},
+			slackIDs:  []string{"U2", "U3"},
+			wantDone:  []string{"U2", "U3"},
+			wantCalls: []string{"github.pulls.removeRequestedReviewers [bob carol]"},
+		},
+		{
+			name:        "bitbucket_add",
+			prURL:       "https://bitbucket.org/workspace/repo/pull-requests/1",
+			pr:          map[string]any{"author": map[string]any{"account_id": "bb-alice"}},
+			slackIDs:    []string{"U1", "U2"},
+			add:         true,
+			wantDone:    []string{"U2"},
+			wantSkipped: []string{"U1"}, // Author.
+			wantCalls:   []string{"bitbucket.pullrequests.get", "bitbucket.pullrequests.update [bb-carol bb-bob]"},
+		},
+		{
+			name:      "bitbucket_remove",
+			prURL:     "https://bitbucket.org/workspace/repo/pull-requests/1",
+			pr:        map[string]any{"author": map[string]any{"account_id": "bb-alice"}},
+			slackIDs:  []string{"U3"},
+			wantDone:  []string{"U3"},
+			wantCalls: []string{"bitbucket.pullrequests.get", "bitbucket.pullrequests.update []"},
+		},
+		{
+			name:        "only_skipped",
+			prURL:       "https://github.com/owner/repo/pull/1",
+			pr:          map[string]any{"user": map[string]any{"login": "alice"}},
+			slackIDs:    []string{"U1"},
+			add:         true,
+			wantSkipped: []string{"U1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			for _, u := range [][3]string{{"U1", "alice", "bb-alice"}, {"U2", "bob", "bb-bob"}, {"U3", "carol", "bb-carol"}} {
+				if err := data.UpsertUser(nil, u[1]+"@example.com", u[1], u[2], u[1], u[0], ""); err != nil {
+					t.Fatalf("UpsertUser() error = %v", err)
+				}
+			}
+			data.StorePRSnapshot(nil, tt.prURL, tt.pr)
+
+			s := testsuite.WorkflowTestSuite{}
+			env := s.NewTestWorkflowEnvironment()
+			env.RegisterWorkflowWithOptions(func(ctx workflow.Context, add bool) ([][]string, error) {
+				done, skipped, err := UpdateReviewers(ctx, "link", tt.prURL, tt.slackIDs, add)
+				return [][]string{done, skipped}, err
+			}, workflow.RegisterOptions{Name: "test"})
+
+			calls := []string{}
+			for _, name := range []string{"github.pulls.requestReviewers", "github.pulls.removeRequestedReviewers"} {
+				env.RegisterActivityWithOptions(func(_ context.Context, req map[string]any) (map[string]any, error) {
+					calls = append(calls, fmt.Sprintf("%s %v", name, req["reviewers"]))
+					return map[string]any{}, nil
+				}, activity.RegisterOptions{Name: name})
+			}
+			env.RegisterActivityWithOptions(func(_ context.Context, _ map[string]any) (map[string]any, error) {
+				calls = append(calls, "bitbucket.pullrequests.get")
+				return map[string]any{"reviewers": []any{map[string]any{"account_id": "bb-carol"}}}, nil
+			}, activity.RegisterOptions{Name: "bitbucket.pullrequests.get"})
+			env.RegisterActivityWithOptions(func(_ context.Context, req map[string]any) (map[string]any, error) {
+				var ids []string
+				pr, _ := req["pullrequest"].(map[string]any)
+				reviewers, _ := pr["reviewers"].([]any)
+				for _, r := range reviewers {
+					ids = append(ids, fmt.Sprint(r.(map[string]any)["account_id"]))
+				}
+				calls = append(calls, fmt.Sprintf("bitbucket.pullrequests.update %v", ids))
+				return map[string]any{}, nil
+			}, activity.RegisterOptions{Name: "bitbucket.pullrequests.update"})
+
+			env.ExecuteWorkflow("test", tt.add)
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("UpdateReviewers() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("UpdateReviewers() error = %v", err)
+			}
+
+			var got [][]string
+			if err := env.GetWorkflowResult(&got); err != nil {
+				t.Fatalf("GetWorkflowResult() error = %v", err)
+			}
+			if !reflect.DeepEqual(got[0], tt.wantDone) {
+				t.Errorf("UpdateReviewers() done = %q, want %q", got[0], tt.wantDone)
+			}
+			if !reflect.DeepEqual(got[1], tt.wantSkipped) {
+				t.Errorf("UpdateReviewers() skipped = %q, want %q", got[1], tt.wantSkipped)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("UpdateReviewers() calls = %q, want %q", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+)
+
+// BlockActionsWorkflow handles button presses in scheduled reminders, and then updates the
+// reminder message in place: https://docs.slack.dev/reference/interaction-payloads/block_actions-payload/
+func (c *Config) BlockActionsWorkflow(ctx workflow.Context, event BlockActionsEvent) error {
+	if event.Message == nil || event.Container.ChannelID == "" || event.Container.MessageTS == "" {
+		return nil
+	}
+
+	blocks := event.Message.Blocks
+	updated := false
+
+	var aggregatedErr error
+	for _, action := range event.Actions {
+		text, err := c.reminderAction(ctx, event.User.ID, action)
+		if err != nil {
+			msg := ":warning: Error: failed to handle your action, please try again later."
+			_ = activities.PostEphemeralMessage(ctx, event.Container.ChannelID, event.User.ID, msg)
+			aggregatedErr = errors.Join(aggregatedErr, err)
+			continue
+		}
+		if text != "" {
+			blocks = replaceActionsBlock(blocks, action.BlockID, text)
+			updated = true
+		}
+	}
+
+	if !updated {
+		return aggregatedErr
+	}
+
+	err := activities.UpdateMessageBlocks(ctx, event.Container.ChannelID, event.Container.MessageTS, event.Message.Text, blocks)
+	return errors.Join(aggregatedErr, err)
+}
+
+// reminderAction performs a single reminder button's action on behalf of the user who pressed it,
+// and returns a short description of the result, to replace the buttons. The returned text is empty
+// if there's nothing to update, e.g. when the user presses the "Open channel" URL button.
+func (c *Config) reminderAction(ctx workflow.Context, userID string, action BlockAction) (string, error) {
+	switch action.ActionID {
+	case reminderActionApprove, reminderActionNotMyTurn, reminderActionSnooze:
+		// Handled below.
+	case reminderActionOpenChannel:
+		return "", nil
+	default:
+		logger.From(ctx).Warn("unrecognized Slack block action", slog.String("action_id", action.ActionID),
+			slog.String("block_id", action.BlockID), slog.String("user_id", userID))
+		return "", nil
+	}
+
+	url := commands.PullRequestURLPattern.FindStringSubmatch(action.Value)
+	if url == nil {
+		return "", fmt.Errorf("invalid PR URL in Slack block action: %q", action.Value)
+	}
+
+	user, _, err := data.SelectUserBySlackID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	switch action.ActionID {
+	case reminderActionApprove:
+		if err := commands.ApprovePR(ctx, user, url); err != nil {
+			return "", err
+		}
+		return ":white_check_mark: You approved this PR.", nil
+
+	case reminderActionNotMyTurn:
+		emails, err := data.LoadCurrentTurnEmails(ctx, c.TemporalOpts, url[0])
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(emails, user.Email) {
+			return ":joy: It wasn't your turn anyway.", nil
+		}
+		if err := data.SwitchTurn(ctx, c.TemporalOpts, url[0], user.Email, true); err != nil {
+			return "", err
+		}
+		return ":arrow_right_hook: Thanks for letting me know, it's no longer your turn.", nil
+
+	default: // reminderActionSnooze.
+		until := workflow.Now(ctx).UTC().Add(24 * time.Hour)
+		if err := data.SnoozePR(ctx, c.TemporalOpts, url[0], user.Email, until); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(":zzz: Snoozed until %s.", commands.SnoozeTimeText(until)), nil
+	}
+}
+
+// replaceActionsBlock replaces the actions block with the given ID (i.e. a PR's reminder buttons)
+// with a context block that contains the given text. Other blocks are returned as they are.
+func replaceActionsBlock(blocks []map[string]any, blockID, text string) []map[string]any {
+	updated := make([]map[string]any, 0, len(blocks))
+	for _, b := range blocks {
+		if b["type"] == "actions" && b["block_id"] == blockID {
+			b = map[string]any{
+				"type":     "context",
+				"block_id": blockID,
+				"elements": []map[string]any{{"type": "mrkdwn", "text": text}},
+			}
+		}
+		updated = append(updated, b)
+	}
+	return updated
+}
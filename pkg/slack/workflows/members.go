@@ -1,28 +1,42 @@
 package workflows
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/config"
 	"github.com/tzrikka/revchat/pkg/data"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/slack"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+	"github.com/tzrikka/revchat/pkg/users"
 )
 
-// MemberJoinedWorkflow ensures that users who are added to RevChat channels by others
-// are opted-in: https://docs.slack.dev/reference/events/member_joined_channel/
+// MemberJoinedWorkflow ensures that users who are added to RevChat channels by others are opted-in,
+// and (in some repositories) adds opted-in users who join PR channels as reviewers of these PRs,
+// based on the "reviewers-add-on-join-repos" flag: https://docs.slack.dev/reference/events/member_joined_channel/
 func (c *Config) MemberJoinedWorkflow(ctx workflow.Context, event memberEventWrapper) error {
 	e := event.InnerEvent
 	if selfTriggeredMemberEvent(ctx, event.Authorizations, e) || !c.isRevChatChannel(ctx, e.Channel) {
 		return nil
 	}
 
-	if _, optedIn, err := data.SelectUserBySlackID(ctx, e.User); err != nil || optedIn {
-		// The error is nil (and there's no alert) if the user is opted-in.
+	user, optedIn, err := data.SelectUserBySlackID(ctx, e.User)
+	if err != nil {
 		return activities.AlertError(ctx, c.AlertsChannel, "", err, "User ID", e.User)
 	}
+	if optedIn {
+		return c.addJoiningReviewer(ctx, e, user)
+	}
 
 	// If the user isn't opted-in, send them a DM explaining how to opt-in.
 	mention := fmt.Sprintf("<@%s>", e.User)
@@ -39,14 +53,141 @@ func (c *Config) MemberJoinedWorkflow(ctx workflow.Context, event memberEventWra
 	return activities.PostMessage(ctx, e.User, fmt.Sprintf(msg, mention)+"this Slack command:\n\n```/revchat opt-in```")
 }
 
-// MemberLeftWorkflow is (or rather will be) based on:
-// https://docs.slack.dev/reference/events/member_left_channel/
+// addJoiningReviewer adds an opted-in user who joined a PR channel as a reviewer of the PR, on their behalf,
+// if the PR's repository is listed in the "reviewers-add-on-join-repos" flag, and they aren't already
+// the PR's author or one of its reviewers.
+func (c *Config) addJoiningReviewer(ctx workflow.Context, e MemberEvent, user data.User) error {
+	if len(c.ReviewersOnJoinRepos) == 0 {
+		return nil
+	}
+
+	prURL, err := data.SwitchURLAndID(ctx, e.Channel)
+	if err != nil || prURL == "" {
+		return err
+	}
+
+	url := commands.PullRequestURLPattern.FindStringSubmatch(prURL)
+	if len(url) < 4 || !c.ReviewersOnJoinRepos[strings.ToLower(url[2]+"/"+url[3])] {
+		return nil
+	}
+	if url[1] != "bitbucket.org" && !datacenter.IsURL(prURL) && !c.GitHubReviewerRequests {
+		logger.From(ctx).Warn("not adding joining user as GitHub PR reviewer, because the reviewer requests flag isn't set",
+			slog.String("user_id", e.User), slog.String("pr_url", prURL))
+		return nil
+	}
+
+	isAuthor, isReviewer := data.LoadParticipantRoles(ctx, c.TemporalOpts, prURL, user.Email)
+	if isAuthor || isReviewer {
+		return nil
+	}
+
+	done, _, err := slack.UpdateReviewers(ctx, user.ThrippyLink, prURL, []string{e.User}, true)
+	if err != nil {
+		logger.From(ctx).Error("failed to add joining user as PR reviewer", slog.Any("error", err),
+			slog.String("user_id", e.User), slog.String("pr_url", prURL))
+		return err
+	}
+	if len(done) == 0 {
+		return nil
+	}
+
+	msg := ":eyes: You joined the channel of this PR, so RevChat added you as a reviewer, on your behalf."
+	return activities.PostEphemeralMessage(ctx, e.Channel, e.User, msg)
+}
+
+// MemberLeftWorkflow applies the "reviewers-left-channel" policy when a PR reviewer leaves its Slack channel,
+// and warns PR authors who leave their own PR channels: https://docs.slack.dev/reference/events/member_left_channel/
 func (c *Config) MemberLeftWorkflow(ctx workflow.Context, event memberEventWrapper) error {
 	e := event.InnerEvent
-	if selfTriggeredMemberEvent(ctx, event.Authorizations, e) || !c.isRevChatChannel(ctx, e.Channel) {
+	if selfTriggeredMemberEvent(ctx, event.Authorizations, e) {
 		return nil
 	}
 
-	logger.From(ctx).Debug("Slack member left channel event - not implemented yet")
-	return nil
+	// Instead of calling ![isRevChatChannel], because we also need the PR's URL below.
+	prURL, err := c.switchURLAndID(ctx, e.Channel)
+	if err != nil || prURL == "" {
+		return err
+	}
+
+	email := users.SlackIDToEmail(ctx, e.User)
+	isAuthor, isReviewer := data.LoadParticipantRoles(ctx, c.TemporalOpts, prURL, email)
+	switch {
+	case isAuthor:
+		// A DM, because ephemeral messages in the channel aren't visible to users who left it.
+		msg := fmt.Sprintf(":warning: You left <#%s>, the Slack channel of your own PR: %s\n\n", e.Channel, prURL)
+		msg += "RevChat keeps mirroring the PR's events and discussions there, so please rejoin it to stay in the loop."
+		return activities.PostMessage(ctx, e.User, msg)
+	case isReviewer:
+		return c.reviewerLeftChannel(ctx, e, prURL, email)
+	default:
+		return nil
+	}
+}
+
+// reviewerLeftChannel posts a note in the PR's channel, and depending on the "reviewers-left-channel"
+// policy also stops tracking the reviewer's turn, and removes them from the PR on their behalf.
+func (c *Config) reviewerLeftChannel(ctx workflow.Context, e MemberEvent, prURL, email string) error {
+	name := users.SlackIDToRealName(ctx, e.User)
+	logger.From(ctx).Info("reviewer left PR's Slack channel", slog.String("user_id", e.User),
+		slog.String("pr_url", prURL), slog.String("policy", c.ReviewersLeftChannel))
+
+	switch c.ReviewersLeftChannel {
+	case config.ReviewerLeftUnassign:
+		err := c.removeReviewer(ctx, e.User, prURL)
+		if err == nil {
+			if err := data.RemoveReviewerFromTurns(ctx, c.TemporalOpts, prURL, email, false); err != nil {
+				return err
+			}
+			return activities.PostMessage(ctx, e.Channel, fmt.Sprintf(
+				":door: %s left this channel, so they were removed from this PR's reviewers.", name))
+		}
+
+		// Otherwise, fall back to the "untrack" policy.
+		logger.From(ctx).Warn("failed to remove reviewer who left PR's Slack channel", slog.Any("error", err),
+			slog.String("user_id", e.User), slog.String("pr_url", prURL))
+		fallthrough
+
+	case config.ReviewerLeftUntrack:
+		if err := data.RemoveReviewerFromTurns(ctx, c.TemporalOpts, prURL, email, false); err != nil {
+			return err
+		}
+		return activities.PostMessage(ctx, e.Channel, fmt.Sprintf(":door: %s left this channel, so RevChat "+
+			"stopped tracking their turn in this PR, but they are still one of its reviewers.", name))
+
+	default: // [config.ReviewerLeftNote].
+		return activities.PostMessage(ctx, e.Channel, fmt.Sprintf(
+			":door: %s left this channel, but they are still one of this PR's reviewers.", name))
+	}
+}
+
+// removeReviewer removes a reviewer from a PR on their own behalf, so it requires them to be opted-in.
+func (c *Config) removeReviewer(ctx workflow.Context, userID, prURL string) error {
+	user, optedIn, err := data.SelectUserBySlackID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !optedIn {
+		return errors.New("user is not opted-in")
+	}
+
+	if strings.HasPrefix(prURL, "https://bitbucket.org/") {
+		return bitbucket.RemovePullRequestReviewers(ctx, user.ThrippyLink, prURL, []string{user.BitbucketID})
+	}
+	if datacenter.IsURL(prURL) {
+		return bitbucket.RemoveDataCenterReviewers(ctx, user.ThrippyLink, prURL, []string{datacenter.AccountID(user.Email)})
+	}
+
+	if !c.GitHubReviewerRequests {
+		return errors.New("removing GitHub PR reviewers is not enabled")
+	}
+	url := commands.PullRequestURLPattern.FindStringSubmatch(prURL)
+	if len(url) < 6 {
+		return errors.New("invalid PR URL: " + prURL)
+	}
+	prID, err := strconv.Atoi(url[5])
+	if err != nil {
+		return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+	}
+
+	return github.RemoveRequestedReviewers(ctx, user.ThrippyLink, url[2], url[3], prID, []string{user.GitHubID})
 }
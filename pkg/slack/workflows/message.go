@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
 	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
 	"github.com/tzrikka/revchat/pkg/slack/commands"
@@ -21,6 +23,9 @@ func (c *Config) MessageWorkflow(ctx workflow.Context, event messageEventWrapper
 	// Instead of calling ![isRevChatChannel], because we also need the PR's URL below.
 	prURL, _ := c.switchURLAndID(ctx, event.InnerEvent.Channel)
 	if prURL == "" {
+		if event.InnerEvent.ChannelType == "im" {
+			return c.importCalendar(ctx, event, extractUserID(ctx, &event.InnerEvent))
+		}
 		return c.triggerNudge(ctx, event, extractUserID(ctx, &event.InnerEvent))
 	}
 
@@ -42,7 +47,7 @@ func (c *Config) MessageWorkflow(ctx workflow.Context, event messageEventWrapper
 		return nil
 	}
 
-	isBitbucket := strings.HasPrefix(prURL, "https://bitbucket.org/")
+	isBitbucket := strings.HasPrefix(prURL, "https://bitbucket.org/") || datacenter.IsURL(prURL)
 	switch subtype {
 	case "", "bot_message", "file_share", "thread_broadcast":
 		return c.createMessage(ctx, event.InnerEvent, userID, isBitbucket)
@@ -100,21 +105,26 @@ func convertBotIDToUserID(ctx workflow.Context, botID string) string {
 	return bot.UserID
 }
 
-// urlParts converts the given Slack ID(s) to the corresponding PR or comment
-// URL, and then extracts and returns its parts, based on [urlPattern].
-func (c *Config) urlParts(ctx workflow.Context, ids string) ([]string, error) {
+// lookupURL converts the given Slack ID(s) to the corresponding PR, PR comment, or commit comment URL.
+func (c *Config) lookupURL(ctx workflow.Context, ids string) (string, error) {
 	url, err := c.switchURLAndID(ctx, ids)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	if url == "" {
 		// When calling this function, we already confirmed that the channel is a
 		// RevChat channel and the user is opted-in, so there should be a mapping.
 		logger.From(ctx).Error("didn't find Slack message's PR comment URL", slog.String("slack_ids", ids))
 		err := errors.New("didn't find Slack message's PR comment URL")
-		return nil, activities.AlertError(ctx, c.AlertsChannel, "", err, "Slack IDs", fmt.Sprintf("`%s`", ids))
+		return "", activities.AlertError(ctx, c.AlertsChannel, "", err, "Slack IDs", fmt.Sprintf("`%s`", ids))
 	}
 
+	return url, nil
+}
+
+// urlParts extracts and returns the parts of a PR or PR comment URL
+// (see [Config.lookupURL]), based on [commands.PullRequestURLPattern].
+func (c *Config) urlParts(ctx workflow.Context, ids, url string) ([]string, error) {
 	parts := commands.PullRequestURLPattern.FindStringSubmatch(url)
 	if len(parts) != 8 {
 		logger.From(ctx).Error("failed to parse Slack message's PR comment URL",
@@ -125,3 +135,20 @@ func (c *Config) urlParts(ctx workflow.Context, ids string) ([]string, error) {
 
 	return parts, nil
 }
+
+// commentURLType returns the type-specific prefix of the comment ID in PR comment
+// URL parts (see [Config.urlParts]), e.g. "#issuecomment-" or "#discussion_r" in GitHub.
+func commentURLType(url []string) string {
+	return strings.TrimSuffix(url[6], url[7])
+}
+
+// commitCommentURLPattern matches the URLs of Bitbucket commit comments, which are mirrored in the
+// Slack channels of PRs that contain the commit, even though they are not PR comments themselves.
+var commitCommentURLPattern = regexp.MustCompile(`^https://bitbucket\.org/([^/]+)/([^/]+)/commits/([0-9a-f]+)#comment-(\d+)$`)
+
+// commitCommentParts extracts and returns the parts of a Bitbucket commit comment URL (see [Config.lookupURL]),
+// based on [commitCommentURLPattern]. It returns nil for any other type of URL, which should be handled
+// by [Config.urlParts].
+func commitCommentParts(url string) []string {
+	return commitCommentURLPattern.FindStringSubmatch(url)
+}
@@ -0,0 +1,44 @@
+package workflows
+
+import (
+	"strings"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+)
+
+const icsHeader = "BEGIN:VCALENDAR"
+
+// importCalendar examines new messages in DMs with RevChat. If they contain the content of an
+// iCalendar file, either as text or as an uploaded file, it imports the user's out-of-office
+// periods from it. Slack's preview of uploaded files is used, because there's no file download.
+func (c *Config) importCalendar(ctx workflow.Context, event messageEventWrapper, senderID string) error {
+	e := event.InnerEvent
+	switch {
+	case e.Subtype != "" && e.Subtype != "file_share":
+		return nil
+	case senderID == "" || selfTriggeredEvent(ctx, event.Authorizations, senderID):
+		return nil
+	}
+
+	ics := e.Text
+	for _, f := range e.Files {
+		if !strings.Contains(f.Preview, icsHeader) && !strings.HasSuffix(strings.ToLower(f.Name), ".ics") {
+			continue
+		}
+		if f.PreviewIsTruncated || f.Preview == "" {
+			msg := ":warning: Error: this calendar file is too long for me to read, please paste its content in a message instead."
+			return activities.PostMessage(ctx, e.Channel, msg)
+		}
+		ics = f.Preview
+		break
+	}
+
+	if !strings.Contains(ics, icsHeader) {
+		return nil
+	}
+
+	return commands.ImportCalendar(ctx, e.Channel, senderID, ics)
+}
@@ -3,12 +3,16 @@ package workflows
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
-	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
 	"github.com/tzrikka/revchat/pkg/markdown"
 )
 
@@ -28,7 +32,18 @@ func (c *Config) changeMessage(ctx workflow.Context, event MessageEvent, userID
 		slackIDs = fmt.Sprintf("%s/%s/%s", event.Channel, event.Message.ThreadTS, event.Message.TS)
 	}
 
-	url, err := c.urlParts(ctx, slackIDs)
+	commentURL, err := c.lookupURL(ctx, slackIDs)
+	if err != nil {
+		return err
+	}
+	if url := commitCommentParts(commentURL); url != nil {
+		if !c.BitbucketCommitComments {
+			return nil
+		}
+		return editCommitCommentInBitbucket(ctx, event, thrippyID, url)
+	}
+
+	url, err := c.urlParts(ctx, slackIDs, commentURL)
 	if err != nil {
 		return err
 	}
@@ -42,10 +57,40 @@ func (c *Config) changeMessage(ctx workflow.Context, event MessageEvent, userID
 func editMessageInBitbucket(ctx workflow.Context, event MessageEvent, thrippyID string, url []string) error {
 	msg, _ := strings.CutSuffix(event.Message.Text, "\n\n[This comment was updated by RevChat]: #")
 	msg = markdown.SlackToBitbucket(ctx, msg) + "\n\n[This comment was updated by RevChat]: #"
-	return activities.UpdatePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7], msg)
+	if datacenter.IsURL(url[0]) {
+		return bitbucket.UpdateDataCenterComment(ctx, thrippyID, url[0], msg)
+	}
+	return bitbucket.UpdatePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7], msg)
+}
+
+func editCommitCommentInBitbucket(ctx workflow.Context, event MessageEvent, thrippyID string, url []string) error {
+	msg, _ := strings.CutSuffix(event.Message.Text, "\n\n[This comment was updated by RevChat]: #")
+	msg = markdown.SlackToBitbucket(ctx, msg) + "\n\n[This comment was updated by RevChat]: #"
+	return bitbucket.UpdateCommitComment(ctx, thrippyID, url[1], url[2], url[3], url[4], msg)
 }
 
-func editMessageInGitHub(ctx workflow.Context, _ MessageEvent, _ string, _ []string) error {
-	logger.From(ctx).Error("edit Slack message in GitHub - not implemented yet")
-	return errors.New("edit Slack message in GitHub - not implemented yet")
+func editMessageInGitHub(ctx workflow.Context, event MessageEvent, thrippyID string, url []string) error {
+	msg, _ := strings.CutSuffix(event.Message.Text, "\n\n[This comment was updated by RevChat]: #")
+	msg = markdown.SlackToGitHub(ctx, msg) + "\n\n[This comment was updated by RevChat]: #"
+
+	commentID, err := strconv.Atoi(url[7])
+	if err != nil {
+		return fmt.Errorf("failed to parse comment ID %q: %w", url[7], err)
+	}
+
+	switch commentURLType(url) {
+	case "#issuecomment-":
+		return github.UpdateIssueComment(ctx, thrippyID, url[2], url[3], commentID, msg)
+	case "#discussion_r":
+		return github.UpdateReviewComment(ctx, thrippyID, url[2], url[3], commentID, msg)
+	case "#pullrequestreview-":
+		prID, err := strconv.Atoi(url[5])
+		if err != nil {
+			return fmt.Errorf("failed to parse PR number %q: %w", url[5], err)
+		}
+		return github.UpdateReview(ctx, thrippyID, url[2], url[3], prID, commentID, msg)
+	default:
+		logger.From(ctx).Error("unrecognized GitHub comment URL", slog.String("comment_url", url[0]))
+		return errors.New("unrecognized GitHub comment URL: " + url[0])
+	}
 }
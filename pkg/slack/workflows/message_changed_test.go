@@ -0,0 +1,107 @@
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+)
+
+const testGitHubPRURL = "https://github.com/owner/repo/pull/1"
+
+// newGitHubMessageTestEnv returns a test environment for workflow functions that propagate
+// Slack message changes to GitHub, and a pointer to a summary of all the activity calls.
+func newGitHubMessageTestEnv(t *testing.T, wf any) (*testsuite.TestWorkflowEnvironment, *[]string) {
+	t.Helper()
+
+	s := testsuite.WorkflowTestSuite{}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflowWithOptions(wf, workflow.RegisterOptions{Name: "test"})
+
+	calls := []string{}
+	for _, name := range []string{
+		"github.issues.comments.update", "github.issues.comments.delete",
+		"github.pulls.reviewComments.update", "github.pulls.reviewComments.delete",
+		"github.pulls.reviews.update", "slack.chat.postEphemeral",
+		"github.reactions.createForIssueComment", "github.reactions.createForPullRequestReviewComment",
+	} {
+		env.RegisterActivityWithOptions(func(_ context.Context, req map[string]any) (map[string]any, error) {
+			call := name
+			for _, key := range []string{"comment_id", "review_id", "body", "text", "content"} {
+				if v, ok := req[key]; ok {
+					call += fmt.Sprintf(" %s=%v", key, v)
+				}
+			}
+			calls = append(calls, call)
+			return map[string]any{"ok": true}, nil
+		}, activity.RegisterOptions{Name: name})
+	}
+
+	return env, &calls
+}
+
+func TestEditMessageInGitHub(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		text    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "issue_comment",
+			url:  testGitHubPRURL + "#issuecomment-123",
+			text: "New text",
+			want: []string{"github.issues.comments.update comment_id=123 body=New text\n\n[This comment was updated by RevChat]: #"},
+		},
+		{
+			name: "review_comment",
+			url:  testGitHubPRURL + "#discussion_r123",
+			text: "New text",
+			want: []string{"github.pulls.reviewComments.update comment_id=123 body=New text\n\n[This comment was updated by RevChat]: #"},
+		},
+		{
+			name: "review",
+			url:  testGitHubPRURL + "#pullrequestreview-123",
+			text: "New text",
+			want: []string{"github.pulls.reviews.update review_id=123 body=New text\n\n[This comment was updated by RevChat]: #"},
+		},
+		{
+			name: "previously_updated_comment",
+			url:  testGitHubPRURL + "#issuecomment-123",
+			text: "New text\n\n[This comment was updated by RevChat]: #",
+			want: []string{"github.issues.comments.update comment_id=123 body=New text\n\n[This comment was updated by RevChat]: #"},
+		},
+		{
+			name:    "unrecognized_url",
+			url:     testGitHubPRURL + "#commits-123",
+			text:    "New text",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, calls := newGitHubMessageTestEnv(t, editMessageInGitHub)
+			url := commands.PullRequestURLPattern.FindStringSubmatch(tt.url)
+			event := MessageEvent{Message: &MessageEvent{Text: tt.text}}
+			env.ExecuteWorkflow("test", event, "link", url)
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("editMessageInGitHub() didn't complete")
+			}
+			if err := env.GetWorkflowError(); (err != nil) != tt.wantErr {
+				t.Fatalf("editMessageInGitHub() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(*calls, tt.want) {
+				t.Errorf("editMessageInGitHub() calls = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	github "github.com/tzrikka/revchat/pkg/github/activities"
 	"github.com/tzrikka/revchat/pkg/markdown"
@@ -33,13 +34,28 @@ func (c *Config) createMessage(ctx workflow.Context, event MessageEvent, userID
 		slackIDs = fmt.Sprintf("%s/%s", slackIDs, event.ThreadTS)
 	}
 
-	parentURL, err := c.urlParts(ctx, slackIDs)
+	url, err := c.lookupURL(ctx, slackIDs)
 	if err != nil {
 		return err
 	}
 
+	commitURL := commitCommentParts(url)
+	var parentURL []string
+	switch {
+	case commitURL != nil && !c.BitbucketCommitComments:
+		msg := ":warning: Replies to commit comments are not mirrored in Bitbucket by RevChat yet."
+		_ = activities.PostEphemeralMessage(ctx, event.Channel, userID, msg)
+		return nil
+	case commitURL == nil:
+		if parentURL, err = c.urlParts(ctx, slackIDs, url); err != nil {
+			return err
+		}
+	}
+
 	var newCommentURL string
 	switch {
+	case commitURL != nil:
+		newCommentURL, err = createCommitCommentReplyInBitbucket(ctx, event, thrippyID, commitURL)
 	case isBitbucket:
 		newCommentURL, err = createCommentInBitbucket(ctx, event, thrippyID, parentURL)
 	case event.ThreadTS == "":
@@ -60,9 +76,19 @@ func createCommentInBitbucket(ctx workflow.Context, event MessageEvent, thrippyI
 	msg := markdown.SlackToBitbucket(ctx, event.Text) + fileLinks(event.Files, true)
 	msg += "\n\n[This comment was created by RevChat]: #"
 
+	if datacenter.IsURL(url[0]) {
+		return bitbucket.CreateDataCenterComment(ctx, thrippyID, url[0], msg)
+	}
 	return bitbucket.CreatePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7], msg)
 }
 
+func createCommitCommentReplyInBitbucket(ctx workflow.Context, event MessageEvent, thrippyID string, url []string) (string, error) {
+	msg := markdown.SlackToBitbucket(ctx, event.Text) + fileLinks(event.Files, true)
+	msg += "\n\n[This comment was created by RevChat]: #"
+
+	return bitbucket.CreateCommitComment(ctx, thrippyID, url[1], url[2], url[3], url[4], msg)
+}
+
 func createReviewInGitHub(ctx workflow.Context, event MessageEvent, thrippyID string, url []string) (string, error) {
 	msg := markdown.SlackToGitHub(ctx, event.Text) + fileLinks(event.Files, false)
 	msg += "\n\n[This comment was created by RevChat]: #"
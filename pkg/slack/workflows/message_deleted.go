@@ -3,12 +3,17 @@ package workflows
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
-	"github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
 )
 
 func (c *Config) deleteMessage(ctx workflow.Context, event MessageEvent, userID string, isBitbucket bool) error {
@@ -27,7 +32,19 @@ func (c *Config) deleteMessage(ctx workflow.Context, event MessageEvent, userID
 		slackIDs = fmt.Sprintf("%s/%s/%s", event.Channel, event.PreviousMessage.ThreadTS, event.DeletedTS)
 	}
 
-	url, err := c.urlParts(ctx, slackIDs)
+	commentURL, err := c.lookupURL(ctx, slackIDs)
+	if err != nil {
+		return err
+	}
+	if url := commitCommentParts(commentURL); url != nil {
+		data.DeleteURLAndIDMapping(ctx, url[0])
+		if !c.BitbucketCommitComments {
+			return nil
+		}
+		return bitbucket.DeleteCommitComment(ctx, thrippyID, url[1], url[2], url[3], url[4])
+	}
+
+	url, err := c.urlParts(ctx, slackIDs, commentURL)
 	if err != nil {
 		return err
 	}
@@ -35,15 +52,39 @@ func (c *Config) deleteMessage(ctx workflow.Context, event MessageEvent, userID
 	if isBitbucket {
 		return deleteMessageInBitbucket(ctx, thrippyID, url)
 	}
-	return deleteMessageInGitHub(ctx, thrippyID, url)
+	return deleteMessageInGitHub(ctx, event.Channel, userID, thrippyID, url)
 }
 
 func deleteMessageInBitbucket(ctx workflow.Context, thrippyID string, url []string) error {
 	data.DeleteURLAndIDMapping(ctx, url[0])
-	return activities.DeletePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7])
+	if datacenter.IsURL(url[0]) {
+		return bitbucket.DeleteDataCenterComment(ctx, thrippyID, url[0])
+	}
+	return bitbucket.DeletePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7])
 }
 
-func deleteMessageInGitHub(ctx workflow.Context, _ string, _ []string) error {
-	logger.From(ctx).Error("delete Slack message in GitHub - not implemented yet")
-	return errors.New("delete Slack message in GitHub - not implemented yet")
+func deleteMessageInGitHub(ctx workflow.Context, channelID, userID, thrippyID string, url []string) error {
+	commentID, err := strconv.Atoi(url[7])
+	if err != nil {
+		return fmt.Errorf("failed to parse comment ID %q: %w", url[7], err)
+	}
+
+	switch commentURLType(url) {
+	case "#issuecomment-":
+		data.DeleteURLAndIDMapping(ctx, url[0])
+		return github.DeleteIssueComment(ctx, thrippyID, url[2], url[3], commentID)
+	case "#discussion_r":
+		data.DeleteURLAndIDMapping(ctx, url[0])
+		return github.DeleteReviewComment(ctx, thrippyID, url[2], url[3], commentID)
+	case "#pullrequestreview-":
+		// GitHub doesn't allow deleting submitted reviews, only pending ones.
+		logger.From(ctx).Warn("can't delete submitted GitHub PR review", slog.String("review_url", url[0]))
+		data.DeleteURLAndIDMapping(ctx, url[0])
+		msg := fmt.Sprintf(":warning: GitHub doesn't allow deleting submitted PR reviews, so <%s|this review> still exists there.", url[0])
+		_ = activities.PostEphemeralMessage(ctx, channelID, userID, msg)
+		return nil
+	default:
+		logger.From(ctx).Error("unrecognized GitHub comment URL", slog.String("comment_url", url[0]))
+		return errors.New("unrecognized GitHub comment URL: " + url[0])
+	}
 }
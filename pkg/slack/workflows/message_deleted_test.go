@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/data"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+)
+
+func TestDeleteMessageInGitHub(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		want        []string
+		wantErr     bool
+		wantMapping bool
+	}{
+		{
+			name: "issue_comment",
+			url:  testGitHubPRURL + "#issuecomment-123",
+			want: []string{"github.issues.comments.delete comment_id=123"},
+		},
+		{
+			name: "review_comment",
+			url:  testGitHubPRURL + "#discussion_r123",
+			want: []string{"github.pulls.reviewComments.delete comment_id=123"},
+		},
+		{
+			name: "submitted_review",
+			url:  testGitHubPRURL + "#pullrequestreview-123",
+			want: []string{"slack.chat.postEphemeral text=:warning: GitHub doesn't allow deleting submitted PR reviews, " +
+				"so <" + testGitHubPRURL + "#pullrequestreview-123|this review> still exists there."},
+		},
+		{
+			name:        "unrecognized_url",
+			url:         testGitHubPRURL + "#commits-123",
+			wantErr:     true,
+			wantMapping: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			if err := data.MapURLAndID(nil, tt.url, "C1/T1"); err != nil {
+				t.Fatalf("MapURLAndID() error = %v", err)
+			}
+
+			env, calls := newGitHubMessageTestEnv(t, deleteMessageInGitHub)
+			url := commands.PullRequestURLPattern.FindStringSubmatch(tt.url)
+			env.ExecuteWorkflow("test", "C1", "U1", "link", url)
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("deleteMessageInGitHub() didn't complete")
+			}
+			if err := env.GetWorkflowError(); (err != nil) != tt.wantErr {
+				t.Fatalf("deleteMessageInGitHub() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(*calls, tt.want) {
+				t.Errorf("deleteMessageInGitHub() calls = %q, want %q", *calls, tt.want)
+			}
+
+			ids, err := data.SwitchURLAndID(nil, tt.url)
+			if err != nil {
+				t.Fatalf("SwitchURLAndID() error = %v", err)
+			}
+			if gotMapping := ids != ""; gotMapping != tt.wantMapping {
+				t.Errorf("deleteMessageInGitHub() kept mapping = %v, want %v", gotMapping, tt.wantMapping)
+			}
+		})
+	}
+}
@@ -153,7 +153,7 @@ func (c *Config) nudge(ctx workflow.Context, event MessageEvent, recipients []st
 	return activities.PostEphemeralMessage(ctx, event.Channel, senderID, msg)
 }
 
-// checkAndNudgeUser ensures that the recipient exists, is opted-in, and is a reviewer of the PR.
+// checkAndNudgeUser ensures that the recipient exists, is opted-in, isn't away, and is a reviewer of the PR.
 // It returns true if the attention state was updated. If not, it also posts an explanation.
 func (c *Config) checkAndNudgeUser(ctx workflow.Context, event MessageEvent, prURL, userID string) bool {
 	// Check other conditions, send error messages as needed.
@@ -166,6 +166,10 @@ func (c *Config) checkAndNudgeUser(ctx workflow.Context, event MessageEvent, prU
 		_ = activities.PostEphemeralMessage(ctx, event.Channel, userID, msg)
 		return false
 	}
+	if msg := commands.AwayText(ctx, user); msg != "" {
+		_ = activities.PostEphemeralMessage(ctx, event.Channel, userID, msg)
+		return false
+	}
 
 	// Update the PR's attention state.
 	ok, approved, err := data.SetReviewerTurn(ctx, c.TemporalOpts, prURL, user.Email, true)
@@ -228,3 +228,49 @@ type ReactionEvent struct {
 
 	EventTS string `json:"event_ts"`
 }
+
+// https://docs.slack.dev/reference/interaction-payloads/block_actions-payload/
+type BlockActionsEvent struct {
+	// Type string `json:"type"` // Always "block_actions".
+
+	APIAppID  string `json:"api_app_id"`
+	TriggerID string `json:"trigger_id"`
+
+	Team struct {
+		ID     string `json:"id"`
+		Domain string `json:"domain"`
+	} `json:"team"`
+
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		TeamID   string `json:"team_id"`
+	} `json:"user"`
+
+	Container struct {
+		Type        string `json:"type"`
+		MessageTS   string `json:"message_ts,omitempty"`
+		ChannelID   string `json:"channel_id,omitempty"`
+		IsEphemeral bool   `json:"is_ephemeral,omitempty"`
+	} `json:"container"`
+
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel"`
+
+	Message *MessageEvent `json:"message,omitempty"`
+
+	Actions []BlockAction `json:"actions"`
+
+	ResponseURL string `json:"response_url,omitempty"`
+}
+
+// https://docs.slack.dev/reference/interaction-payloads/block_actions-payload/#fields
+type BlockAction struct {
+	Type     string `json:"type"`
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Value    string `json:"value,omitempty"`
+	ActionTS string `json:"action_ts"`
+}
@@ -1,31 +1,178 @@
 package workflows
 
 import (
+	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	bitbucket "github.com/tzrikka/revchat/pkg/bitbucket/activities"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
+	"github.com/tzrikka/revchat/pkg/config"
+	"github.com/tzrikka/revchat/pkg/data"
+	github "github.com/tzrikka/revchat/pkg/github/activities"
+	"github.com/tzrikka/revchat/pkg/markdown"
+	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
 )
 
 // ReactionAddedWorkflow mirrors the addition of a reaction to a Slack message in
 // the corresponding PR comment: https://docs.slack.dev/reference/events/reaction_added/
-func ReactionAddedWorkflow(ctx workflow.Context, event reactionEventWrapper) error {
+func (c *Config) ReactionAddedWorkflow(ctx workflow.Context, event reactionEventWrapper) error {
 	if selfTriggeredEvent(ctx, event.Authorizations, event.InnerEvent.User) {
 		return nil
 	}
 
-	logger.From(ctx).Debug("Slack reaction added event - not implemented yet", slog.Any("event", event))
-	return nil
+	return c.mirrorReaction(ctx, event.InnerEvent, true)
 }
 
 // ReactionRemovedWorkflow mirrors the removal of a reaction from a Slack message in
 // the corresponding PR comment: https://docs.slack.dev/reference/events/reaction_removed/
-func ReactionRemovedWorkflow(ctx workflow.Context, event reactionEventWrapper) error {
+func (c *Config) ReactionRemovedWorkflow(ctx workflow.Context, event reactionEventWrapper) error {
 	if selfTriggeredEvent(ctx, event.Authorizations, event.InnerEvent.User) {
 		return nil
 	}
 
-	logger.From(ctx).Debug("Slack reaction removed event - not implemented yet", slog.Any("event", event))
-	return nil
+	return c.mirrorReaction(ctx, event.InnerEvent, false)
+}
+
+// mirrorReaction mirrors a Slack reaction as a GitHub reaction, if there's a corresponding one (and the
+// "github-reactions" flag is set). Bitbucket doesn't support comment reactions, so they are either ignored or
+// mirrored as replies, depending on the "bitbucket-reactions" flag. Reactions to Slack messages which aren't
+// mirrored PR comments are ignored.
+func (c *Config) mirrorReaction(ctx workflow.Context, event ReactionEvent, added bool) error {
+	if event.Item.Type != "message" {
+		return nil
+	}
+
+	// Instead of calling ![isRevChatChannel], because we also need the PR's URL below.
+	prURL, _ := c.switchURLAndID(ctx, event.Item.Channel)
+	if prURL == "" {
+		return nil
+	}
+
+	isBitbucket := strings.HasPrefix(prURL, "https://bitbucket.org/") || datacenter.IsURL(prURL)
+	content := markdown.SlackToGitHubReaction(event.Reaction)
+	if (isBitbucket && c.BitbucketReactions != config.BitbucketReactionsReply) || (!isBitbucket && (!c.GitHubReactions || content == "")) {
+		return nil
+	}
+
+	slackIDs, url := reactionTarget(ctx, event.Item.Channel, event.Item.TS)
+	if url == nil {
+		return nil
+	}
+
+	thrippyID, err := c.thrippyLinkID(ctx, event.User, event.Item.Channel)
+	if err != nil || thrippyID == "" {
+		return err
+	}
+
+	if isBitbucket {
+		return c.mirrorReactionInBitbucket(ctx, event, thrippyID, slackIDs, url, added)
+	}
+	return mirrorReactionInGitHub(ctx, event, thrippyID, content, url, added)
+}
+
+// reactionTarget returns the Slack IDs of a mirrored PR comment, and the parts of its URL (based on
+// [commands.PullRequestURLPattern]). Reaction events don't specify whether the message is a thread reply,
+// so if it's not a top-level message we need to retrieve its thread's timestamp from Slack.
+func reactionTarget(ctx workflow.Context, channelID, timestamp string) (string, []string) {
+	slackIDs := fmt.Sprintf("%s/%s", channelID, timestamp)
+	url, err := data.SwitchURLAndID(ctx, slackIDs)
+	if err != nil {
+		return "", nil
+	}
+
+	if url == "" {
+		threadTS, err := activities.ThreadTS(ctx, channelID, timestamp)
+		if err != nil || threadTS == "" {
+			return "", nil
+		}
+		slackIDs = fmt.Sprintf("%s/%s/%s", channelID, threadTS, timestamp)
+		if url, err = data.SwitchURLAndID(ctx, slackIDs); err != nil || url == "" {
+			return "", nil
+		}
+	}
+
+	// Ignore messages which aren't PR comments (e.g. Bitbucket commit comments).
+	parts := commands.PullRequestURLPattern.FindStringSubmatch(url)
+	if len(parts) != 8 || parts[7] == "" {
+		return "", nil
+	}
+
+	return slackIDs, parts
+}
+
+// mirrorReactionInBitbucket posts (or deletes) a reply with the reaction's emoji to
+// the PR comment, on behalf of the user, and maps the reply to the Slack reaction.
+func (c *Config) mirrorReactionInBitbucket(ctx workflow.Context, event ReactionEvent, thrippyID, slackIDs string, url []string, added bool) error {
+	reactionIDs := fmt.Sprintf("%s/reactions/%s/%s", slackIDs, event.User, event.Reaction)
+
+	if !added {
+		replyURL, err := data.SwitchURLAndID(ctx, reactionIDs)
+		if err != nil || replyURL == "" {
+			return err
+		}
+
+		// Forget the reply even if deleting it fails, so adding the same reaction again won't be ignored.
+		data.DeleteURLAndIDMapping(ctx, reactionIDs)
+		reply := commands.PullRequestURLPattern.FindStringSubmatch(replyURL)
+		if len(reply) != 8 {
+			return nil
+		}
+		return deleteMessageInBitbucket(ctx, thrippyID, reply)
+	}
+
+	msg := markdown.SlackToBitbucketEmoji(":"+event.Reaction+":") + "\n\n[This comment was created by RevChat]: #"
+
+	var replyURL string
+	var err error
+	if datacenter.IsURL(url[0]) {
+		replyURL, err = bitbucket.CreateDataCenterComment(ctx, thrippyID, url[0], msg)
+	} else {
+		replyURL, err = bitbucket.CreatePullRequestComment(ctx, thrippyID, url[2], url[3], url[5], url[7], msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	return activities.AlertError(ctx, c.AlertsChannel, "failed to set mapping between a new PR comment and its Slack IDs",
+		data.MapURLAndID(ctx, replyURL, reactionIDs), "Comment URL", replyURL, "Slack IDs", reactionIDs)
+}
+
+// mirrorReactionInGitHub adds (or removes) a reaction to the PR comment, on behalf of the user.
+// GitHub doesn't support reactions to PR reviews, so they are ignored.
+func mirrorReactionInGitHub(ctx workflow.Context, event ReactionEvent, thrippyID, content string, url []string, added bool) error {
+	commentID, err := strconv.Atoi(url[7])
+	if err != nil {
+		return fmt.Errorf("failed to parse comment ID %q: %w", url[7], err)
+	}
+
+	login := ""
+	if !added {
+		user, _, err := data.SelectUserBySlackID(ctx, event.User)
+		if err != nil {
+			return err
+		}
+		login = user.GitHubID
+	}
+
+	switch commentURLType(url) {
+	case "#issuecomment-":
+		if added {
+			return github.CreateIssueCommentReaction(ctx, thrippyID, url[2], url[3], commentID, content)
+		}
+		return github.DeleteIssueCommentReaction(ctx, thrippyID, url[2], url[3], commentID, content, login)
+	case "#discussion_r":
+		if added {
+			return github.CreateReviewCommentReaction(ctx, thrippyID, url[2], url[3], commentID, content)
+		}
+		return github.DeleteReviewCommentReaction(ctx, thrippyID, url[2], url[3], commentID, content, login)
+	default:
+		logger.From(ctx).Debug("ignoring reaction to unsupported GitHub comment type", slog.String("comment_url", url[0]))
+		return nil
+	}
 }
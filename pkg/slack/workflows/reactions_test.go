@@ -0,0 +1,50 @@
+package workflows
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+)
+
+func TestMirrorReactionInGitHub(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{
+			name: "issue_comment",
+			url:  testGitHubPRURL + "#issuecomment-123",
+			want: []string{"github.reactions.createForIssueComment comment_id=123 content=heart"},
+		},
+		{
+			name: "review_comment",
+			url:  testGitHubPRURL + "#discussion_r123",
+			want: []string{"github.reactions.createForPullRequestReviewComment comment_id=123 content=heart"},
+		},
+		{
+			name: "submitted_review",
+			url:  testGitHubPRURL + "#pullrequestreview-123",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, calls := newGitHubMessageTestEnv(t, mirrorReactionInGitHub)
+			url := commands.PullRequestURLPattern.FindStringSubmatch(tt.url)
+			env.ExecuteWorkflow("test", ReactionEvent{User: "U1", Reaction: "heart"}, "link", "heart", url, true)
+
+			if !env.IsWorkflowCompleted() {
+				t.Fatal("mirrorReactionInGitHub() didn't complete")
+			}
+			if err := env.GetWorkflowError(); err != nil {
+				t.Fatalf("mirrorReactionInGitHub() error = %v", err)
+			}
+			if !slices.Equal(*calls, tt.want) {
+				t.Errorf("mirrorReactionInGitHub() calls = %q, want %q", *calls, tt.want)
+			}
+		})
+	}
+}
@@ -15,15 +15,28 @@ import (
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/revchat/pkg/slack"
 	"github.com/tzrikka/revchat/pkg/slack/activities"
+	"github.com/tzrikka/revchat/pkg/slack/commands"
+	"github.com/tzrikka/revchat/pkg/users"
 )
 
 const (
 	dateTimeLayout = time.DateOnly + " " + time.Kitchen
+
+	// maxRemindersPerMessage is based on Slack's limit of 50 blocks per message
+	// (https://docs.slack.dev/reference/block-kit/blocks), where each PR uses 2 blocks.
+	maxRemindersPerMessage = 20
+
+	reminderActionApprove     = "reminder_approve"
+	reminderActionNotMyTurn   = "reminder_not_my_turn"
+	reminderActionSnooze      = "reminder_snooze"
+	reminderActionOpenChannel = "reminder_open_channel"
+
+	reminderHeader = ":bell: This is your scheduled daily reminder to take action on these PRs:"
 )
 
 func (c *Config) RemindersWorkflow(ctx workflow.Context) error {
 	startTime := workflow.Now(ctx).UTC().Truncate(time.Minute)
-	var users []string
+	var userIDs []string
 
 	reminders, err := data.ListScheduledUserReminders(ctx)
 	if err != nil {
@@ -31,30 +44,46 @@ func (c *Config) RemindersWorkflow(ctx workflow.Context) error {
 	}
 
 	var aggregatedErr error
-	for userID, r := range reminders {
-		now, reminderTime, err := reminderTimes(ctx, startTime, userID, r)
+	for _, userID := range slices.Sorted(maps.Keys(reminders)) { //workflowcheck:ignore // Sorted for deterministic order.
+		user, _, err := data.SelectUserBySlackID(ctx, userID)
+		if err == nil {
+			// Away periods may start between the user's daily reminders.
+			commands.HandOverAwayTurns(ctx, c.TemporalOpts, user)
+		}
+
+		now, reminderTime, err := reminderTimes(ctx, startTime, userID, reminders[userID])
 		if err != nil {
 			err = activities.AlertError(ctx, c.AlertsChannel, "", err, "User", fmt.Sprintf("<@%s>", userID))
 			aggregatedErr = errors.Join(aggregatedErr, err)
 			continue
 		}
 
-		if reminderTime.Equal(now) {
-			users = append(users, userID)
+		if !reminderTime.Equal(now) {
+			continue
 		}
+		if _, away := user.AwayAt(startTime); away {
+			continue // Reminders are paused while the user is out of office.
+		}
+		userIDs = append(userIDs, userID)
 	}
-	if len(users) == 0 {
+	if len(userIDs) == 0 {
 		return aggregatedErr
 	}
 
-	userPRs, userAlerts := data.ListPRsPerSlackUser(ctx, c.TemporalOpts, true, true, true, users)
+	userPRs, userAlerts := data.ListPRsPerSlackUser(ctx, c.TemporalOpts, true, true, true, userIDs)
 	for _, details := range userAlerts {
 		activities.AlertWarn(ctx, c.AlertsChannel, "Slack email lookup failed - removed email from turn(s)", details...)
 	}
 
 	keys := slices.Sorted(maps.Keys(userPRs)) //workflowcheck:ignore // Sorted for deterministic order.
+	var allPRs []string
+	for _, user := range keys {
+		allPRs = append(allPRs, userPRs[user]...)
+	}
+	urgent := slack.UrgentPRs(ctx, allPRs, c.LabelRules)
+
 	for _, user := range keys {
-		prs := userPRs[user]
+		prs, _ := commands.WithoutSnoozedPRs(ctx, c.TemporalOpts, user, userPRs[user])
 		if len(prs) == 0 {
 			continue
 		}
@@ -62,37 +91,101 @@ func (c *Config) RemindersWorkflow(ctx workflow.Context) error {
 		logger.From(ctx).Info("sending scheduled Slack reminder to user",
 			slog.String("user_id", user), slog.Int("pr_count", len(prs)))
 		slices.Sort(prs)
+		prs = slack.UrgentPRsFirst(prs, urgent)
 
-		var msg strings.Builder
-		msg.WriteString(":bell: This is your scheduled daily reminder to take action on these PRs:")
+		blocks := []map[string]any{markdownSection(reminderHeader)}
 		singleUser := []string{user}
+		email := users.SlackIDToEmail(ctx, user)
+		count := 0
 
 		for _, prURL := range prs {
 			prDetails := slack.PRDetails(ctx, c.TemporalOpts, prURL, singleUser, true, c.ReportDrafts, false, "")
+			if prDetails == "" {
+				continue
+			}
 
-			// If the message becomes too long, split it into multiple chunks,
-			// even if the Slack API could technically handle a bit more.
-			// 325 is a safety margin for the additional text added below.
-			if msg.Len()+len(prDetails) > 4000-325 {
-				aggregatedErr = errors.Join(aggregatedErr, activities.PostMessage(ctx, user, msg.String()))
-				msg.Reset()
+			// If the message becomes too long, split it into multiple chunks.
+			if count == maxRemindersPerMessage {
+				aggregatedErr = errors.Join(aggregatedErr, activities.PostMessageWithBlocks(ctx, user, reminderHeader, blocks))
+				blocks, count = nil, 0
 			}
 
-			msg.WriteString(prDetails)
+			if c.ReminderButtons {
+				channelID, _ := data.SwitchURLAndID(ctx, prURL)
+				isAuthor, _ := data.LoadParticipantRoles(ctx, c.TemporalOpts, prURL, email)
+				blocks = append(blocks, reminderBlocks(prURL, prDetails, channelID, isAuthor)...)
+			} else {
+				blocks = append(blocks, markdownSection(strings.TrimPrefix(prDetails, "\n\n")))
+			}
+			count++
 		}
 
-		msg.WriteString("\n\n:information_source: Slack command tips:")
-		msg.WriteString("\n  •   `/revchat status` - updated report at any time")
-		msg.WriteString("\n  •   `/revchat reminder <time in 12h or 24h format>` - change time or timezone")
-		msg.WriteString("\n  •   `/revchat who` / `[not] my turn` / `[un]freeze` - only in PR channels")
-		msg.WriteString("\n  •   `/revchat explain` - who needs to approve each file, and have they?")
+		var tips strings.Builder
+		tips.WriteString(":information_source: Slack command tips:")
+		tips.WriteString("\n  •   `/revchat status` - updated report at any time")
+		tips.WriteString("\n  •   `/revchat reminder <time in 12h or 24h format>` - change time or timezone")
+		tips.WriteString("\n  •   `/revchat who` / `[not] my turn` / `[un]freeze` - only in PR channels")
+		tips.WriteString("\n  •   `/revchat explain` - who needs to approve each file, and have they?")
+		blocks = append(blocks, markdownSection(tips.String()))
 
-		aggregatedErr = errors.Join(aggregatedErr, activities.PostMessage(ctx, user, msg.String()))
+		aggregatedErr = errors.Join(aggregatedErr, activities.PostMessageWithBlocks(ctx, user, reminderHeader, blocks))
 	}
 
 	return aggregatedErr
 }
 
+// reminderBlocks renders a single PR in a scheduled reminder as a Block Kit section with action
+// buttons, which are handled by [Config.BlockActionsWorkflow]. Authors can't approve their own PRs.
+func reminderBlocks(prURL, prDetails, channelID string, isAuthor bool) []map[string]any {
+	var buttons []map[string]any
+	if !isAuthor {
+		approve := reminderButton(reminderActionApprove, "Approve", prURL)
+		approve["style"] = "primary"
+		buttons = append(buttons, approve)
+	}
+	buttons = append(buttons,
+		reminderButton(reminderActionNotMyTurn, "Not my turn", prURL),
+		reminderButton(reminderActionSnooze, "Snooze 1 day", prURL),
+	)
+
+	if channelID != "" {
+		b := reminderButton(reminderActionOpenChannel, "Open channel", prURL)
+		b["url"] = "https://slack.com/app_redirect?channel=" + channelID
+		buttons = append(buttons, b)
+	}
+
+	return []map[string]any{
+		markdownSection(strings.TrimPrefix(prDetails, "\n\n")),
+		{
+			"type":     "actions",
+			"block_id": prURL,
+			"elements": buttons,
+		},
+	}
+}
+
+func reminderButton(actionID, text, value string) map[string]any {
+	return map[string]any{
+		"type":      "button",
+		"action_id": actionID,
+		"text": map[string]any{
+			"type": "plain_text",
+			"text": text,
+		},
+		"value": value,
+	}
+}
+
+func markdownSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{
+			"type": "mrkdwn",
+			"text": text,
+		},
+	}
+}
+
 func reminderTimes(ctx workflow.Context, startTime time.Time, userID, reminder string) (parsed, now time.Time, err error) {
 	// Read and parse the daily reminder time for each user.
 	kitchenTime, tz, found := strings.Cut(reminder, " ")
@@ -18,3 +18,51 @@ func TestReminderTimes(t *testing.T) {
 		t.Errorf("reminderTimes() = %v, want 08:00:00", gotTime)
 	}
 }
+
+func TestReminderBlocks(t *testing.T) {
+	prURL := "https://github.com/owner/repo/pull/1"
+	blocks := reminderBlocks(prURL, "\n\n<url|*title*>", "C123", false)
+	if len(blocks) != 2 {
+		t.Fatalf("reminderBlocks() = %d blocks, want 2", len(blocks))
+	}
+
+	text := blocks[0]["text"].(map[string]any)["text"]
+	if text != "<url|*title*>" {
+		t.Errorf("reminderBlocks() section text = %q, want %q", text, "<url|*title*>")
+	}
+
+	buttons := blocks[1]["elements"].([]map[string]any)
+	if len(buttons) != 4 {
+		t.Fatalf("reminderBlocks() = %d buttons, want 4", len(buttons))
+	}
+	if got := buttons[0]["action_id"]; got != reminderActionApprove {
+		t.Errorf("reminderBlocks() first button = %q, want %q", got, reminderActionApprove)
+	}
+	if got := buttons[3]["url"]; got != "https://slack.com/app_redirect?channel=C123" {
+		t.Errorf("reminderBlocks() channel URL = %q", got)
+	}
+
+	// Without a Slack channel.
+	buttons = reminderBlocks(prURL, "", "", false)[1]["elements"].([]map[string]any)
+	if len(buttons) != 3 {
+		t.Fatalf("reminderBlocks() = %d buttons, want 3", len(buttons))
+	}
+
+	// The PR's author can't approve it.
+	buttons = reminderBlocks(prURL, "", "C123", true)[1]["elements"].([]map[string]any)
+	if len(buttons) != 3 || buttons[0]["action_id"] == reminderActionApprove {
+		t.Fatalf("reminderBlocks() = %v, want 3 buttons without %q", buttons, reminderActionApprove)
+	}
+
+	// Replace the buttons with a result.
+	blocks = replaceActionsBlock(append(blocks, reminderBlocks("other", "", "", false)...), prURL, "done")
+	if len(blocks) != 4 {
+		t.Fatalf("replaceActionsBlock() = %d blocks, want 4", len(blocks))
+	}
+	if blocks[1]["type"] != "context" || blocks[1]["block_id"] != prURL {
+		t.Errorf("replaceActionsBlock() = %v, want context block", blocks[1])
+	}
+	if blocks[3]["type"] != "actions" {
+		t.Errorf("replaceActionsBlock() = %v, want unchanged actions block", blocks[3])
+	}
+}
@@ -11,12 +11,29 @@ import (
 	"github.com/tzrikka/revchat/pkg/slack/commands"
 )
 
-var userCommandsPattern = regexp.MustCompile(`^((un)?follow|invite|nudge|ping|poke|stat(e|us)?([\s-](auth(ors|or)?|rev(iew(ers|er|s)?)?))?)`)
+var userCommandsPattern = regexp.MustCompile(`^((un)?follow|invite|nudge|ping|poke|reviewers?\s+(add|remove)|stat(e|us)?([\s-](auth(ors|or)?|rev(iew(ers|er|s)?)?))?)`)
 
 // SlashCommandWorkflow routes user command events to their respective handlers in the [commands] package:
 //   - https://docs.slack.dev/apis/events-api/using-socket-mode#command
 //   - https://docs.slack.dev/interactivity/implementing-slash-commands#app_command_handling
 func (c *Config) SlashCommandWorkflow(ctx workflow.Context, event commands.SlashCommandEvent) error {
+	// Commands with free-text arguments, which must not be lowercased.
+	cmd, text, _ := strings.Cut(strings.TrimSpace(event.Text), " ")
+	switch strings.ToLower(cmd) {
+	case "task":
+		return commands.Task(ctx, event, strings.TrimSpace(text), c.BitbucketCreateTasks, c.PinChecklists)
+	case "merge":
+		return commands.Merge(ctx, event, text, c.GitHubListReviews, c.GitHubDeleteBranches)
+	case "decline":
+		return commands.Decline(ctx, event, strings.TrimSpace(text))
+	case "flaky":
+		return commands.Flaky(ctx, event, strings.TrimSpace(text))
+	case "snooze":
+		return commands.Snooze(ctx, c.TemporalOpts, event, text)
+	case "away":
+		return commands.Away(ctx, c.TemporalOpts, event, text)
+	}
+
 	// Commands without any arguments.
 	event.Text = strings.ToLower(event.Text)
 	switch event.Text {
@@ -29,11 +46,15 @@ func (c *Config) SlashCommandWorkflow(ctx workflow.Context, event commands.Slash
 		return c.OptOutSlashCommand(ctx, event)
 
 	case "clean":
-		return commands.Clean(ctx, event)
+		return commands.Clean(ctx, event, c.GitHubReviewerRequests)
 	case "explain":
-		return commands.Explain(ctx, event)
+		return commands.Explain(ctx, event, c.GitHubListReviews)
+	case "suggest":
+		return commands.Suggest(ctx, c.TemporalOpts, event, c.ReviewersMaxLoad)
 	case "stat", "state", "status":
 		return commands.SelfStatus(ctx, c.TemporalOpts, event, c.AlertsChannel, c.ReportDrafts)
+	case "back":
+		return commands.Back(ctx, event)
 
 	case "who", "whose", "whose turn":
 		return commands.WhoseTurn(ctx, c.TemporalOpts, event)
@@ -41,6 +62,10 @@ func (c *Config) SlashCommandWorkflow(ctx workflow.Context, event commands.Slash
 		return commands.MyTurn(ctx, c.TemporalOpts, event)
 	case "not my turn":
 		return commands.NotMyTurn(ctx, c.TemporalOpts, event)
+	case "unsnooze":
+		return commands.Unsnooze(ctx, c.TemporalOpts, event)
+	case "snoozed":
+		return commands.Snoozed(ctx, c.TemporalOpts, event)
 	case "freeze", "freeze turn", "freeze turns":
 		return commands.FreezeTurns(ctx, c.TemporalOpts, event)
 	case "unfreeze", "unfreeze turn", "unfreeze turns":
@@ -49,7 +74,7 @@ func (c *Config) SlashCommandWorkflow(ctx workflow.Context, event commands.Slash
 	case "approve", "lgtm", "+1":
 		return commands.Approve(ctx, event)
 	case "unapprove", "-1":
-		return commands.Unapprove(ctx, event)
+		return commands.Unapprove(ctx, event, c.GitHubListReviews)
 
 	case "clean-pr-data":
 		return commands.CleanPRData(ctx, event, c.AlertsChannel)
@@ -67,6 +92,12 @@ func (c *Config) SlashCommandWorkflow(ctx workflow.Context, event commands.Slash
 		case "nudge", "ping", "poke":
 			return commands.Nudge(ctx, c.TemporalOpts, event, c.ThrippyHTTPAddress)
 		default:
+			switch cmd[3] {
+			case "add":
+				return commands.AddReviewers(ctx, event, c.GitHubReviewerRequests)
+			case "remove":
+				return commands.RemoveReviewers(ctx, event, c.GitHubReviewerRequests)
+			}
 			user, _, _ := data.SelectUserBySlackID(ctx, event.UserID)
 			return commands.StatusOfOthers(ctx, c.TemporalOpts, event, c.ReportDrafts, user.ThrippyLink, c.AlertsChannel)
 		}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/tzrikka/revchat/internal/logger"
 	"github.com/tzrikka/revchat/internal/otel"
+	"github.com/tzrikka/revchat/pkg/config"
 	"github.com/tzrikka/revchat/pkg/slack/commands"
 )
 
@@ -24,8 +25,23 @@ type Config struct {
 	NudgeChannels []string
 	NudgeGroups   []string
 	ReportDrafts  bool
+	PinChecklists bool
 
-	BitbucketWorkspace string
+	ReminderButtons bool
+
+	ReviewersMaxLoad     int
+	ReviewersLeftChannel string
+	ReviewersOnJoinRepos map[string]bool
+
+	BitbucketWorkspace      string
+	BitbucketReactions      string
+	BitbucketCommitComments bool
+	BitbucketCreateTasks    bool
+	LabelRules              map[string]string
+	GitHubListReviews       bool
+	GitHubDeleteBranches    bool
+	GitHubReviewerRequests  bool
+	GitHubReactions         bool
 
 	TemporalOpts client.Options
 
@@ -47,8 +63,23 @@ func newConfig(cmd *cli.Command, temporalOpts client.Options) *Config {
 		NudgeChannels: cmd.StringSlice("slack-nudge-channels"),
 		NudgeGroups:   cmd.StringSlice("slack-nudge-groups"),
 		ReportDrafts:  cmd.Bool("slack-report-drafts"),
+		PinChecklists: cmd.Bool("slack-pin-checklists"),
+
+		ReminderButtons: cmd.Bool("slack-reminder-buttons"),
 
-		BitbucketWorkspace: cmd.String("bitbucket-workspace"),
+		ReviewersMaxLoad:     cmd.Int("reviewers-max-load"),
+		ReviewersLeftChannel: cmd.String("reviewers-left-channel"),
+		ReviewersOnJoinRepos: config.RepoSet(cmd.StringSlice("reviewers-add-on-join-repos")),
+
+		BitbucketWorkspace:      cmd.String("bitbucket-workspace"),
+		BitbucketReactions:      cmd.String("bitbucket-reactions"),
+		BitbucketCommitComments: cmd.Bool("bitbucket-commit-comments"),
+		BitbucketCreateTasks:    cmd.Bool("bitbucket-create-tasks"),
+		LabelRules:              config.LabelRules(cmd.StringSlice("github-label-rules")),
+		GitHubListReviews:       cmd.Bool("github-list-reviews"),
+		GitHubDeleteBranches:    cmd.Bool("github-delete-branches"),
+		GitHubReviewerRequests:  cmd.Bool("github-reviewer-requests"),
+		GitHubReactions:         cmd.Bool("github-reactions"),
 
 		TemporalOpts: temporalOpts,
 
@@ -80,6 +111,7 @@ var Signals = []string{
 	"slack.events.reaction_removed",
 
 	"slack.events.slash_command",
+	"slack.events.block_actions",
 }
 
 // Schedules is a list of workflow names that RevChat runs periodically via
@@ -99,9 +131,10 @@ func RegisterWorkflows(ctx context.Context, cmd *cli.Command, temporalOpts clien
 	w.RegisterWorkflowWithOptions(c.MemberJoinedWorkflow, workflow.RegisterOptions{Name: Signals[3]})
 	w.RegisterWorkflowWithOptions(c.MemberLeftWorkflow, workflow.RegisterOptions{Name: Signals[4]})
 	w.RegisterWorkflowWithOptions(c.MessageWorkflow, workflow.RegisterOptions{Name: Signals[5]})
-	w.RegisterWorkflowWithOptions(ReactionAddedWorkflow, workflow.RegisterOptions{Name: Signals[6]})
-	w.RegisterWorkflowWithOptions(ReactionRemovedWorkflow, workflow.RegisterOptions{Name: Signals[7]})
+	w.RegisterWorkflowWithOptions(c.ReactionAddedWorkflow, workflow.RegisterOptions{Name: Signals[6]})
+	w.RegisterWorkflowWithOptions(c.ReactionRemovedWorkflow, workflow.RegisterOptions{Name: Signals[7]})
 	w.RegisterWorkflowWithOptions(c.SlashCommandWorkflow, workflow.RegisterOptions{Name: Signals[8]})
+	w.RegisterWorkflowWithOptions(c.BlockActionsWorkflow, workflow.RegisterOptions{Name: Signals[9]})
 
 	// Special case: scheduled workflows.
 	w.RegisterWorkflowWithOptions(c.RemindersWorkflow, workflow.RegisterOptions{Name: Schedules[0]})
@@ -118,6 +151,7 @@ func RegisterSignals(ctx workflow.Context, sel workflow.Selector) {
 	addReceive[reactionEventWrapper](ctx, sel, Signals[6])
 	addReceive[reactionEventWrapper](ctx, sel, Signals[7])
 	addReceive[commands.SlashCommandEvent](ctx, sel, Signals[8])
+	addReceive[BlockActionsEvent](ctx, sel, Signals[9])
 }
 
 func addReceive[T any](ctx workflow.Context, sel workflow.Selector, signalName string) {
@@ -150,6 +184,7 @@ func DrainSignals(ctx workflow.Context) bool {
 	totalEvents += receiveAsync[reactionEventWrapper](ctx, Signals[6])
 	totalEvents += receiveAsync[reactionEventWrapper](ctx, Signals[7])
 	totalEvents += receiveAsync[commands.SlashCommandEvent](ctx, Signals[8])
+	totalEvents += receiveAsync[BlockActionsEvent](ctx, Signals[9])
 	return totalEvents > 0
 }
 
@@ -210,6 +245,10 @@ func childWorkflowID[T any](ctx workflow.Context, signal string, payload *T) str
 			}
 			id = fmt.Sprintf("%s%s_%s", cmd, event.ChannelID, event.UserID)
 		}
+	case Signals[9]:
+		if event, ok := any(payload).(*BlockActionsEvent); ok {
+			id = fmt.Sprintf("%s_%s", event.Container.ChannelID, event.Container.MessageTS)
+		}
 	}
 
 	if id == "" {
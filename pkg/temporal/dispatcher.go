@@ -28,6 +28,8 @@ const (
 )
 
 type Config struct {
+	bitbucketDataCenter bool
+
 	dispatcherWorkflowID string
 	dispatcherRunID      string
 
@@ -35,9 +37,12 @@ type Config struct {
 	shutdownDone   chan<- any
 }
 
-func workflowOptions(taskQueue string) client.StartWorkflowOptions {
+func (c *Config) workflowOptions(taskQueue string) client.StartWorkflowOptions {
 	// https://docs.temporal.io/develop/go/observability#visibility
 	signals := slices.Concat(bitbucketwf.PullRequestSignals, bitbucketwf.RepositorySignals, githubwf.Signals, slackwf.Signals)
+	if c.bitbucketDataCenter {
+		signals = append(signals, bitbucketwf.DataCenterSignals...)
+	}
 	attrs := temporal.NewSearchAttributes(temporal.NewSearchAttributeKeyKeywordList(SearchAttribute).ValueSet(signals))
 
 	return client.StartWorkflowOptions{
@@ -67,6 +72,9 @@ func (c *Config) EventDispatcherWorkflow(ctx workflow.Context) error {
 
 	bitbucketwf.RegisterPullRequestSignals(ctx, selector)
 	bitbucketwf.RegisterRepositorySignals(ctx, selector)
+	if c.bitbucketDataCenter {
+		bitbucketwf.RegisterDataCenterSignals(ctx, selector)
+	}
 	githubwf.RegisterSignals(ctx, selector)
 	slackwf.RegisterSignals(ctx, selector)
 
@@ -111,7 +119,7 @@ func (c *Config) prepareForReset(ctx workflow.Context, info *workflow.Info) {
 	}
 
 	for cyclesSinceLastSignal := 0; cyclesSinceLastSignal < 3; cyclesSinceLastSignal++ {
-		if drainCycle(ctx) {
+		if c.drainCycle(ctx) {
 			cyclesSinceLastSignal = -1 // Will become 0 after loop increment.
 		}
 	}
@@ -129,11 +137,12 @@ func (c *Config) prepareForReset(ctx workflow.Context, info *workflow.Info) {
 }
 
 // drainCycle processes each event source and returns true if any signals were found.
-func drainCycle(ctx workflow.Context) bool {
+func (c *Config) drainCycle(ctx workflow.Context) bool {
 	bitbucketPRSignalsFound := bitbucketwf.DrainPullRequestSignals(ctx)
 	bitbucketRepoSignalsFound := bitbucketwf.DrainRepositorySignals(ctx)
+	bitbucketDCSignalsFound := c.bitbucketDataCenter && bitbucketwf.DrainDataCenterSignals(ctx)
 	githubSignalsFound := githubwf.DrainSignals(ctx)
 	slackSignalsFound := slackwf.DrainSignals(ctx)
 
-	return bitbucketPRSignalsFound || bitbucketRepoSignalsFound || githubSignalsFound || slackSignalsFound
+	return bitbucketPRSignalsFound || bitbucketRepoSignalsFound || bitbucketDCSignalsFound || githubSignalsFound || slackSignalsFound
 }
@@ -59,7 +59,7 @@ func Run(ctx context.Context, cmd *cli.Command, bi *debug.BuildInfo) error {
 	githubwf.RegisterWorkflows(cmd, dialOpts, w)
 	slackwf.RegisterWorkflows(ctx, cmd, dialOpts, w)
 
-	bitbucketwf.CreateSchedule(ctx, cli, taskQueue)
+	bitbucketwf.DeleteLegacySchedules(ctx, cli)
 	slackwf.CreateSchedule(ctx, cli, taskQueue)
 
 	temporal.ActivityOptions = &workflow.ActivityOptions{
@@ -70,9 +70,9 @@ func Run(ctx context.Context, cmd *cli.Command, bi *debug.BuildInfo) error {
 		},
 	}
 
-	cfg := new(Config)
+	cfg := &Config{bitbucketDataCenter: cmd.Bool("bitbucket-datacenter")}
 	w.RegisterWorkflowWithOptions(cfg.EventDispatcherWorkflow, workflow.RegisterOptions{Name: EventDispatcher})
-	run, err := cli.ExecuteWorkflow(ctx, workflowOptions(taskQueue), EventDispatcher)
+	run, err := cli.ExecuteWorkflow(ctx, cfg.workflowOptions(taskQueue), EventDispatcher)
 	if err != nil {
 		return fmt.Errorf("failed to start event dispatcher workflow: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tzrikka/revchat/internal/logger"
+	"github.com/tzrikka/revchat/pkg/bitbucket/datacenter"
 	"github.com/tzrikka/revchat/pkg/data"
 	"github.com/tzrikka/timpani-api/pkg/bitbucket"
 	"github.com/tzrikka/timpani-api/pkg/jira"
@@ -60,6 +61,9 @@ func BitbucketIDToEmail(ctx workflow.Context, accountID, accountType string) str
 		}
 		return "" // Unknown user, so no point to look-up in Jira.
 	}
+	if email, ok := strings.CutPrefix(accountID, datacenter.AccountIDPrefix); ok {
+		return email // Bitbucket Data Center users are identified by their email addresses.
+	}
 
 	if user := data.SelectUserByBitbucketID(ctx, accountID); user.Email != "" {
 		return user.Email
@@ -136,6 +140,9 @@ func BitbucketIDToSlackRef(ctx workflow.Context, accountID, displayName string)
 	if accountID == "" {
 		return "A bot"
 	}
+	if email, ok := strings.CutPrefix(accountID, datacenter.AccountIDPrefix); ok {
+		return email // Bitbucket Data Center users can't be looked-up in the Bitbucket Cloud API.
+	}
 
 	// Fallback 2: display name from Bitbucket API.
 	apiUser, err := bitbucket.UsersGetByAccountID(ctx, accountID)
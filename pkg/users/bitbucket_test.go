@@ -40,6 +40,14 @@ func TestBitbucketActorToEmail(t *testing.T) {
 			},
 			want: "bot",
 		},
+		{
+			name: "data_center_user",
+			actor: bitbucket.User{
+				Type:      "user",
+				AccountID: "dc:alice@example.com",
+			},
+			want: "alice@example.com",
+		},
 	}
 
 	for _, tt := range tests {